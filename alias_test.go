@@ -0,0 +1,131 @@
+package weightedrand
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestNewAliasChooser(t *testing.T) {
+	tests := []struct {
+		name    string
+		cs      []Choice[rune, uint64]
+		wantErr error
+	}{
+		{name: "zero choices", wantErr: errNoValidChoices},
+		{name: "zero weights", cs: []Choice[rune, uint64]{{'a', 0}}, wantErr: errNoValidChoices},
+		{name: "weight overflow", cs: []Choice[rune, uint64]{{'a', math.MaxUint64}}, wantErr: errWeightOverflow},
+		{name: "alias overflow", cs: []Choice[rune, uint64]{{'a', 1 << 62}, {'b', 1 << 60}, {'c', 1 << 60}, {'d', 1 << 60}, {'e', 1}}, wantErr: errAliasOverflow},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewAliasChooser(tt.cs...); err != tt.wantErr {
+				t.Errorf("NewAliasChooser() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// Every random value maps to a choice, with exactly n·weight values mapping to
+// each, so the distribution is exact up to the bias of the generator.
+func TestAliasChooser_exact(t *testing.T) {
+	for _, weights := range [][]int{
+		{1},
+		{1, 1},
+		{1, 2, 3, 4},
+		{0, 7, -1, 1, 1, 1},
+		{100, 1, 1, 1, 1, 1, 1, 1, 1, 1},
+		{3, 3, 3, 5, 8, 13, 21},
+	} {
+		t.Run(fmt.Sprint(weights), func(t *testing.T) {
+			choices := make([]Choice[int, int], len(weights))
+			for i, w := range weights {
+				choices[i] = NewChoice(i, w)
+			}
+			c, err := NewAliasChooser(choices...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			counts := make(map[int]uint64)
+			for r := uint64(0); r < c.bound; r++ {
+				counts[c.data[c.index(r)].Item]++
+			}
+			n := uint64(len(c.data))
+			for i, w := range weights {
+				want := uint64(0)
+				if w > 0 {
+					want = n * uint64(w)
+				}
+				if counts[i] != want {
+					t.Errorf("choice %d selected by %d values, want %d", i, counts[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestAliasChooser_Pick(t *testing.T) {
+	choices := mockFrequencyChoices(t, testChoices)
+	sortChoices(choices) // as expected by verifyFrequencyCounts
+	chooser, err := NewAliasChooser(choices...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	counts := make(map[int]int)
+	for i := 0; i < testIterations; i++ {
+		counts[chooser.Pick()]++
+	}
+	verifyFrequencyCounts(t, counts, choices)
+}
+
+func BenchmarkAliasChooser_Pick(b *testing.B) {
+	for n := BMMinChoices; n <= BMMaxChoices; n *= 10 {
+		b.Run(fmt.Sprintf("size=%s", fmt1eN(n)), func(b *testing.B) {
+			chooser, err := NewAliasChooser(mockChoices(n)...)
+			if err != nil {
+				b.Fatal(err)
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_ = chooser.Pick()
+			}
+		})
+	}
+}
+
+// BenchmarkPickTail reports the median and 99.9th percentile latency of picks,
+// timed in batches to amortize the overhead of reading the clock.
+func BenchmarkPickTail(b *testing.B) {
+	const size, batch = 1_000_000, 16
+	chooser, err := NewChooser(mockChoices(size)...)
+	if err != nil {
+		b.Fatal(err)
+	}
+	alias, err := NewAliasChooser(mockChoices(size)...)
+	if err != nil {
+		b.Fatal(err)
+	}
+	for _, bm := range []struct {
+		name string
+		pick func() rune
+	}{
+		{"Chooser", chooser.Pick},
+		{"AliasChooser", alias.Pick},
+	} {
+		b.Run(fmt.Sprintf("size=%s/%s", fmt1eN(size), bm.name), func(b *testing.B) {
+			samples := make([]time.Duration, 0, b.N/batch+1)
+			for i := 0; i < b.N; i += batch {
+				start := time.Now()
+				for j := 0; j < batch; j++ {
+					_ = bm.pick()
+				}
+				samples = append(samples, time.Since(start)/batch)
+			}
+			sort.Slice(samples, func(i, j int) bool { return samples[i] < samples[j] })
+			b.ReportMetric(float64(samples[len(samples)/2]), "p50-ns/op")
+			b.ReportMetric(float64(samples[len(samples)*999/1000]), "p99.9-ns/op")
+		})
+	}
+}
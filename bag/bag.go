@@ -0,0 +1,104 @@
+// Package bag implements a shuffle bag, or "Tetris-style" randomizer: a
+// weighted random picker which deals every item in proportion to its weight
+// within each cycle, so that picks are weighted but free of long streaks and
+// droughts.
+package bag
+
+import (
+	"errors"
+	"math/rand"
+	"sync"
+
+	"github.com/mroth/weightedrand/v2"
+	"github.com/mroth/weightedrand/v2/internal/constraints"
+)
+
+// MaxCycle is the largest number of picks per cycle supported by a Bag, after
+// its weights are reduced by their greatest common divisor.
+const MaxCycle = 1 << 24
+
+// Possible errors returned by New.
+var (
+	ErrNoValidChoices = errors.New("bag: zero choices with weight >= 1")
+	ErrCycleLength    = errors.New("bag: sum of reduced weights exceeds MaxCycle")
+)
+
+// A Bag deals items in random order, each as many times per cycle as its
+// weight, then refills itself for the next cycle. It is safe for concurrent
+// usage.
+type Bag[T any] struct {
+	mu    sync.Mutex
+	items []T // one entry per pick of the cycle, items[:next] already dealt
+	next  int
+}
+
+// New initializes a Bag from choices, whose weights give the number of times
+// each item is dealt per cycle. Weights are first divided by their greatest
+// common divisor, so weights of 50, 30 and 20 result in cycles of 10 picks
+// rather than 100. Choices with a weight < 1 are never dealt.
+func New[T any, W constraints.Integer](choices ...weightedrand.Choice[T, W]) (*Bag[T], error) {
+	var g uint64
+	for _, c := range choices {
+		if c.Weight >= 1 {
+			g = gcd(g, uint64(c.Weight))
+		}
+	}
+	if g == 0 {
+		return nil, ErrNoValidChoices
+	}
+
+	var size uint64
+	for _, c := range choices {
+		if c.Weight >= 1 {
+			size += uint64(c.Weight) / g
+			if size > MaxCycle {
+				return nil, ErrCycleLength
+			}
+		}
+	}
+	b := &Bag[T]{items: make([]T, 0, size)}
+	for _, c := range choices {
+		for i := W(0); c.Weight >= 1 && uint64(i) < uint64(c.Weight)/g; i++ {
+			b.items = append(b.items, c.Item)
+		}
+	}
+	return b, nil
+}
+
+// Pick deals the next item from the Bag, starting a new cycle if the current
+// one is exhausted.
+//
+// Utilizes global rand as the source of randomness.
+func (b *Bag[T]) Pick() T {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.next == len(b.items) {
+		b.next = 0
+	}
+	// Shuffle incrementally, swapping a random undealt item into place.
+	j := b.next + rand.Intn(len(b.items)-b.next)
+	b.items[b.next], b.items[j] = b.items[j], b.items[b.next]
+	item := b.items[b.next]
+	b.next++
+	return item
+}
+
+// Remaining returns the number of picks left before the current cycle is
+// exhausted and the Bag refills.
+func (b *Bag[T]) Remaining() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.items) - b.next
+}
+
+// Cycle returns the number of picks in each cycle of the Bag.
+func (b *Bag[T]) Cycle() int {
+	return len(b.items)
+}
+
+func gcd(a, b uint64) uint64 {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
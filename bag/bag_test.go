@@ -0,0 +1,91 @@
+package bag
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/mroth/weightedrand/v2"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name      string
+		weights   []int
+		wantCycle int
+		wantErr   error
+	}{
+		{name: "uniform", weights: []int{1, 1, 1, 1, 1, 1, 1}, wantCycle: 7},
+		{name: "reduced by gcd", weights: []int{50, 30, 20}, wantCycle: 10},
+		{name: "ignores zero and negative", weights: []int{4, 0, -2, 6}, wantCycle: 5},
+		{name: "no positive weights", weights: []int{0, -1}, wantErr: ErrNoValidChoices},
+		{name: "cycle too long", weights: []int{MaxCycle, 1}, wantErr: ErrCycleLength},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			choices := make([]weightedrand.Choice[int, int], len(tt.weights))
+			for i, w := range tt.weights {
+				choices[i] = weightedrand.NewChoice(i, w)
+			}
+			b, err := New(choices...)
+			if err != tt.wantErr {
+				t.Fatalf("New() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && b.Cycle() != tt.wantCycle {
+				t.Errorf("Cycle() = %d, want %d", b.Cycle(), tt.wantCycle)
+			}
+		})
+	}
+
+	if _, err := New(weightedrand.NewChoice("a", uint64(math.MaxUint64))); err != nil {
+		t.Errorf("New() with a single huge weight error = %v", err)
+	}
+}
+
+func TestBag_Pick(t *testing.T) {
+	b, err := New(
+		weightedrand.NewChoice("a", 3),
+		weightedrand.NewChoice("b", 2),
+		weightedrand.NewChoice("c", 1),
+		weightedrand.NewChoice("never", 0),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	orders := make(map[string]bool)
+	for cycle := 0; cycle < 100; cycle++ {
+		counts := make(map[string]int)
+		var order string
+		for i := 0; i < 6; i++ {
+			item := b.Pick()
+			counts[item]++
+			order += item
+		}
+		if b.Remaining() != 0 {
+			t.Fatalf("Remaining() = %d at end of cycle, want 0", b.Remaining())
+		}
+		if counts["a"] != 3 || counts["b"] != 2 || counts["c"] != 1 {
+			t.Fatalf("cycle %d dealt %v, want a:3 b:2 c:1", cycle, counts)
+		}
+		orders[order] = true
+	}
+	// There are 60 distinct orders of a cycle, which should be well covered.
+	if len(orders) < 30 {
+		t.Errorf("only %d distinct cycle orders in 100 cycles", len(orders))
+	}
+}
+
+func ExampleBag() {
+	pieces := []weightedrand.Choice[string, int]{
+		{Item: "I", Weight: 1}, {Item: "O", Weight: 1}, {Item: "T", Weight: 1},
+		{Item: "S", Weight: 1}, {Item: "Z", Weight: 1}, {Item: "J", Weight: 1},
+		{Item: "L", Weight: 1},
+	}
+	b, _ := New(pieces...)
+	seen := make(map[string]bool)
+	for i := 0; i < b.Cycle(); i++ {
+		seen[b.Pick()] = true
+	}
+	fmt.Println(len(seen))
+	// Output: 7
+}
@@ -0,0 +1,104 @@
+// Package balance provides a weighted load balancer which excludes unhealthy
+// items from selection, picking among the remaining items in proportion to
+// their weights.
+package balance
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/mroth/weightedrand/v2"
+	"github.com/mroth/weightedrand/v2/internal/constraints"
+)
+
+// A Balancer picks among weighted items, excluding those marked down. It is
+// safe for concurrent usage. Pick is lock-free, while marking items up or down
+// rebuilds the underlying Chooser in O(n log n), so it suits health changes
+// which are infrequent relative to picks.
+type Balancer[T comparable, W constraints.Integer] struct {
+	current atomic.Value // *weightedrand.Chooser[T, W], or nil if none are up
+
+	mu      sync.Mutex // serializes rebuilds, guards fields below
+	choices []weightedrand.Choice[T, W]
+	index   map[T]int
+	down    []bool
+}
+
+// New initializes a Balancer over choices, all of which start up. Choices with
+// duplicate items are resolved in favor of the last. Since at least one item
+// must be pickable initially, an error is returned if no choices have a
+// weight >= 1.
+func New[T comparable, W constraints.Integer](choices ...weightedrand.Choice[T, W]) (*Balancer[T, W], error) {
+	b := &Balancer[T, W]{index: make(map[T]int, len(choices))}
+	for _, c := range choices {
+		if i, ok := b.index[c.Item]; ok {
+			b.choices[i] = c
+			continue
+		}
+		b.index[c.Item] = len(b.choices)
+		b.choices = append(b.choices, c)
+	}
+	b.down = make([]bool, len(b.choices))
+	c, err := weightedrand.NewChooser(b.upChoices()...)
+	if err != nil {
+		return nil, err
+	}
+	b.current.Store(c)
+	return b, nil
+}
+
+// Pick returns a weighted random item from among those which are up, or false
+// if no items with a weight >= 1 are up.
+func (b *Balancer[T, W]) Pick() (T, bool) {
+	c, _ := b.current.Load().(*weightedrand.Chooser[T, W])
+	if c == nil {
+		var zero T
+		return zero, false
+	}
+	return c.Pick(), true
+}
+
+// MarkDown excludes item from selection until it is marked up again, with the
+// weights of the remaining items renormalized. Unknown items are ignored.
+func (b *Balancer[T, W]) MarkDown(item T) { b.mark(item, true) }
+
+// MarkUp returns item to selection. Unknown items are ignored.
+func (b *Balancer[T, W]) MarkUp(item T) { b.mark(item, false) }
+
+// IsUp reports whether item is known and up.
+func (b *Balancer[T, W]) IsUp(item T) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	i, ok := b.index[item]
+	return ok && !b.down[i]
+}
+
+func (b *Balancer[T, W]) mark(item T, down bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	i, ok := b.index[item]
+	if !ok || b.down[i] == down {
+		return
+	}
+	b.down[i] = down
+	c, err := weightedrand.NewChooser(b.upChoices()...)
+	if err != nil {
+		// The weights of up items can only sum to less than those which
+		// succeeded in New, so the only possible error is that none remain.
+		c = nil
+	}
+	b.current.Store(c)
+}
+
+// upChoices returns a new slice of the choices which are up, since Chooser
+// takes ownership of the slice it is constructed from.
+func (b *Balancer[T, W]) upChoices() []weightedrand.Choice[T, W] {
+	up := make([]weightedrand.Choice[T, W], 0, len(b.choices))
+	for i, c := range b.choices {
+		if !b.down[i] {
+			up = append(up, c)
+		}
+	}
+	return up
+}
@@ -0,0 +1,90 @@
+package balance
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/mroth/weightedrand/v2"
+)
+
+func TestNew(t *testing.T) {
+	if _, err := New[string, int](); err == nil {
+		t.Error("New() with no choices should return an error")
+	}
+	b, err := New(
+		weightedrand.NewChoice("a", 1),
+		weightedrand.NewChoice("b", 5),
+		weightedrand.NewChoice("a", 0),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// duplicate resolved in favor of the last, so a can never be picked
+	for i := 0; i < 100; i++ {
+		if got, _ := b.Pick(); got != "b" {
+			t.Fatalf("Pick() = %q, want b", got)
+		}
+	}
+}
+
+func TestBalancer_MarkDown(t *testing.T) {
+	b, _ := New(
+		weightedrand.NewChoice("a", 1),
+		weightedrand.NewChoice("b", 1),
+		weightedrand.NewChoice("c", 2),
+	)
+
+	b.MarkDown("c")
+	b.MarkDown("unknown")
+	if b.IsUp("c") || !b.IsUp("a") || b.IsUp("unknown") {
+		t.Fatal("unexpected IsUp state after MarkDown")
+	}
+	counts := make(map[string]int)
+	for i := 0; i < 10000; i++ {
+		item, ok := b.Pick()
+		if !ok {
+			t.Fatal("Pick() returned false with items up")
+		}
+		counts[item]++
+	}
+	if counts["c"] != 0 {
+		t.Errorf("picked down item %d times", counts["c"])
+	}
+	if counts["a"] < 4500 || counts["b"] < 4500 {
+		t.Errorf("weights not renormalized among up items: %v", counts)
+	}
+
+	b.MarkDown("a")
+	b.MarkDown("b")
+	if item, ok := b.Pick(); ok {
+		t.Errorf("Pick() = %q, true with all items down", item)
+	}
+
+	b.MarkUp("c")
+	if item, ok := b.Pick(); !ok || item != "c" {
+		t.Errorf("Pick() = %q, %v, want c, true", item, ok)
+	}
+}
+
+func TestBalancer_concurrent(t *testing.T) {
+	b, _ := New(weightedrand.NewChoice("a", 1), weightedrand.NewChoice("b", 1))
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			b.MarkDown("a")
+			b.MarkUp("a")
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			if _, ok := b.Pick(); !ok {
+				t.Error("Pick() returned false with b always up")
+				return
+			}
+		}
+	}()
+	wg.Wait()
+}
@@ -0,0 +1,129 @@
+package bandit
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+
+	"github.com/mroth/weightedrand/v2"
+	"github.com/mroth/weightedrand/v2/internal/constraints"
+)
+
+// ErrLearningRate is returned by NewAdaptive for a learning rate outside
+// (0, 1].
+var ErrLearningRate = errors.New("bandit: learning rate must be in (0, 1]")
+
+// Adaptive is a Selector which picks items in proportion to weights that drift
+// towards the items yielding the highest rewards, using the Exp3 algorithm.
+// Unlike the other selectors, it starts from an initial weighted distribution,
+// such as the configured weights of backends, and keeps picking every item
+// with at least a minimum probability, so it continues to adapt if rewards
+// change over time.
+//
+// Each reward is scaled by the inverse of the probability of the item having
+// been picked, so that rarely picked items are not penalized for receiving
+// fewer reports.
+type Adaptive[T comparable] struct {
+	rate float64
+
+	mu      sync.Mutex
+	items   []T
+	index   map[T]int
+	logW    []float64 // log weights, to avoid overflow as rewards accrue
+	probs   []float64
+	cumProb []float64
+}
+
+// NewAdaptive initializes an Adaptive selector starting from the distribution
+// of choices, which may be of any weights accepted by weightedrand.NewChooser.
+// Choices with a weight < 1 are excluded.
+//
+// The learning rate, in the range (0, 1], controls how quickly weights respond
+// to rewards. It is also the fraction of picks spread uniformly across all
+// items, so that no item is ever starved of picks. Values around 0.01 to 0.1
+// are typical. Any other rate returns ErrLearningRate.
+func NewAdaptive[T comparable, W constraints.Integer](learningRate float64, choices ...weightedrand.Choice[T, W]) (*Adaptive[T], error) {
+	if !(learningRate > 0 && learningRate <= 1) {
+		return nil, ErrLearningRate
+	}
+	c, err := weightedrand.NewChooser(choices...)
+	if err != nil {
+		return nil, err
+	}
+	a := &Adaptive[T]{rate: learningRate, index: make(map[T]int)}
+	for _, choice := range c.Choices() {
+		if choice.Weight < 1 {
+			continue
+		}
+		a.index[choice.Item] = len(a.items)
+		a.items = append(a.items, choice.Item)
+		a.logW = append(a.logW, math.Log(float64(choice.Weight)))
+	}
+	a.probs = make([]float64, len(a.items))
+	a.cumProb = make([]float64, len(a.items))
+	a.update()
+	return a, nil
+}
+
+// Pick returns the next item to use.
+func (a *Adaptive[T]) Pick() T {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	i := sort.SearchFloat64s(a.cumProb, rand.Float64())
+	if i == len(a.items) {
+		i-- // guard against rounding of the final cumulative probability
+	}
+	return a.items[i]
+}
+
+// Report records the reward observed for an item. Rewards are clamped to the
+// range [0, 1], with NaN counted as 0, and unknown items are ignored.
+func (a *Adaptive[T]) Report(item T, reward float64) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	i, ok := a.index[item]
+	if !ok {
+		return
+	}
+	estimate := clamp01(reward) / a.probs[i]
+	a.logW[i] += a.rate * estimate / float64(len(a.items))
+	a.update()
+}
+
+// Probability returns the current probability of item being picked, or 0 if
+// item is unknown.
+func (a *Adaptive[T]) Probability(item T) float64 {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	i, ok := a.index[item]
+	if !ok {
+		return 0
+	}
+	return a.probs[i]
+}
+
+// update recomputes pick probabilities from the current weights, mixed with
+// the uniform distribution in proportion to the learning rate.
+func (a *Adaptive[T]) update() {
+	maxLogW := math.Inf(-1)
+	for _, l := range a.logW {
+		maxLogW = math.Max(maxLogW, l)
+	}
+	var sum float64
+	for i, l := range a.logW {
+		a.probs[i] = math.Exp(l - maxLogW)
+		sum += a.probs[i]
+	}
+	k := float64(len(a.items))
+	var cum float64
+	for i := range a.probs {
+		a.probs[i] = (1-a.rate)*a.probs[i]/sum + a.rate/k
+		cum += a.probs[i]
+		a.cumProb[i] = cum
+	}
+}
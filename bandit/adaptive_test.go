@@ -0,0 +1,78 @@
+package bandit
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/mroth/weightedrand/v2"
+)
+
+func TestNewAdaptive(t *testing.T) {
+	a, err := NewAdaptive(0.1,
+		weightedrand.NewChoice("a", 1),
+		weightedrand.NewChoice("b", 3),
+		weightedrand.NewChoice("never", 0),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// initial probabilities follow the weights, mixed with 10% uniform
+	for item, want := range map[string]float64{"a": 0.9*0.25 + 0.05, "b": 0.9*0.75 + 0.05, "never": 0} {
+		if got := a.Probability(item); math.Abs(got-want) > 1e-12 {
+			t.Errorf("Probability(%q) = %v, want %v", item, got, want)
+		}
+	}
+
+	if _, err := NewAdaptive[string, int](0.1); err == nil {
+		t.Error("NewAdaptive() with no choices should return an error")
+	}
+}
+
+func TestNewAdaptive_learningRate(t *testing.T) {
+	tests := []struct {
+		rate    float64
+		wantErr error
+	}{
+		{rate: 0.1},
+		{rate: 1},
+		{rate: 0, wantErr: ErrLearningRate},
+		{rate: -0.1, wantErr: ErrLearningRate},
+		{rate: 1.5, wantErr: ErrLearningRate},
+		{rate: math.NaN(), wantErr: ErrLearningRate},
+		{rate: math.Inf(1), wantErr: ErrLearningRate},
+	}
+	for _, tt := range tests {
+		_, err := NewAdaptive(tt.rate, weightedrand.NewChoice("a", 1), weightedrand.NewChoice("b", 1))
+		if err != tt.wantErr {
+			t.Errorf("NewAdaptive(%v) error = %v, want %v", tt.rate, err, tt.wantErr)
+		}
+	}
+}
+
+func TestAdaptive_Report(t *testing.T) {
+	a, _ := NewAdaptive(0.1,
+		weightedrand.NewChoice("bad", 9),
+		weightedrand.NewChoice("good", 1),
+	)
+	a.Report("unknown", 1) // ignored
+
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 5000; i++ {
+		item := a.Pick()
+		var reward float64
+		if item == "good" && rng.Float64() < 0.8 || item == "bad" && rng.Float64() < 0.2 {
+			reward = 1
+		}
+		a.Report(item, reward)
+	}
+	// weights drift towards the better item, but exploration keeps a floor
+	if p := a.Probability("good"); p < 0.9 {
+		t.Errorf("Probability(good) = %v, want > 0.9", p)
+	}
+	if p := a.Probability("bad"); p < 0.05 {
+		t.Errorf("Probability(bad) = %v, want >= 0.05", p)
+	}
+}
+
+var _ Selector[string] = (*Adaptive[string])(nil)
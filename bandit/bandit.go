@@ -0,0 +1,95 @@
+// Package bandit provides multi-armed bandit selectors, which learn to favor
+// the items yielding the highest rewards, along with an offline simulator for
+// comparing their performance against a static weighted Chooser.
+package bandit
+
+import (
+	"github.com/mroth/weightedrand/v2"
+	"github.com/mroth/weightedrand/v2/internal/constraints"
+)
+
+// A Selector picks items and learns from the rewards reported for them. All
+// selectors in this package implement Selector and are safe for concurrent
+// usage, so policies can be swapped without changing call sites.
+type Selector[T comparable] interface {
+	// Pick returns the next item to use.
+	Pick() T
+	// Report records the reward observed for an item. Rewards are expected to
+	// be in the range [0, 1], with higher values being better.
+	Report(item T, reward float64)
+}
+
+// Static is a Selector which picks from a fixed weighted distribution, ignoring
+// all reported rewards. It serves as a baseline to compare adaptive policies
+// against.
+type Static[T comparable] struct {
+	pick func() T
+}
+
+// NewStatic initializes a Static selector picking from choices.
+func NewStatic[T comparable, W constraints.Integer](choices ...weightedrand.Choice[T, W]) (*Static[T], error) {
+	c, err := weightedrand.NewChooser(choices...)
+	if err != nil {
+		return nil, err
+	}
+	return &Static[T]{pick: c.Pick}, nil
+}
+
+// Pick returns a single weighted random item.
+func (s *Static[T]) Pick() T { return s.pick() }
+
+// Report is a no-op.
+func (s *Static[T]) Report(item T, reward float64) {}
+
+// arms tracks pull counts and cumulative rewards for a fixed set of items.
+// Callers are responsible for synchronization.
+type arms[T comparable] struct {
+	items  []T
+	index  map[T]int
+	pulls  []float64
+	totals []float64
+}
+
+func newArms[T comparable](items []T) arms[T] {
+	a := arms[T]{
+		items:  items,
+		index:  make(map[T]int, len(items)),
+		pulls:  make([]float64, len(items)),
+		totals: make([]float64, len(items)),
+	}
+	for i, item := range items {
+		a.index[item] = i
+	}
+	return a
+}
+
+// record adds reward to the arm for item, returning its index, or -1 if item
+// is unknown.
+func (a *arms[T]) record(item T, reward float64) int {
+	i, ok := a.index[item]
+	if !ok {
+		return -1
+	}
+	a.pulls[i]++
+	a.totals[i] += reward
+	return i
+}
+
+func (a *arms[T]) mean(i int) float64 {
+	if a.pulls[i] == 0 {
+		return 0
+	}
+	return a.totals[i] / a.pulls[i]
+}
+
+// clamp01 limits a reward to the range [0, 1]. NaN is treated as 0, since it
+// would otherwise poison every estimate it is folded into.
+func clamp01(x float64) float64 {
+	switch {
+	case !(x >= 0):
+		return 0
+	case x > 1:
+		return 1
+	}
+	return x
+}
@@ -0,0 +1,45 @@
+package bandit
+
+import (
+	"testing"
+
+	"github.com/mroth/weightedrand/v2"
+)
+
+// Interface conformance for all selectors.
+var (
+	_ Selector[string] = (*Static[string])(nil)
+	_ Selector[string] = (*EpsilonGreedy[string])(nil)
+	_ Selector[string] = (*Thompson[string])(nil)
+)
+
+func TestStatic(t *testing.T) {
+	if _, err := NewStatic[string, int](); err == nil {
+		t.Error("expected error for no choices")
+	}
+	s, err := NewStatic(weightedrand.NewChoice("a", 0), weightedrand.NewChoice("b", 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 100; i++ {
+		s.Report("a", 1) // should have no effect
+		if got := s.Pick(); got != "b" {
+			t.Fatalf("Pick() = %q, want %q", got, "b")
+		}
+	}
+}
+
+func TestArms(t *testing.T) {
+	a := newArms([]string{"a", "b"})
+	if i := a.record("z", 1); i != -1 {
+		t.Errorf("record() for unknown item = %d, want -1", i)
+	}
+	a.record("b", 1)
+	a.record("b", 0)
+	if m := a.mean(1); m != 0.5 {
+		t.Errorf("mean() = %v, want 0.5", m)
+	}
+	if m := a.mean(0); m != 0 {
+		t.Errorf("mean() of unpulled arm = %v, want 0", m)
+	}
+}
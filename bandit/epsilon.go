@@ -0,0 +1,54 @@
+package bandit
+
+import (
+	"math/rand"
+	"sync"
+)
+
+// EpsilonGreedy is a Selector which picks the item with the highest mean
+// reward observed so far, except with probability epsilon, when it explores a
+// uniformly random item instead. Items which have never been reported on are
+// tried first.
+type EpsilonGreedy[T comparable] struct {
+	epsilon float64
+
+	mu   sync.Mutex
+	arms arms[T]
+}
+
+// NewEpsilonGreedy initializes an EpsilonGreedy selector over items, exploring
+// with probability epsilon. It panics if items is empty.
+func NewEpsilonGreedy[T comparable](epsilon float64, items ...T) *EpsilonGreedy[T] {
+	if len(items) == 0 {
+		panic("bandit: no items")
+	}
+	return &EpsilonGreedy[T]{epsilon: epsilon, arms: newArms(items)}
+}
+
+// Pick returns the next item to use.
+func (e *EpsilonGreedy[T]) Pick() T {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if rand.Float64() < e.epsilon {
+		return e.arms.items[rand.Intn(len(e.arms.items))]
+	}
+	best, bestMean := 0, -1.0
+	for i := range e.arms.items {
+		if e.arms.pulls[i] == 0 {
+			return e.arms.items[i]
+		}
+		if m := e.arms.mean(i); m > bestMean {
+			best, bestMean = i, m
+		}
+	}
+	return e.arms.items[best]
+}
+
+// Report records the reward observed for an item. Rewards are clamped to the
+// range [0, 1], with NaN counted as 0, and unknown items are ignored.
+func (e *EpsilonGreedy[T]) Report(item T, reward float64) {
+	e.mu.Lock()
+	e.arms.record(item, clamp01(reward))
+	e.mu.Unlock()
+}
@@ -0,0 +1,38 @@
+package bandit
+
+import "testing"
+
+func TestEpsilonGreedy(t *testing.T) {
+	e := NewEpsilonGreedy(0, "a", "b", "c")
+
+	// untried items are picked first
+	seen := make(map[string]bool)
+	for i := 0; i < 3; i++ {
+		item := e.Pick()
+		seen[item] = true
+		reward := 0.0
+		if item == "b" {
+			reward = 1
+		}
+		e.Report(item, reward)
+	}
+	if len(seen) != 3 {
+		t.Fatalf("expected each item to be tried once, got %v", seen)
+	}
+
+	// with no exploration, the best item is always exploited
+	for i := 0; i < 100; i++ {
+		if got := e.Pick(); got != "b" {
+			t.Fatalf("Pick() = %q, want %q", got, "b")
+		}
+	}
+}
+
+func TestNewEpsilonGreedy_panic(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for no items")
+		}
+	}()
+	NewEpsilonGreedy[string](0.1)
+}
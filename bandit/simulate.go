@@ -0,0 +1,95 @@
+package bandit
+
+import (
+	"math/rand"
+)
+
+// An Arm describes the reward model for a single item in a Simulation.
+type Arm[T any] struct {
+	Item T
+	// Mean is the expected reward of the item, used to compute regret.
+	Mean float64
+	// Reward samples a reward for a single use of the item. If nil, a reward of
+	// 1 is returned with probability Mean, and 0 otherwise.
+	Reward func() float64
+}
+
+func (a Arm[T]) sample() float64 {
+	if a.Reward != nil {
+		return a.Reward()
+	}
+	if rand.Float64() < a.Mean {
+		return 1
+	}
+	return 0
+}
+
+// A Simulation replays a reward model against selectors offline, so that
+// policies can be compared before being deployed.
+type Simulation[T comparable] struct {
+	Arms  []Arm[T]
+	Steps int // number of picks per run
+	Runs  int // number of independent runs to average over, defaults to 1
+}
+
+// Regret is a curve of mean cumulative regret, where Regret[i] is the total
+// expected reward lost after i+1 picks versus always picking the best arm.
+type Regret []float64
+
+// Final returns the cumulative regret at the end of the simulation.
+func (r Regret) Final() float64 {
+	if len(r) == 0 {
+		return 0
+	}
+	return r[len(r)-1]
+}
+
+// Run simulates a fresh selector from newSelector for each run, reporting the
+// sampled reward for every pick, and returns the regret curve averaged across
+// all runs.
+//
+// Picks of items not described by the simulation's Arms cause a panic.
+func (s Simulation[T]) Run(newSelector func() Selector[T]) Regret {
+	arms := make(map[T]Arm[T], len(s.Arms))
+	best := 0.0
+	for i, a := range s.Arms {
+		arms[a.Item] = a
+		if i == 0 || a.Mean > best {
+			best = a.Mean
+		}
+	}
+	runs := s.Runs
+	if runs < 1 {
+		runs = 1
+	}
+
+	regret := make(Regret, s.Steps)
+	for run := 0; run < runs; run++ {
+		sel := newSelector()
+		var total float64
+		for i := range regret {
+			item := sel.Pick()
+			arm, ok := arms[item]
+			if !ok {
+				panic("bandit: simulated selector picked unknown item")
+			}
+			sel.Report(item, arm.sample())
+			total += best - arm.Mean
+			regret[i] += total
+		}
+	}
+	for i := range regret {
+		regret[i] /= float64(runs)
+	}
+	return regret
+}
+
+// Compare runs the simulation for each of the named selectors, returning their
+// regret curves by name.
+func (s Simulation[T]) Compare(selectors map[string]func() Selector[T]) map[string]Regret {
+	results := make(map[string]Regret, len(selectors))
+	for name, fn := range selectors {
+		results[name] = s.Run(fn)
+	}
+	return results
+}
@@ -0,0 +1,72 @@
+package bandit
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/mroth/weightedrand/v2"
+)
+
+func ExampleSimulation_Compare() {
+	sim := Simulation[string]{
+		Arms: []Arm[string]{
+			{Item: "control", Mean: 0.05},
+			{Item: "variant", Mean: 0.10},
+		},
+		Steps: 10000,
+		Runs:  10,
+	}
+	results := sim.Compare(map[string]func() Selector[string]{
+		"static": func() Selector[string] {
+			s, _ := NewStatic(weightedrand.NewChoice("control", 1), weightedrand.NewChoice("variant", 1))
+			return s
+		},
+		"thompson": func() Selector[string] { return NewThompson("control", "variant") },
+	})
+	fmt.Println(results["thompson"].Final() < results["static"].Final())
+	//Output: true
+}
+
+func TestSimulation_Run(t *testing.T) {
+	sim := Simulation[string]{
+		Arms: []Arm[string]{
+			{Item: "bad", Mean: 0.2},
+			{Item: "good", Mean: 0.8, Reward: func() float64 { return 0.8 }},
+		},
+		Steps: 100,
+	}
+
+	// always picking the worst arm accrues regret linearly
+	worst := sim.Run(func() Selector[string] {
+		s, _ := NewStatic(weightedrand.NewChoice("bad", 1))
+		return s
+	})
+	if len(worst) != sim.Steps {
+		t.Fatalf("len(Regret) = %d, want %d", len(worst), sim.Steps)
+	}
+	for i, r := range worst {
+		if want := 0.6 * float64(i+1); math.Abs(r-want) > 1e-9 {
+			t.Fatalf("Regret[%d] = %v, want %v", i, r, want)
+		}
+	}
+
+	// always picking the best arm accrues none
+	best := sim.Run(func() Selector[string] {
+		s, _ := NewStatic(weightedrand.NewChoice("good", 1))
+		return s
+	})
+	if best.Final() != 0 {
+		t.Errorf("Final() = %v, want 0", best.Final())
+	}
+}
+
+func TestSimulation_Run_unknownItem(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for unknown item")
+		}
+	}()
+	sim := Simulation[string]{Arms: []Arm[string]{{Item: "a", Mean: 1}}, Steps: 1}
+	sim.Run(func() Selector[string] { return NewThompson("z") })
+}
@@ -0,0 +1,167 @@
+package bandit
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// StateVersion is the version of the State format written by Snapshot.
+const StateVersion = 1
+
+// ErrState is returned when restoring a State which is invalid or of an
+// unsupported version.
+var ErrState = errors.New("bandit: invalid state")
+
+// State is the runtime-learned state of a selector, so that what has been
+// learned can survive process restarts. Its JSON encoding is stable, provided
+// the items themselves encode stably.
+type State[T comparable] struct {
+	Version int           `json:"version"`
+	Arms    []ArmState[T] `json:"arms"`
+}
+
+// ArmState is the learned state of a single item. Each selector uses only the
+// fields relevant to it: Pulls and Rewards for EpsilonGreedy, UCB1 and
+// Thompson, and LogWeight for Adaptive.
+type ArmState[T comparable] struct {
+	Item      T       `json:"item"`
+	Pulls     float64 `json:"pulls,omitempty"`      // number of rewards reported
+	Rewards   float64 `json:"rewards,omitempty"`    // sum of rewards reported
+	LogWeight float64 `json:"log_weight,omitempty"` // natural log of the weight
+}
+
+// validate checks the version of s and the values of its arms.
+func (s State[T]) validate() error {
+	if s.Version != StateVersion {
+		return fmt.Errorf("%w: unsupported version %d", ErrState, s.Version)
+	}
+	for _, a := range s.Arms {
+		if !finite(a.Pulls) || !finite(a.Rewards) || !finite(a.LogWeight) || a.Pulls < 0 || a.Rewards < 0 || a.Rewards > a.Pulls {
+			return fmt.Errorf("%w: arm %v", ErrState, a.Item)
+		}
+	}
+	return nil
+}
+
+func finite(x float64) bool {
+	return !math.IsNaN(x) && !math.IsInf(x, 0)
+}
+
+func (a *arms[T]) snapshot() State[T] {
+	s := State[T]{Version: StateVersion, Arms: make([]ArmState[T], len(a.items))}
+	for i, item := range a.items {
+		s.Arms[i] = ArmState[T]{Item: item, Pulls: a.pulls[i], Rewards: a.totals[i]}
+	}
+	return s
+}
+
+// restore replaces the state of each arm in s, returning the change in the
+// total number of pulls.
+func (a *arms[T]) restore(s State[T]) (float64, error) {
+	if err := s.validate(); err != nil {
+		return 0, err
+	}
+	var delta float64
+	for _, arm := range s.Arms {
+		if i, ok := a.index[arm.Item]; ok {
+			delta += arm.Pulls - a.pulls[i]
+			a.pulls[i], a.totals[i] = arm.Pulls, arm.Rewards
+		}
+	}
+	return delta, nil
+}
+
+// Snapshot returns the learned state of the selector.
+func (e *EpsilonGreedy[T]) Snapshot() State[T] {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.arms.snapshot()
+}
+
+// Restore replaces the learned state of the items of the selector which are in
+// s. Items of s which are unknown to the selector are ignored. If s is
+// invalid, an error wrapping ErrState is returned and nothing is restored.
+func (e *EpsilonGreedy[T]) Restore(s State[T]) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	_, err := e.arms.restore(s)
+	return err
+}
+
+// Snapshot returns the learned state of the selector.
+func (u *UCB1[T]) Snapshot() State[T] {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.arms.snapshot()
+}
+
+// Restore replaces the learned state of the items of the selector which are in
+// s. Items of s which are unknown to the selector are ignored. If s is
+// invalid, an error wrapping ErrState is returned and nothing is restored.
+func (u *UCB1[T]) Restore(s State[T]) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	delta, err := u.arms.restore(s)
+	u.total += delta
+	return err
+}
+
+// Snapshot returns the learned state of the selector, as the number and sum of
+// rewards from which its Beta distributions were updated.
+func (t *Thompson[T]) Snapshot() State[T] {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	s := State[T]{Version: StateVersion, Arms: make([]ArmState[T], len(t.items))}
+	for i, item := range t.items {
+		s.Arms[i] = ArmState[T]{Item: item, Pulls: t.alpha[i] + t.beta[i] - 2, Rewards: t.alpha[i] - 1}
+	}
+	return s
+}
+
+// Restore replaces the learned state of the items of the selector which are in
+// s. Items of s which are unknown to the selector are ignored. If s is
+// invalid, an error wrapping ErrState is returned and nothing is restored.
+func (t *Thompson[T]) Restore(s State[T]) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if err := s.validate(); err != nil {
+		return err
+	}
+	for _, arm := range s.Arms {
+		if i, ok := t.index[arm.Item]; ok {
+			t.alpha[i], t.beta[i] = 1+arm.Rewards, 1+arm.Pulls-arm.Rewards
+		}
+	}
+	return nil
+}
+
+// Snapshot returns the learned state of the selector, as the log weight of
+// each item.
+func (a *Adaptive[T]) Snapshot() State[T] {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	s := State[T]{Version: StateVersion, Arms: make([]ArmState[T], len(a.items))}
+	for i, item := range a.items {
+		s.Arms[i] = ArmState[T]{Item: item, LogWeight: a.logW[i]}
+	}
+	return s
+}
+
+// Restore replaces the learned state of the items of the selector which are in
+// s. Items of s which are unknown to the selector are ignored. If s is
+// invalid, an error wrapping ErrState is returned and nothing is restored.
+func (a *Adaptive[T]) Restore(s State[T]) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err := s.validate(); err != nil {
+		return err
+	}
+	for _, arm := range s.Arms {
+		if i, ok := a.index[arm.Item]; ok {
+			a.logW[i] = arm.LogWeight
+		}
+	}
+	a.update()
+	return nil
+}
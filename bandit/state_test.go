@@ -0,0 +1,160 @@
+package bandit
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mroth/weightedrand/v2"
+)
+
+// snapshotter is implemented by all selectors with learned state.
+type snapshotter interface {
+	Selector[string]
+	Snapshot() State[string]
+	Restore(State[string]) error
+}
+
+func TestSnapshotRestore(t *testing.T) {
+	newAdaptive := func() snapshotter {
+		a, err := NewAdaptive(0.1, weightedrand.NewChoice("a", 1), weightedrand.NewChoice("b", 1))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return a
+	}
+	selectors := map[string]func() snapshotter{
+		"EpsilonGreedy": func() snapshotter { return NewEpsilonGreedy(0.1, "a", "b") },
+		"UCB1":          func() snapshotter { return NewUCB1("a", "b") },
+		"Thompson":      func() snapshotter { return NewThompson("a", "b") },
+		"Adaptive":      newAdaptive,
+	}
+	for name, newSelector := range selectors {
+		t.Run(name, func(t *testing.T) {
+			trained := newSelector()
+			for i := 0; i < 100; i++ {
+				trained.Report("a", 1)
+				trained.Report("b", 0.25)
+			}
+			data, err := json.Marshal(trained.Snapshot())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var s State[string]
+			if err := json.Unmarshal(data, &s); err != nil {
+				t.Fatal(err)
+			}
+			restored := newSelector()
+			if err := restored.Restore(s); err != nil {
+				t.Fatal(err)
+			}
+			if got, want := restored.Snapshot(), trained.Snapshot(); !reflect.DeepEqual(got, want) {
+				t.Errorf("Snapshot() after Restore = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestSnapshotRestore_outOfRangeReward(t *testing.T) {
+	// Rewards outside [0, 1] must not produce a state Restore rejects.
+	selectors := map[string]func() snapshotter{
+		"EpsilonGreedy": func() snapshotter { return NewEpsilonGreedy(0.1, "a", "b") },
+		"UCB1":          func() snapshotter { return NewUCB1("a", "b") },
+		"Thompson":      func() snapshotter { return NewThompson("a", "b") },
+	}
+	for name, newSelector := range selectors {
+		t.Run(name, func(t *testing.T) {
+			trained := newSelector()
+			trained.Report("a", 5)
+			trained.Report("b", -2)
+			if err := newSelector().Restore(trained.Snapshot()); err != nil {
+				t.Errorf("Restore(Snapshot()) error = %v", err)
+			}
+		})
+	}
+}
+
+func TestUCB1_Restore(t *testing.T) {
+	u := NewUCB1("a", "b")
+	err := u.Restore(State[string]{Version: StateVersion, Arms: []ArmState[string]{
+		{Item: "a", Pulls: 10, Rewards: 9},
+		{Item: "b", Pulls: 10, Rewards: 1},
+		{Item: "unknown", Pulls: 5},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.total != 20 {
+		t.Errorf("total = %v, want 20", u.total)
+	}
+	if got := u.Pick(); got != "a" {
+		t.Errorf("Pick() = %q, want %q", got, "a")
+	}
+}
+
+func TestRestore_invalid(t *testing.T) {
+	tests := map[string]State[string]{
+		"version":     {Version: 0},
+		"negative":    {Version: StateVersion, Arms: []ArmState[string]{{Item: "a", Pulls: -1}}},
+		"rewards":     {Version: StateVersion, Arms: []ArmState[string]{{Item: "a", Pulls: 1, Rewards: 2}}},
+		"nan":         {Version: StateVersion, Arms: []ArmState[string]{{Item: "a", LogWeight: math.NaN()}}},
+		"inf":         {Version: StateVersion, Arms: []ArmState[string]{{Item: "a", Pulls: math.Inf(1)}}},
+		"unknown arm": {Version: StateVersion, Arms: []ArmState[string]{{Item: "zzz", Pulls: -1}}},
+	}
+	for name, s := range tests {
+		t.Run(name, func(t *testing.T) {
+			e := NewEpsilonGreedy(0, "a")
+			before := e.Snapshot()
+			if err := e.Restore(s); !errors.Is(err, ErrState) {
+				t.Errorf("Restore() error = %v, want %v", err, ErrState)
+			}
+			if !reflect.DeepEqual(e.Snapshot(), before) {
+				t.Error("failed Restore() modified state")
+			}
+		})
+	}
+}
+
+func TestReport_NaN(t *testing.T) {
+	// A NaN reward must neither stall Pick nor produce a state Restore rejects.
+	newAdaptive := func() snapshotter {
+		a, err := NewAdaptive(0.1, weightedrand.NewChoice("a", 1), weightedrand.NewChoice("b", 1))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return a
+	}
+	selectors := map[string]func() snapshotter{
+		"EpsilonGreedy": func() snapshotter { return NewEpsilonGreedy(0.1, "a", "b") },
+		"UCB1":          func() snapshotter { return NewUCB1("a", "b") },
+		"Thompson":      func() snapshotter { return NewThompson("a", "b") },
+		"Adaptive":      newAdaptive,
+	}
+	for name, newSelector := range selectors {
+		t.Run(name, func(t *testing.T) {
+			s := newSelector()
+			s.Report("a", math.NaN())
+			s.Report("b", math.NaN())
+
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				for i := 0; i < 100; i++ {
+					s.Pick()
+				}
+			}()
+			select {
+			case <-done:
+			case <-time.After(5 * time.Second):
+				t.Fatal("Pick did not return after a NaN reward")
+			}
+			if err := newSelector().Restore(s.Snapshot()); err != nil {
+				t.Errorf("Restore(Snapshot()) error = %v", err)
+			}
+		})
+	}
+}
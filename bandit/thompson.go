@@ -0,0 +1,92 @@
+package bandit
+
+import (
+	"math"
+	"math/rand"
+	"sync"
+)
+
+// Thompson is a Selector implementing Thompson sampling with a Beta-Bernoulli
+// model: each item's reward probability is modeled as a Beta distribution
+// updated by reported rewards, and each Pick samples from every distribution
+// and selects the item with the highest sample.
+//
+// Rewards between 0 and 1 are treated as fractional successes.
+type Thompson[T comparable] struct {
+	mu    sync.Mutex
+	items []T
+	index map[T]int
+	alpha []float64
+	beta  []float64
+}
+
+// NewThompson initializes a Thompson selector over items, starting from a
+// uniform prior for each. It panics if items is empty.
+func NewThompson[T comparable](items ...T) *Thompson[T] {
+	if len(items) == 0 {
+		panic("bandit: no items")
+	}
+	t := &Thompson[T]{
+		items: items,
+		index: make(map[T]int, len(items)),
+		alpha: make([]float64, len(items)),
+		beta:  make([]float64, len(items)),
+	}
+	for i, item := range items {
+		t.index[item] = i
+		t.alpha[i], t.beta[i] = 1, 1
+	}
+	return t
+}
+
+// Pick returns the next item to use.
+func (t *Thompson[T]) Pick() T {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	best, bestSample := 0, -1.0
+	for i := range t.items {
+		if s := sampleBeta(t.alpha[i], t.beta[i]); s > bestSample {
+			best, bestSample = i, s
+		}
+	}
+	return t.items[best]
+}
+
+// Report records the reward observed for an item. Rewards are clamped to the
+// range [0, 1], with NaN counted as 0, and unknown items are ignored.
+func (t *Thompson[T]) Report(item T, reward float64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if i, ok := t.index[item]; ok {
+		r := clamp01(reward)
+		t.alpha[i] += r
+		t.beta[i] += 1 - r
+	}
+}
+
+// sampleBeta draws from a Beta(a, b) distribution, for a, b >= 1.
+func sampleBeta(a, b float64) float64 {
+	x := sampleGamma(a)
+	y := sampleGamma(b)
+	return x / (x + y)
+}
+
+// sampleGamma draws from a Gamma(a, 1) distribution for a >= 1, using the
+// method of Marsaglia and Tsang (2000).
+func sampleGamma(a float64) float64 {
+	d := a - 1.0/3
+	c := 1 / math.Sqrt(9*d)
+	for {
+		x := rand.NormFloat64()
+		v := 1 + c*x
+		if v <= 0 {
+			continue
+		}
+		v = v * v * v
+		u := rand.Float64()
+		if math.Log(u) < 0.5*x*x+d-d*v+d*math.Log(v) {
+			return d * v
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package bandit
+
+import (
+	"math"
+	"testing"
+)
+
+func TestThompson(t *testing.T) {
+	th := NewThompson("a", "b")
+	th.Report("z", 1) // unknown items are ignored
+	for i := 0; i < 100; i++ {
+		th.Report("a", 0)
+		th.Report("b", 1)
+	}
+	counts := make(map[string]int)
+	for i := 0; i < 1000; i++ {
+		counts[th.Pick()]++
+	}
+	if counts["b"] < 990 {
+		t.Errorf("expected b to dominate picks, got %v", counts)
+	}
+}
+
+func TestSampleBeta(t *testing.T) {
+	const n = 100000
+	a, b := 2.0, 5.0
+	var sum float64
+	for i := 0; i < n; i++ {
+		x := sampleBeta(a, b)
+		if x < 0 || x > 1 {
+			t.Fatalf("sampleBeta() = %v, out of range", x)
+		}
+		sum += x
+	}
+	if mean, want := sum/n, a/(a+b); math.Abs(mean-want) > 0.01 {
+		t.Errorf("sample mean = %v, want %v", mean, want)
+	}
+}
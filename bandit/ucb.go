@@ -0,0 +1,58 @@
+package bandit
+
+import (
+	"math"
+	"sync"
+)
+
+// UCB1 is a Selector implementing the UCB1 policy: it picks the item with the
+// highest upper confidence bound on its mean reward,
+//
+//	mean(i) + sqrt(2 ln(n) / n(i))
+//
+// where n(i) is the number of rewards reported for item i and n the total. The
+// bound shrinks as an item is reported on, so exploration of each item tapers
+// off as confidence in its mean grows. Items which have never been reported
+// on are tried first. Unlike the other selectors, it is deterministic.
+type UCB1[T comparable] struct {
+	mu    sync.Mutex
+	arms  arms[T]
+	total float64
+}
+
+// NewUCB1 initializes a UCB1 selector over items. It panics if items is empty.
+func NewUCB1[T comparable](items ...T) *UCB1[T] {
+	if len(items) == 0 {
+		panic("bandit: no items")
+	}
+	return &UCB1[T]{arms: newArms(items)}
+}
+
+// Pick returns the next item to use.
+func (u *UCB1[T]) Pick() T {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	logTotal := math.Log(u.total)
+	best, bestBound := 0, math.Inf(-1)
+	for i := range u.arms.items {
+		if u.arms.pulls[i] == 0 {
+			return u.arms.items[i]
+		}
+		bound := u.arms.mean(i) + math.Sqrt(2*logTotal/u.arms.pulls[i])
+		if bound > bestBound {
+			best, bestBound = i, bound
+		}
+	}
+	return u.arms.items[best]
+}
+
+// Report records the reward observed for an item. Rewards are clamped to the
+// range [0, 1], with NaN counted as 0, and unknown items are ignored.
+func (u *UCB1[T]) Report(item T, reward float64) {
+	u.mu.Lock()
+	if u.arms.record(item, clamp01(reward)) >= 0 {
+		u.total++
+	}
+	u.mu.Unlock()
+}
@@ -0,0 +1,48 @@
+package bandit
+
+import (
+	"math"
+	"testing"
+)
+
+func TestUCB1(t *testing.T) {
+	u := NewUCB1("a", "b", "c")
+	u.Report("unknown", 1) // ignored
+
+	// untried items are picked first
+	seen := make(map[string]bool)
+	for i := 0; i < 3; i++ {
+		item := u.Pick()
+		seen[item] = true
+		u.Report(item, map[string]float64{"a": 0.2, "b": 0.9, "c": 0.5}[item])
+	}
+	if len(seen) != 3 {
+		t.Fatalf("expected each item to be tried once, got %v", seen)
+	}
+
+	// the best item dominates, while the others are still explored
+	// logarithmically often
+	counts := make(map[string]int)
+	for i := 0; i < 1000; i++ {
+		item := u.Pick()
+		counts[item]++
+		u.Report(item, map[string]float64{"a": 0.2, "b": 0.9, "c": 0.5}[item])
+	}
+	if counts["b"] < 900 || counts["a"] == 0 || counts["c"] == 0 {
+		t.Errorf("unexpected distribution of picks: %v", counts)
+	}
+	if max := 8 * math.Log(1003); float64(counts["a"]) > max {
+		t.Errorf("worst item picked %d times, want at most %.0f", counts["a"], max)
+	}
+}
+
+func TestNewUCB1_panic(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for no items")
+		}
+	}()
+	NewUCB1[string]()
+}
+
+var _ Selector[string] = (*UCB1[string])(nil)
@@ -0,0 +1,76 @@
+package weightedrand
+
+import "sort"
+
+// PickN returns n independent weighted random Choice.Items from the Chooser,
+// as if by n calls to Pick.
+//
+// Rather than performing n independent binary searches, all random numbers are
+// drawn up front and sorted, and then resolved in a single forward pass over
+// the Chooser's totals, with each search starting from the position of the
+// previous result. For large batches from large Choosers this greatly improves
+// memory locality. The results are shuffled before being returned, so their
+// order is as random as that of individual picks.
+//
+// Safe for concurrent usage.
+func (c Chooser[T, W]) PickN(n int) []T {
+	if n <= 0 {
+		return nil
+	}
+	draws := make([]int, n)
+	for i := range draws {
+		draws[i] = c.intn(c.max) + 1
+	}
+	sort.Ints(draws)
+
+	items := make([]T, n)
+	i := 0
+	for j, r := range draws {
+		i = gallopInts(c.totals, i, r)
+		items[j] = c.selected(i)
+	}
+	// Fisher–Yates shuffle, drawing from the Chooser's own source so that
+	// seeded Choosers return reproducible batches.
+	for i := len(items) - 1; i > 0; i-- {
+		j := c.intn(i + 1)
+		items[i], items[j] = items[j], items[i]
+	}
+	return items
+}
+
+// PickNInto fills dst with independent weighted random Choice.Items from the
+// Chooser, as if by len(dst) calls to Pick, without allocating. It is intended
+// for hot paths which pick into reusable batch buffers.
+//
+// Unlike PickN, each pick is resolved by an independent search, since resolving
+// them in sorted order would require a buffer for the random numbers. For
+// very large batches from very large Choosers, PickN may therefore be faster
+// despite its allocations.
+//
+// Safe for concurrent usage, provided dst is not shared.
+func (c Chooser[T, W]) PickNInto(dst []T) {
+	for i := range dst {
+		dst[i] = c.selected(c.search(c.intn(c.max) + 1))
+	}
+}
+
+// gallopInts returns the smallest index i such that a[i] >= x, for sorted a
+// where such an index is known to exist and be >= lo. It searches exponentially outwards
+// from lo before binary searching, so is O(log d) for a distance d from lo.
+func gallopInts(a []int, lo, x int) int {
+	if a[lo] >= x {
+		return lo
+	}
+	// Invariant: a[lo] < x.
+	step := 1
+	hi := lo + step
+	for hi < len(a) && a[hi] < x {
+		lo = hi
+		step <<= 1
+		hi = lo + step
+	}
+	if hi > len(a) {
+		hi = len(a)
+	}
+	return lo + 1 + searchInts(a[lo+1:hi], x)
+}
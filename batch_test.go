@@ -0,0 +1,124 @@
+package weightedrand
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestChooser_PickN(t *testing.T) {
+	choices := mockFrequencyChoices(t, testChoices)
+	chooser, err := NewChooser(choices...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := chooser.PickN(0); len(got) != 0 {
+		t.Errorf("PickN(0) = %v, want empty", got)
+	}
+
+	picks := chooser.PickN(testIterations)
+	if len(picks) != testIterations {
+		t.Fatalf("len(PickN()) = %d, want %d", len(picks), testIterations)
+	}
+	counts := make(map[int]int)
+	for _, c := range picks {
+		counts[c]++
+	}
+	verifyFrequencyCounts(t, counts, choices)
+
+	// Results must not be left in sorted order.
+	sorted := true
+	for i := 1; i < len(picks) && sorted; i++ {
+		sorted = picks[i-1] <= picks[i]
+	}
+	if sorted {
+		t.Error("PickN() results are sorted, expected shuffled order")
+	}
+}
+
+func TestChooser_PickN_seeded(t *testing.T) {
+	options := map[string]func() Option{
+		"WithSource":    func() Option { return WithSource(rand.NewSource(42)) },
+		"WithSeededRNG": func() Option { return WithSeededRNG(PCG, 42) },
+	}
+	for name, option := range options {
+		t.Run(name, func(t *testing.T) {
+			picks := func() []rune {
+				c, err := NewChooserWithOptions(mockChoices(100), option())
+				if err != nil {
+					t.Fatal(err)
+				}
+				return c.PickN(50)
+			}
+			if a, b := picks(), picks(); !reflect.DeepEqual(a, b) {
+				t.Errorf("same seed produced different batches:\n%v\n%v", a, b)
+			}
+		})
+	}
+}
+
+func TestChooser_PickNInto(t *testing.T) {
+	choices := mockFrequencyChoices(t, testChoices)
+	chooser, err := NewChooser(choices...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chooser.PickNInto(nil) // no-op
+
+	dst := make([]int, testIterations)
+	chooser.PickNInto(dst)
+	counts := make(map[int]int)
+	for _, c := range dst {
+		counts[c]++
+	}
+	verifyFrequencyCounts(t, counts, choices)
+
+	allocs := testing.AllocsPerRun(10, func() { chooser.PickNInto(dst[:100]) })
+	if allocs != 0 {
+		t.Errorf("PickNInto allocated %v times per run, want 0", allocs)
+	}
+}
+
+func TestGallopInts(t *testing.T) {
+	a := []int{1, 3, 3, 5, 8, 13, 21, 34, 55, 89}
+	for x := 0; x <= a[len(a)-1]; x++ {
+		want := searchInts(a, x)
+		for lo := 0; lo <= want; lo++ {
+			if got := gallopInts(a, lo, x); got != want {
+				t.Fatalf("gallopInts(a, %d, %d) = %d, want %d", lo, x, got, want)
+			}
+		}
+	}
+}
+
+func BenchmarkPickN(b *testing.B) {
+	const size = BMMaxChoices
+	choices := mockChoices(size)
+	chooser, err := NewChooser(choices...)
+	if err != nil {
+		b.Fatal(err)
+	}
+	for batch := 1_000; batch <= 100_000; batch *= 10 {
+		b.Run(fmt.Sprintf("size=%s/batch=%s/method=PickN", fmt1eN(size), fmt1eN(batch)), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_ = chooser.PickN(batch)
+			}
+		})
+		b.Run(fmt.Sprintf("size=%s/batch=%s/method=PickNInto", fmt1eN(size), fmt1eN(batch)), func(b *testing.B) {
+			items := make([]rune, batch)
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				chooser.PickNInto(items)
+			}
+		})
+		b.Run(fmt.Sprintf("size=%s/batch=%s/method=Pick", fmt1eN(size), fmt1eN(batch)), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				items := make([]rune, batch)
+				for j := range items {
+					items[j] = chooser.Pick()
+				}
+			}
+		})
+	}
+}
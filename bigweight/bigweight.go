@@ -0,0 +1,92 @@
+// Package bigweight provides a weighted random chooser with arbitrary precision
+// weights, for configurations whose weights are too large to sum within the
+// integer types supported by weightedrand.Chooser.
+//
+// Cumulative totals are held as math/big integers, so picks are considerably
+// slower than with weightedrand.Chooser and should only be used when required.
+package bigweight
+
+import (
+	"errors"
+	"math/big"
+	"math/rand"
+	"sort"
+)
+
+// ErrNoValidChoices is returned by NewChooser when no choices have a weight
+// >= 1.
+var ErrNoValidChoices = errors.New("bigweight: zero choices with weight >= 1")
+
+// Choice is an item with an arbitrary precision weight.
+type Choice[T any] struct {
+	Item   T
+	Weight *big.Int
+}
+
+// NewChoice creates a new Choice with specified item and weight.
+func NewChoice[T any](item T, weight *big.Int) Choice[T] {
+	return Choice[T]{Item: item, Weight: weight}
+}
+
+// A Chooser caches many possible Choices for weighted random selection. As with
+// weightedrand.Chooser, only choices with a weight >= 1 can be picked.
+type Chooser[T any] struct {
+	items  []T
+	totals []*big.Int
+}
+
+// NewChooser initializes a new Chooser for picking from the provided choices.
+// Choices with a nil, zero or negative weight are ignored. Weights are not
+// retained, so may be modified by the caller afterwards.
+func NewChooser[T any](choices ...Choice[T]) (*Chooser[T], error) {
+	c := &Chooser[T]{}
+	total := new(big.Int)
+	for _, choice := range choices {
+		if choice.Weight == nil || choice.Weight.Sign() <= 0 {
+			continue
+		}
+		total.Add(total, choice.Weight)
+		c.items = append(c.items, choice.Item)
+		c.totals = append(c.totals, new(big.Int).Set(total))
+	}
+	if len(c.items) == 0 {
+		return nil, ErrNoValidChoices
+	}
+	return c, nil
+}
+
+// Total returns the sum of the weights of all choices which can be picked.
+func (c *Chooser[T]) Total() *big.Int {
+	return new(big.Int).Set(c.totals[len(c.totals)-1])
+}
+
+// Pick returns a single weighted random Choice.Item from the Chooser.
+//
+// Utilizes global rand as the source of randomness. Safe for concurrent usage.
+func (c *Chooser[T]) Pick() T {
+	r := randBelow(c.totals[len(c.totals)-1])
+	i := sort.Search(len(c.totals), func(i int) bool {
+		return c.totals[i].Cmp(r) > 0
+	})
+	return c.items[i]
+}
+
+// randBelow returns a uniform random integer in [0,n) for n > 0, by drawing as
+// many random bits as n has and rejecting results >= n, which happens less
+// than half of the time.
+func randBelow(n *big.Int) *big.Int {
+	const wordSize = 32 << (^big.Word(0) >> 63)
+	bits := n.BitLen()
+	words := make([]big.Word, (bits+wordSize-1)/wordSize)
+	mask := ^big.Word(0) >> (len(words)*wordSize - bits)
+	r := new(big.Int)
+	for {
+		for i := range words {
+			words[i] = big.Word(rand.Uint64())
+		}
+		words[len(words)-1] &= mask
+		if r.SetBits(words).Cmp(n) < 0 {
+			return r
+		}
+	}
+}
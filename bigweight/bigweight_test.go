@@ -0,0 +1,92 @@
+package bigweight
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"testing"
+)
+
+func TestNewChooser(t *testing.T) {
+	tests := []struct {
+		name    string
+		choices []Choice[string]
+		wantErr error
+	}{
+		{name: "zero choices", wantErr: ErrNoValidChoices},
+		{
+			name: "no positive weights",
+			choices: []Choice[string]{
+				NewChoice("a", nil),
+				NewChoice("b", big.NewInt(0)),
+				NewChoice("c", big.NewInt(-1)),
+			},
+			wantErr: ErrNoValidChoices,
+		},
+		{
+			name:    "weight beyond uint64",
+			choices: []Choice[string]{NewChoice("a", new(big.Int).Lsh(big.NewInt(1), 200))},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewChooser(tt.choices...)
+			if err != tt.wantErr {
+				t.Errorf("NewChooser() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestChooser_Pick(t *testing.T) {
+	unit := new(big.Int).Lsh(big.NewInt(1), 100)
+	chooser, err := NewChooser(
+		NewChoice("a", unit),
+		NewChoice("never", big.NewInt(0)),
+		NewChoice("b", new(big.Int).Mul(unit, big.NewInt(3))),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := new(big.Int).Mul(unit, big.NewInt(4)); chooser.Total().Cmp(want) != 0 {
+		t.Errorf("Total() = %v, want %v", chooser.Total(), want)
+	}
+
+	const n = 100_000
+	counts := make(map[string]int)
+	for i := 0; i < n; i++ {
+		counts[chooser.Pick()]++
+	}
+	if counts["never"] != 0 {
+		t.Errorf("picked zero weight choice %d times", counts["never"])
+	}
+	if got := float64(counts["b"]) / n; math.Abs(got-0.75) > 0.01 {
+		t.Errorf("frequency of b = %.3f, want 0.75", got)
+	}
+}
+
+func TestRandBelow(t *testing.T) {
+	for _, n := range []int64{1, 2, 5, 64, 1000} {
+		seen := make(map[int64]bool)
+		for i := 0; i < 100*int(n); i++ {
+			r := randBelow(big.NewInt(n))
+			if r.Sign() < 0 || r.Cmp(big.NewInt(n)) >= 0 {
+				t.Fatalf("randBelow(%d) = %v, out of range", n, r)
+			}
+			seen[r.Int64()] = true
+		}
+		if len(seen) != int(n) {
+			t.Errorf("randBelow(%d) produced %d distinct values, want %d", n, len(seen), n)
+		}
+	}
+}
+
+func ExampleNewChooser() {
+	balance, _ := new(big.Int).SetString("340282366920938463463374607431768211456", 10)
+	chooser, _ := NewChooser(
+		NewChoice("whale", balance),
+		NewChoice("minnow", big.NewInt(0)),
+	)
+	fmt.Println(chooser.Pick())
+	// Output: whale
+}
@@ -0,0 +1,107 @@
+package bigweight
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// ErrInvalidWeight is returned when a weight cannot be represented exactly as
+// a rational number, such as an infinite float or malformed decimal string.
+var ErrInvalidWeight = errors.New("bigweight: invalid weight")
+
+// FloatChoice is an item with an arbitrary precision floating point weight.
+type FloatChoice[T any] struct {
+	Item   T
+	Weight *big.Float
+}
+
+// DecimalChoice is an item with a weight given as a decimal string, such as
+// "0.000000125" or "1.25e-7", or as a fraction such as "2/7".
+type DecimalChoice[T any] struct {
+	Item   T
+	Weight string
+}
+
+// RatChoice is an item with a rational weight.
+type RatChoice[T any] struct {
+	Item   T
+	Weight *big.Rat
+}
+
+// NewChooserFromRats initializes a new Chooser for picking from choices with
+// rational weights, normalized to a common denominator so the relative
+// probabilities of the choices are preserved exactly. Choices with a nil, zero
+// or negative weight are ignored.
+func NewChooserFromRats[T any](choices ...RatChoice[T]) (*Chooser[T], error) {
+	items := make([]T, len(choices))
+	weights := make([]*big.Rat, len(choices))
+	for i, c := range choices {
+		items[i], weights[i] = c.Item, c.Weight
+	}
+	return newChooserFromRats(items, weights)
+}
+
+// NewChooserFromFloats initializes a new Chooser for picking from choices with
+// floating point weights. Every finite big.Float is exactly a rational number,
+// so the relative probabilities of the choices are preserved exactly. Choices
+// with a nil, zero or negative weight are ignored.
+func NewChooserFromFloats[T any](choices ...FloatChoice[T]) (*Chooser[T], error) {
+	items := make([]T, len(choices))
+	weights := make([]*big.Rat, len(choices))
+	for i, c := range choices {
+		items[i] = c.Item
+		if c.Weight == nil {
+			continue
+		}
+		if c.Weight.IsInf() {
+			return nil, fmt.Errorf("%w: choice %d is %v", ErrInvalidWeight, i, c.Weight)
+		}
+		weights[i], _ = c.Weight.Rat(nil)
+	}
+	return newChooserFromRats(items, weights)
+}
+
+// NewChooserFromDecimals initializes a new Chooser for picking from choices with
+// decimal string weights. Weights are parsed exactly, without the rounding of
+// binary floating point, so the relative probabilities of the choices are
+// preserved exactly. Choices with a zero or negative weight are ignored.
+func NewChooserFromDecimals[T any](choices ...DecimalChoice[T]) (*Chooser[T], error) {
+	items := make([]T, len(choices))
+	weights := make([]*big.Rat, len(choices))
+	for i, c := range choices {
+		items[i] = c.Item
+		w, ok := new(big.Rat).SetString(c.Weight)
+		if !ok {
+			return nil, fmt.Errorf("%w: choice %d is %q", ErrInvalidWeight, i, c.Weight)
+		}
+		weights[i] = w
+	}
+	return newChooserFromRats(items, weights)
+}
+
+// newChooserFromRats builds a Chooser from rational weights by scaling each by
+// the least common multiple of their denominators, which yields the smallest
+// integer weights in exactly the same proportions. Nil weights are ignored.
+func newChooserFromRats[T any](items []T, weights []*big.Rat) (*Chooser[T], error) {
+	lcm := big.NewInt(1)
+	gcd := new(big.Int)
+	for _, w := range weights {
+		if w == nil || w.Sign() <= 0 {
+			continue
+		}
+		gcd.GCD(nil, nil, lcm, w.Denom())
+		lcm.Div(lcm, gcd).Mul(lcm, w.Denom())
+	}
+
+	choices := make([]Choice[T], 0, len(items))
+	for i, w := range weights {
+		if w == nil || w.Sign() <= 0 {
+			continue
+		}
+		scaled := new(big.Int).Div(lcm, w.Denom())
+		scaled.Mul(scaled, w.Num())
+		choices = append(choices, NewChoice(items[i], scaled))
+	}
+	return NewChooser(choices...)
+}
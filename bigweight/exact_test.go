@@ -0,0 +1,98 @@
+package bigweight
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"testing"
+)
+
+// weights returns the integer weights of the choices the chooser can pick.
+func weights[T any](c *Chooser[T]) []int64 {
+	ws := make([]int64, len(c.totals))
+	prev := new(big.Int)
+	for i, total := range c.totals {
+		ws[i] = new(big.Int).Sub(total, prev).Int64()
+		prev = total
+	}
+	return ws
+}
+
+func TestNewChooserFromDecimals(t *testing.T) {
+	tests := []struct {
+		name    string
+		weights []string
+		want    []int64
+		wantErr error
+	}{
+		{name: "tiny", weights: []string{"0.000000125", "0.000000375"}, want: []int64{1, 3}},
+		{name: "mixed scales", weights: []string{"1.5", "0.25", "2"}, want: []int64{6, 1, 8}},
+		{name: "exponent", weights: []string{"1.25e-7", "0", "-1", "2.5e-7"}, want: []int64{1, 2}},
+		{name: "fractions", weights: []string{"1/3", "2/7", "0.5"}, want: []int64{14, 12, 21}},
+		{name: "malformed", weights: []string{"1", "one"}, wantErr: ErrInvalidWeight},
+		{name: "no positive weights", weights: []string{"0", "-0.5"}, wantErr: ErrNoValidChoices},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			choices := make([]DecimalChoice[int], len(tt.weights))
+			for i, w := range tt.weights {
+				choices[i] = DecimalChoice[int]{Item: i, Weight: w}
+			}
+			c, err := NewChooserFromDecimals(choices...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewChooserFromDecimals() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got := weights(c); fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("scaled weights = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewChooserFromRats(t *testing.T) {
+	c, err := NewChooserFromRats(
+		RatChoice[string]{Item: "a", Weight: big.NewRat(1, 3)},
+		RatChoice[string]{Item: "b", Weight: big.NewRat(2, 7)},
+		RatChoice[string]{Item: "nil", Weight: nil},
+		RatChoice[string]{Item: "c", Weight: big.NewRat(-1, 2)},
+		RatChoice[string]{Item: "d", Weight: big.NewRat(4, 6)},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := weights(c); fmt.Sprint(got) != "[7 6 14]" {
+		t.Errorf("scaled weights = %v, want [7 6 14]", got)
+	}
+}
+
+func TestNewChooserFromFloats(t *testing.T) {
+	c, err := NewChooserFromFloats(
+		FloatChoice[string]{Item: "a", Weight: big.NewFloat(0.5)},
+		FloatChoice[string]{Item: "nil", Weight: nil},
+		FloatChoice[string]{Item: "b", Weight: big.NewFloat(0.25)},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := weights(c); fmt.Sprint(got) != "[2 1]" {
+		t.Errorf("scaled weights = %v, want [2 1]", got)
+	}
+
+	_, err = NewChooserFromFloats(FloatChoice[string]{Item: "inf", Weight: big.NewFloat(math.Inf(1))})
+	if !errors.Is(err, ErrInvalidWeight) {
+		t.Errorf("NewChooserFromFloats() error = %v, want %v", err, ErrInvalidWeight)
+	}
+}
+
+func ExampleNewChooserFromDecimals() {
+	chooser, _ := NewChooserFromDecimals(
+		DecimalChoice[string]{Item: "fee", Weight: "0.000000125"},
+		DecimalChoice[string]{Item: "principal", Weight: "0"},
+	)
+	fmt.Println(chooser.Pick())
+	// Output: fee
+}
@@ -0,0 +1,113 @@
+// Package chaos injects weighted random faults, such as added latency, errors
+// and dropped connections, for chaos testing of services.
+//
+// An Injector picks a Fault for each request in proportion to configured
+// weights, typically with a heavily weighted None fault so that most requests
+// proceed normally. Middleware applies faults to net/http handlers; see the
+// grpcchaos module for gRPC interceptors.
+package chaos
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/mroth/weightedrand/v2"
+	"github.com/mroth/weightedrand/v2/internal/constraints"
+)
+
+// Kind is the kind of a Fault.
+type Kind int
+
+// Kinds of Fault.
+const (
+	None    Kind = iota // proceed normally
+	Latency             // delay by Fault.Delay, then proceed normally
+	Error               // fail with an error instead of proceeding
+	Drop                // abort the request without any response
+)
+
+func (k Kind) String() string {
+	switch k {
+	case None:
+		return "none"
+	case Latency:
+		return "latency"
+	case Error:
+		return "error"
+	case Drop:
+		return "drop"
+	}
+	return "unknown"
+}
+
+// A Fault is a failure mode to inject.
+type Fault struct {
+	Kind Kind
+	// Delay is the latency added by a Latency fault.
+	Delay time.Duration
+	// StatusCode is the HTTP status of an Error fault, or 503 Service
+	// Unavailable if zero.
+	StatusCode int
+	// Err is the error of an Error fault, for protocols with error values.
+	Err error
+}
+
+// An Injector picks weighted random Faults. It is safe for concurrent usage.
+type Injector struct {
+	pick func() Fault
+}
+
+// New initializes an Injector picking among faults in proportion to their
+// weights.
+func New[W constraints.Integer](faults ...weightedrand.Choice[Fault, W]) (*Injector, error) {
+	c, err := weightedrand.NewChooser(faults...)
+	if err != nil {
+		return nil, err
+	}
+	return &Injector{pick: c.Pick}, nil
+}
+
+// Pick returns a weighted random Fault.
+func (i *Injector) Pick() Fault { return i.pick() }
+
+// Sleep waits for the Delay of a Latency fault, returning early with the error
+// of ctx if it is done first. It returns immediately for other kinds.
+func (f Fault) Sleep(ctx context.Context) error {
+	if f.Kind != Latency || f.Delay <= 0 {
+		return nil
+	}
+	t := time.NewTimer(f.Delay)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Middleware returns a handler which applies a weighted random Fault to each
+// request before passing it to next. Error faults respond with their status
+// code, and Drop faults abort the response as if the connection was lost.
+func (i *Injector) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f := i.Pick()
+		switch f.Kind {
+		case Latency:
+			if f.Sleep(r.Context()) != nil {
+				return // client went away
+			}
+		case Error:
+			code := f.StatusCode
+			if code == 0 {
+				code = http.StatusServiceUnavailable
+			}
+			http.Error(w, http.StatusText(code), code)
+			return
+		case Drop:
+			panic(http.ErrAbortHandler)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
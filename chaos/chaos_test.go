@@ -0,0 +1,90 @@
+package chaos
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mroth/weightedrand/v2"
+)
+
+var ok = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	fmt.Fprint(w, "ok")
+})
+
+func ExampleInjector_Middleware() {
+	inj, _ := New(
+		weightedrand.NewChoice(Fault{Kind: None}, 0),
+		weightedrand.NewChoice(Fault{Kind: Error, StatusCode: http.StatusTeapot}, 1),
+	)
+	rec := httptest.NewRecorder()
+	inj.Middleware(ok).ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	fmt.Println(rec.Code)
+	//Output: 418
+}
+
+func TestInjector_Middleware(t *testing.T) {
+	tests := []struct {
+		fault    Fault
+		wantCode int
+		wantBody string
+	}{
+		{fault: Fault{Kind: None}, wantCode: 200, wantBody: "ok"},
+		{fault: Fault{Kind: Latency, Delay: time.Millisecond}, wantCode: 200, wantBody: "ok"},
+		{fault: Fault{Kind: Error}, wantCode: 503, wantBody: "Service Unavailable\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fault.Kind.String(), func(t *testing.T) {
+			inj, err := New(weightedrand.NewChoice(tt.fault, 1))
+			if err != nil {
+				t.Fatal(err)
+			}
+			rec := httptest.NewRecorder()
+			inj.Middleware(ok).ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+			if rec.Code != tt.wantCode || rec.Body.String() != tt.wantBody {
+				t.Errorf("response = %d %q, want %d %q", rec.Code, rec.Body.String(), tt.wantCode, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestInjector_Middleware_drop(t *testing.T) {
+	inj, _ := New(weightedrand.NewChoice(Fault{Kind: Drop}, 1))
+	srv := httptest.NewServer(inj.Middleware(ok))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	if err == nil {
+		resp.Body.Close()
+		t.Fatalf("GET succeeded with status %d, want connection dropped", resp.StatusCode)
+	}
+}
+
+func TestFault_Sleep(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	f := Fault{Kind: Latency, Delay: time.Hour}
+	if err := f.Sleep(ctx); err != context.Canceled {
+		t.Errorf("Sleep() error = %v, want %v", err, context.Canceled)
+	}
+	if err := (Fault{Kind: Error, Delay: time.Hour}).Sleep(ctx); err != nil {
+		t.Errorf("Sleep() for non-latency fault error = %v, want nil", err)
+	}
+}
+
+func TestInjector_Pick(t *testing.T) {
+	inj, _ := New(
+		weightedrand.NewChoice(Fault{Kind: None}, 9),
+		weightedrand.NewChoice(Fault{Kind: Drop}, 1),
+	)
+	counts := make(map[Kind]int)
+	for i := 0; i < 10000; i++ {
+		counts[inj.Pick().Kind]++
+	}
+	if counts[Drop] < 800 || counts[Drop] > 1200 {
+		t.Errorf("picked %d drops in 10000, want about 1000", counts[Drop])
+	}
+}
@@ -0,0 +1,60 @@
+// Package grpcchaos provides gRPC server interceptors injecting the weighted
+// random faults of a chaos.Injector.
+//
+// It is a separate module so that the chaos package, like the rest of
+// weightedrand, does not depend on gRPC.
+package grpcchaos
+
+import (
+	"context"
+
+	"github.com/mroth/weightedrand/v2/chaos"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// apply applies a weighted random fault from inj, returning an error if the
+// call should fail rather than proceed. Since a server cannot drop a single
+// call's connection, Drop faults fail with codes.Unavailable, as would be seen
+// by a client whose connection was lost.
+func apply(ctx context.Context, inj *chaos.Injector) error {
+	f := inj.Pick()
+	switch f.Kind {
+	case chaos.Latency:
+		if err := f.Sleep(ctx); err != nil {
+			return status.FromContextError(err).Err()
+		}
+	case chaos.Error:
+		if f.Err != nil {
+			return f.Err
+		}
+		return status.Error(codes.Unavailable, "chaos: injected error")
+	case chaos.Drop:
+		return status.Error(codes.Unavailable, "chaos: injected drop")
+	}
+	return nil
+}
+
+// UnaryServerInterceptor returns an interceptor applying a weighted random
+// fault from inj to each unary call. Error faults fail with their Err, which
+// should be a gRPC status error, or codes.Unavailable if it is nil.
+func UnaryServerInterceptor(inj *chaos.Injector) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := apply(ctx, inj); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor is like UnaryServerInterceptor, applying faults to
+// each streaming call when it starts.
+func StreamServerInterceptor(inj *chaos.Injector) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := apply(ss.Context(), inj); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
@@ -0,0 +1,67 @@
+package grpcchaos
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mroth/weightedrand/v2"
+	"github.com/mroth/weightedrand/v2/chaos"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return "ok", nil }
+	tests := []struct {
+		fault    chaos.Fault
+		wantCode codes.Code
+	}{
+		{fault: chaos.Fault{Kind: chaos.None}, wantCode: codes.OK},
+		{fault: chaos.Fault{Kind: chaos.Latency, Delay: time.Millisecond}, wantCode: codes.OK},
+		{fault: chaos.Fault{Kind: chaos.Error}, wantCode: codes.Unavailable},
+		{fault: chaos.Fault{Kind: chaos.Error, Err: status.Error(codes.Internal, "boom")}, wantCode: codes.Internal},
+		{fault: chaos.Fault{Kind: chaos.Drop}, wantCode: codes.Unavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fault.Kind.String(), func(t *testing.T) {
+			inj, err := chaos.New(weightedrand.NewChoice(tt.fault, 1))
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := UnaryServerInterceptor(inj)(context.Background(), nil, &grpc.UnaryServerInfo{}, handler)
+			if got := status.Code(err); got != tt.wantCode {
+				t.Errorf("code = %v, want %v", got, tt.wantCode)
+			}
+			if err == nil && resp != "ok" {
+				t.Errorf("resp = %v, want ok", resp)
+			}
+		})
+	}
+}
+
+func TestUnaryServerInterceptor_deadline(t *testing.T) {
+	inj, _ := chaos.New(weightedrand.NewChoice(chaos.Fault{Kind: chaos.Latency, Delay: time.Hour}, 1))
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return "ok", nil }
+	_, err := UnaryServerInterceptor(inj)(ctx, nil, &grpc.UnaryServerInfo{}, handler)
+	if got := status.Code(err); got != codes.DeadlineExceeded {
+		t.Errorf("code = %v, want %v", got, codes.DeadlineExceeded)
+	}
+}
+
+type fakeStream struct{ grpc.ServerStream }
+
+func (fakeStream) Context() context.Context { return context.Background() }
+
+func TestStreamServerInterceptor(t *testing.T) {
+	inj, _ := chaos.New(weightedrand.NewChoice(chaos.Fault{Kind: chaos.Drop}, 1))
+	called := false
+	handler := func(srv interface{}, ss grpc.ServerStream) error { called = true; return nil }
+	err := StreamServerInterceptor(inj)(nil, fakeStream{}, &grpc.StreamServerInfo{}, handler)
+	if status.Code(err) != codes.Unavailable || called {
+		t.Errorf("err = %v, handler called = %v; want Unavailable without calling handler", err, called)
+	}
+}
@@ -0,0 +1,6 @@
+// Package example holds choosers generated by choosergen, to test the
+// generated code.
+package example
+
+//go:generate go run ../.. -type string -weight uint32 -name StringChooser
+//go:generate go run ../.. -type netip.Addr -weight int64 -import net/netip
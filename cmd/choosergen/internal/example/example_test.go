@@ -0,0 +1,113 @@
+package example
+
+import (
+	"math"
+	"math/rand"
+	"net/netip"
+	"testing"
+
+	"github.com/mroth/weightedrand/v2"
+)
+
+func TestStringChooser(t *testing.T) {
+	c, err := NewStringChooser(
+		weightedrand.NewChoice("a", uint32(1)),
+		weightedrand.NewChoice("never", uint32(0)),
+		weightedrand.NewChoice("b", uint32(2)),
+		weightedrand.NewChoice("c", uint32(5)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const n = 100000
+	counts := make(map[string]int)
+	for i := 0; i < n; i++ {
+		counts[c.Pick()]++
+	}
+	rs := rand.New(rand.NewSource(1))
+	for i := 0; i < n; i++ {
+		counts[c.PickSource(rs)]++
+	}
+	if counts["never"] != 0 {
+		t.Errorf("picked zero weight choice %d times", counts["never"])
+	}
+	for item, want := range map[string]float64{"a": 1.0 / 8, "b": 2.0 / 8, "c": 5.0 / 8} {
+		if got := float64(counts[item]) / (2 * n); math.Abs(got-want) > 0.01 {
+			t.Errorf("frequency of %s = %.3f, want %.3f", item, got, want)
+		}
+	}
+
+	if allocs := testing.AllocsPerRun(100, func() { c.Pick() }); allocs != 0 {
+		t.Errorf("Pick() allocates %v times, want 0", allocs)
+	}
+}
+
+func TestStringChooser_search(t *testing.T) {
+	// Every total in tables of various lengths must be found.
+	for n := 1; n <= 33; n++ {
+		choices := make([]weightedrand.Choice[string, uint32], n)
+		for i := range choices {
+			choices[i] = weightedrand.NewChoice("", uint32(i%3+1))
+		}
+		c, err := NewStringChooser(choices...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, total := range c.totals {
+			for x := total - int(choices[i].Weight) + 1; x <= total; x++ {
+				if got := c.search(x); got != i {
+					t.Fatalf("n=%d: search(%d) = %d, want %d", n, x, got, i)
+				}
+			}
+		}
+	}
+}
+
+func TestNewAddrChooser(t *testing.T) {
+	addr := netip.MustParseAddr("10.0.0.1")
+	c, err := NewAddrChooser(
+		weightedrand.NewChoice(addr, int64(3)),
+		weightedrand.NewChoice(netip.MustParseAddr("10.0.0.2"), int64(-1)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := c.Pick(); got != addr {
+		t.Errorf("Pick() = %v, want %v", got, addr)
+	}
+
+	if _, err := NewAddrChooser(); err != errAddrChooserNoValidChoices {
+		t.Errorf("NewAddrChooser() error = %v, want %v", err, errAddrChooserNoValidChoices)
+	}
+	_, err = NewAddrChooser(
+		weightedrand.NewChoice(addr, int64(math.MaxInt64/2+1)),
+		weightedrand.NewChoice(addr, int64(math.MaxInt64/2+1)),
+	)
+	if err != errAddrChooserWeightOverflow {
+		t.Errorf("NewAddrChooser() error = %v, want %v", err, errAddrChooserWeightOverflow)
+	}
+}
+
+func benchmarkChoices(n int) []weightedrand.Choice[string, uint32] {
+	choices := make([]weightedrand.Choice[string, uint32], n)
+	for i := range choices {
+		choices[i] = weightedrand.NewChoice("", uint32(rand.Intn(10)+1))
+	}
+	return choices
+}
+
+func BenchmarkStringChooser_Pick(b *testing.B) {
+	c, _ := NewStringChooser(benchmarkChoices(1000)...)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		c.Pick()
+	}
+}
+
+func BenchmarkChooser_Pick(b *testing.B) {
+	c, _ := weightedrand.NewChooser(benchmarkChoices(1000)...)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		c.Pick()
+	}
+}
@@ -0,0 +1,213 @@
+// Command choosergen generates a weighted random chooser specialized for a
+// single item and weight type, for hot paths whose profiles show the cost of
+// generic dispatch in weightedrand.Chooser. The generated chooser holds its
+// items and cumulative weights in parallel slices, searches them inline with
+// the branchless search of weightedrand, and does not allocate when picking.
+//
+// It is intended for use with go generate, for example:
+//
+//	//go:generate go run github.com/mroth/weightedrand/v2/cmd/choosergen -type Backend -weight uint32 -name BackendChooser
+//
+// Usage:
+//
+//	choosergen -type T [-weight W] [-name Name] [-import path,...] [-package pkg] [-o file]
+//
+// The type T may be qualified by a package imported with -import, such as
+// -type netip.Addr -import net/netip. The name defaults to T with "Chooser"
+// appended, and the package to $GOPACKAGE as set by go generate. The output
+// file defaults to the name in lower case with a .go extension.
+//
+// The generated constructor takes weightedrand.Choice values, and as with
+// weightedrand.NewChooser, ignores choices with a weight < 1 and returns an
+// error if there are none, or if the sum of the weights would overflow an int.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/format"
+	"go/token"
+	"os"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+func main() {
+	var cfg config
+	flag.StringVar(&cfg.Type, "type", "", "item `type` of the chooser (required)")
+	flag.StringVar(&cfg.Weight, "weight", "int", "integer weight `type` of the chooser")
+	flag.StringVar(&cfg.Name, "name", "", "`name` of the generated chooser type (default T with Chooser appended)")
+	imports := flag.String("import", "", "comma separated import `paths` for qualified item types")
+	flag.StringVar(&cfg.Package, "package", os.Getenv("GOPACKAGE"), "`package` of the generated file")
+	output := flag.String("o", "", "output `file` (default name in lower case with .go appended)")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: choosergen -type T [-weight W] [-name Name] [-import path,...] [-package pkg] [-o file]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if *imports != "" {
+		cfg.Imports = strings.Split(*imports, ",")
+	}
+	cfg.Args = strings.Join(os.Args[1:], " ")
+
+	src, err := generate(cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "choosergen:", err)
+		os.Exit(1)
+	}
+	if *output == "" {
+		*output = strings.ToLower(cfg.name()) + ".go"
+	}
+	if err := os.WriteFile(*output, src, 0o644); err != nil {
+		fmt.Fprintln(os.Stderr, "choosergen:", err)
+		os.Exit(1)
+	}
+}
+
+// config describes a chooser to generate.
+type config struct {
+	Type    string
+	Weight  string
+	Name    string
+	Imports []string
+	Package string
+	Std     []string // standard library imports, grouped separately
+	Args    string   // command line arguments, recorded in the generated header
+}
+
+// name returns the name of the chooser type, defaulting to the unqualified
+// item type with Chooser appended.
+func (cfg config) name() string {
+	if cfg.Name != "" {
+		return cfg.Name
+	}
+	t := cfg.Type[strings.LastIndex(cfg.Type, ".")+1:]
+	return strings.ToUpper(t[:1]) + t[1:] + "Chooser"
+}
+
+var integerTypes = map[string]bool{
+	"int": true, "int8": true, "int16": true, "int32": true, "int64": true,
+	"uint": true, "uint8": true, "uint16": true, "uint32": true, "uint64": true, "uintptr": true,
+}
+
+// generate returns the formatted source of the chooser described by cfg.
+func generate(cfg config) ([]byte, error) {
+	switch {
+	case cfg.Type == "":
+		return nil, errors.New("-type is required")
+	case cfg.Package == "" || !token.IsIdentifier(cfg.Package):
+		return nil, fmt.Errorf("invalid package name %q, set -package", cfg.Package)
+	case !integerTypes[cfg.Weight]:
+		return nil, fmt.Errorf("weight type %q is not a predeclared integer type", cfg.Weight)
+	}
+	cfg.Name = cfg.name()
+	if !token.IsIdentifier(cfg.Name) {
+		return nil, fmt.Errorf("invalid chooser name %q, set -name", cfg.Name)
+	}
+	imports := cfg.Imports
+	cfg.Std, cfg.Imports = []string{"errors", "math/rand"}, []string{"github.com/mroth/weightedrand/v2"}
+	for _, path := range imports {
+		if strings.Contains(strings.SplitN(path, "/", 2)[0], ".") {
+			cfg.Imports = append(cfg.Imports, path)
+		} else {
+			cfg.Std = append(cfg.Std, path)
+		}
+	}
+	sort.Strings(cfg.Std)
+	sort.Strings(cfg.Imports)
+
+	var buf bytes.Buffer
+	if err := chooserTemplate.Execute(&buf, cfg); err != nil {
+		return nil, err
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("generated invalid code: %w", err)
+	}
+	return src, nil
+}
+
+var chooserTemplate = template.Must(template.New("chooser").Parse(`// Code generated by choosergen {{.Args}}; DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+{{- range .Std}}
+	"{{.}}"
+{{- end}}
+{{range .Imports}}
+	"{{.}}"
+{{- end}}
+)
+
+{{$err := print "err" .Name}}
+// Possible errors returned by New{{.Name}}, as for weightedrand.NewChooser.
+var (
+	{{$err}}WeightOverflow = errors.New("sum of Choice Weights exceeds max int")
+	{{$err}}NoValidChoices = errors.New("zero Choices with Weight >= 1")
+)
+
+// {{.Name}} is a weighted random chooser of {{.Type}} items with {{.Weight}}
+// weights, specialized from weightedrand.Chooser. It is safe for concurrent
+// usage.
+type {{.Name}} struct {
+	items  []{{.Type}}
+	totals []int // cumulative weights of items
+	max    int
+}
+
+// New{{.Name}} initializes a new {{.Name}} for picking from the provided
+// choices. Choices with a weight < 1 are ignored.
+func New{{.Name}}(choices ...weightedrand.Choice[{{.Type}}, {{.Weight}}]) (*{{.Name}}, error) {
+	const maxInt = int(^uint(0) >> 1)
+	c := &{{.Name}}{
+		items:  make([]{{.Type}}, 0, len(choices)),
+		totals: make([]int, 0, len(choices)),
+	}
+	for _, choice := range choices {
+		if choice.Weight < 1 {
+			continue
+		}
+		if uint64(choice.Weight) >= uint64(maxInt) || maxInt-c.max <= int(choice.Weight) {
+			return nil, {{$err}}WeightOverflow
+		}
+		c.max += int(choice.Weight)
+		c.items = append(c.items, choice.Item)
+		c.totals = append(c.totals, c.max)
+	}
+	if c.max < 1 {
+		return nil, {{$err}}NoValidChoices
+	}
+	return c, nil
+}
+
+// Pick returns a single weighted random item, using global rand as the source
+// of randomness.
+func (c *{{.Name}}) Pick() {{.Type}} {
+	return c.items[c.search(rand.Intn(c.max)+1)]
+}
+
+// PickSource returns a single weighted random item, using rs as the source of
+// randomness. It is the responsibility of the caller to ensure rs is free from
+// thread safety issues.
+func (c *{{.Name}}) PickSource(rs *rand.Rand) {{.Type}} {
+	return c.items[c.search(rs.Intn(c.max)+1)]
+}
+
+// search returns the index of the first total >= x, by a branchless binary
+// search.
+func (c *{{.Name}}) search(x int) int {
+	const intSize = 32 << (^uint(0) >> 63)
+	a := c.totals
+	base, n := 0, len(a)
+	for n > 1 {
+		half := n >> 1
+		base += half & ((a[base+half-1] - x) >> (intSize - 1))
+		n -= half
+	}
+	return base + 1&((a[base]-x)>>(intSize-1))
+}
+`))
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     config
+		wantErr string
+	}{
+		{name: "valid", cfg: config{Type: "string", Weight: "int", Package: "p"}},
+		{name: "slice type", cfg: config{Type: "[]byte", Weight: "uint8", Name: "BytesChooser", Package: "p"}},
+		{name: "missing type", cfg: config{Weight: "int", Package: "p"}, wantErr: "-type is required"},
+		{name: "missing package", cfg: config{Type: "string", Weight: "int"}, wantErr: `invalid package name ""`},
+		{name: "float weight", cfg: config{Type: "string", Weight: "float64", Package: "p"}, wantErr: `weight type "float64"`},
+		{name: "slice type without name", cfg: config{Type: "[]byte", Weight: "int", Package: "p"}, wantErr: "set -name"},
+		{name: "invalid type", cfg: config{Type: "map[", Weight: "int", Name: "C", Package: "p"}, wantErr: "generated invalid code"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := generate(tt.cfg)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("generate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("generate() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestGenerate_upToDate checks that the generated code tested by the example
+// package matches the current output of the generator.
+func TestGenerate_upToDate(t *testing.T) {
+	tests := []struct {
+		file string
+		cfg  config
+	}{
+		{
+			file: "stringchooser.go",
+			cfg:  config{Type: "string", Weight: "uint32", Name: "StringChooser", Args: "-type string -weight uint32 -name StringChooser"},
+		},
+		{
+			file: "addrchooser.go",
+			cfg:  config{Type: "netip.Addr", Weight: "int64", Imports: []string{"net/netip"}, Args: "-type netip.Addr -weight int64 -import net/netip"},
+		},
+	}
+	for _, tt := range tests {
+		tt.cfg.Package = "example"
+		want, err := generate(tt.cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := os.ReadFile(filepath.Join("internal", "example", tt.file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("%s is out of date, run go generate ./internal/example", tt.file)
+		}
+	}
+}
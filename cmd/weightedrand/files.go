@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/mroth/weightedrand/v2/filepick"
+)
+
+func filesMain(args []string) {
+	fset := flag.NewFlagSet("files", flag.ExitOnError)
+	n := fset.Int("n", 1, "number of files to print")
+	by := fset.String("by", "uniform", "weight files by `uniform|size|age|recent`")
+	halfLife := fset.Duration("half-life", 24*time.Hour, "half life of the weights of files with -by recent")
+	sidecar := fset.String("weights", "", "weight files as listed in `file`, rather than -by")
+	fset.Usage = func() {
+		fmt.Fprintf(fset.Output(), "usage: weightedrand files [-n count] [-by uniform|size|age|recent] [-half-life duration] [-weights file] dir\n")
+		fset.PrintDefaults()
+	}
+	fset.Parse(args)
+	if fset.NArg() != 1 {
+		fset.Usage()
+		os.Exit(2)
+	}
+
+	err := func() error {
+		weight, err := weightFunc(*by, *halfLife, *sidecar, time.Now())
+		if err != nil {
+			return err
+		}
+		return runFiles(fset.Arg(0), os.Stdout, *n, weight)
+	}()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "weightedrand:", err)
+		os.Exit(1)
+	}
+}
+
+// weightFunc returns the filepick.WeightFunc selected by the flags of the
+// files verb.
+func weightFunc(by string, halfLife time.Duration, sidecar string, now time.Time) (filepick.WeightFunc, error) {
+	if sidecar != "" {
+		f, err := os.Open(sidecar)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		return filepick.Sidecar(f)
+	}
+	switch by {
+	case "uniform":
+		return filepick.Uniform, nil
+	case "size":
+		return filepick.Size, nil
+	case "age":
+		return filepick.Age(now), nil
+	case "recent":
+		if halfLife <= 0 {
+			return nil, fmt.Errorf("half life must be positive")
+		}
+		return filepick.Recency(now, halfLife), nil
+	}
+	return nil, fmt.Errorf("unknown weighting %q", by)
+}
+
+// runFiles prints the paths of n files picked from under dir by weight.
+func runFiles(dir string, stdout io.Writer, n int, weight filepick.WeightFunc) error {
+	names, err := filepick.Pick(os.DirFS(dir), ".", n, weight)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(stdout)
+	for _, name := range names {
+		fmt.Fprintln(w, filepath.Join(dir, filepath.FromSlash(name)))
+	}
+	return w.Flush()
+}
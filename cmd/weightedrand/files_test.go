@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRunFiles(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"a.txt":     "a",
+		"empty.txt": "",
+		"sub/b.txt": "bb",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	weight, err := weightFunc("size", 0, "", time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	if err := runFiles(dir, &out, 10, weight); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	sort.Strings(lines)
+	want := []string{filepath.Join(dir, "a.txt"), filepath.Join(dir, "sub", "b.txt")}
+	if strings.Join(lines, ",") != strings.Join(want, ",") {
+		t.Errorf("got %q, want files with a positive size %q", lines, want)
+	}
+
+	sidecar := filepath.Join(t.TempDir(), "weights.tsv")
+	if err := os.WriteFile(sidecar, []byte("1\tempty.txt\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if weight, err = weightFunc("size", 0, sidecar, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	out.Reset()
+	if err := runFiles(dir, &out, 10, weight); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := strings.TrimSpace(out.String()), filepath.Join(dir, "empty.txt"); got != want {
+		t.Errorf("got %q with sidecar, want %q", got, want)
+	}
+}
+
+func TestWeightFunc(t *testing.T) {
+	for _, by := range []string{"uniform", "size", "age", "recent"} {
+		if _, err := weightFunc(by, time.Hour, "", time.Now()); err != nil {
+			t.Errorf("weightFunc(%q) error = %v", by, err)
+		}
+	}
+	if _, err := weightFunc("recent", 0, "", time.Now()); err == nil {
+		t.Error("expected error with zero half life")
+	}
+	if _, err := weightFunc("name", time.Hour, "", time.Now()); err == nil {
+		t.Error("expected error with unknown weighting")
+	}
+	if _, err := weightFunc("size", time.Hour, filepath.Join(t.TempDir(), "missing"), time.Now()); err == nil {
+		t.Error("expected error with missing sidecar")
+	}
+}
@@ -0,0 +1,182 @@
+// Command weightedrand prints weighted random selections from a list of
+// values, for use in shell scripts.
+//
+// Input is read from the named file, or standard input if none is given, and
+// consists of one choice per line, formatted as a non-negative integer weight
+// and a value separated by a tab. Blank lines and lines beginning with # are
+// ignored. Weights may instead all be percentages summing to exactly 100%,
+// such as 99.5% and 0.5%.
+//
+// Usage:
+//
+//	weightedrand [-n count] [-unique] [file]
+//
+// By default a single value is selected. With -unique, values are selected
+// without replacement, so no line is printed more than once, and fewer than
+// count values are printed if there are not enough lines with a positive
+// weight.
+//
+// The files verb instead prints the paths of files picked without replacement
+// from under a directory, weighted by their size or age, or by a sidecar file
+// of weights in the same format as the input above, with paths relative to the
+// directory:
+//
+//	weightedrand files [-n count] [-by uniform|size|age|recent] [-half-life duration] [-weights file] dir
+//
+// To read choices from a file named files, pass it as ./files.
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/mroth/weightedrand/v2"
+	"github.com/mroth/weightedrand/v2/dynamic"
+	"github.com/mroth/weightedrand/v2/internal/percent"
+)
+
+func main() {
+	if len(os.Args) > 1 && os.Args[1] == "files" {
+		filesMain(os.Args[2:])
+		return
+	}
+
+	n := flag.Int("n", 1, "number of selections to print")
+	unique := flag.Bool("unique", false, "select without replacement")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: weightedrand [-n count] [-unique] [file]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if err := run(flag.Args(), os.Stdin, os.Stdout, *n, *unique); err != nil {
+		fmt.Fprintln(os.Stderr, "weightedrand:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdin io.Reader, stdout io.Writer, n int, unique bool) error {
+	in := stdin
+	switch len(args) {
+	case 0:
+	case 1:
+		f, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	default:
+		return errors.New("too many arguments")
+	}
+
+	choices, err := parse(in)
+	if err != nil {
+		return err
+	}
+	values, err := sample(choices, n, unique)
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(stdout)
+	for _, v := range values {
+		fmt.Fprintln(w, v)
+	}
+	return w.Flush()
+}
+
+// parse reads "weight<TAB>value" lines from r.
+func parse(r io.Reader) ([]weightedrand.Choice[string, uint64], error) {
+	var choices []weightedrand.Choice[string, uint64]
+	var percents []percent.Percent
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if strings.TrimSpace(text) == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		weight, value, ok := strings.Cut(text, "\t")
+		if !ok {
+			return nil, fmt.Errorf("line %d: missing tab separating weight and value", line)
+		}
+		weight = strings.TrimSpace(weight)
+		if len(choices) > 0 && percent.Is(weight) != (len(percents) > 0) {
+			return nil, fmt.Errorf("line %d: weights mix percentages and integers", line)
+		}
+		if percent.Is(weight) {
+			p, err := percent.Parse(weight)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			percents = append(percents, p)
+			choices = append(choices, weightedrand.NewChoice(value, uint64(0)))
+			continue
+		}
+		w, err := strconv.ParseUint(weight, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid weight: %w", line, err)
+		}
+		choices = append(choices, weightedrand.NewChoice(value, w))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(percents) > 0 {
+		weights, err := percent.Weights(percents)
+		if err != nil {
+			return nil, err
+		}
+		for i, w := range weights {
+			choices[i].Weight = uint64(w)
+		}
+	}
+	return choices, nil
+}
+
+// sample returns n weighted random values from choices, without replacement
+// if unique is set.
+func sample(choices []weightedrand.Choice[string, uint64], n int, unique bool) ([]string, error) {
+	if n < 0 {
+		return nil, errors.New("count must not be negative")
+	}
+	if !unique {
+		chooser, err := weightedrand.NewChooser(choices...)
+		if err != nil {
+			return nil, err
+		}
+		values := make([]string, n)
+		for i := range values {
+			values[i] = chooser.Pick()
+		}
+		return values, nil
+	}
+
+	// Choices are keyed by line rather than value, so that duplicate values on
+	// separate lines are each eligible for selection.
+	indexed := make([]weightedrand.Choice[int, uint64], len(choices))
+	for i, c := range choices {
+		indexed[i] = weightedrand.NewChoice(i, c.Weight)
+	}
+	chooser, err := dynamic.NewChooser(indexed...)
+	if err != nil {
+		return nil, err
+	}
+	var values []string
+	for len(values) < n {
+		i, ok := chooser.Pick()
+		if !ok {
+			break
+		}
+		chooser.Remove(i)
+		values = append(values, choices[i].Item)
+	}
+	return values, nil
+}
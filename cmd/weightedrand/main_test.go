@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+const testInput = `# weight	value
+0	never
+1	a b
+
+3	c
+`
+
+func TestParse(t *testing.T) {
+	choices, err := parse(strings.NewReader(testInput))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(choices) != 3 || choices[1].Item != "a b" || choices[2].Weight != 3 {
+		t.Errorf("parse() = %v", choices)
+	}
+
+	choices, err = parse(strings.NewReader("99.5%\ta\n0.5%\tb\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if choices[0].Weight != 199 || choices[1].Weight != 1 {
+		t.Errorf("parse() = %v, want weights 199 and 1", choices)
+	}
+
+	for _, bad := range []string{"1 a\n", "x\ta\n", "-1\ta\n", "50%\ta\n40%\tb\n", "50%\ta\n50\tb\n", "1\ta\n5%\tb\n"} {
+		if _, err := parse(strings.NewReader(bad)); err == nil {
+			t.Errorf("parse(%q) succeeded, expected error", bad)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	var out bytes.Buffer
+	if err := run(nil, strings.NewReader(testInput), &out, 100, false); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 100 {
+		t.Fatalf("got %d lines, want 100", len(lines))
+	}
+	for _, l := range lines {
+		if l != "a b" && l != "c" {
+			t.Fatalf("unexpected output line %q", l)
+		}
+	}
+
+	if err := run(nil, strings.NewReader("0\tnever\n"), &out, 1, false); err == nil {
+		t.Error("expected error with no valid choices")
+	}
+	if err := run([]string{"a", "b"}, nil, &out, 1, false); err == nil {
+		t.Error("expected error with too many arguments")
+	}
+}
+
+func TestRun_unique(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "choices.tsv")
+	if err := os.WriteFile(path, []byte(testInput+"1\tc\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	if err := run([]string{path}, nil, &out, 10, true); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	sort.Strings(lines)
+	if got, want := strings.Join(lines, ","), "a b,c,c"; got != want {
+		t.Errorf("got %q, want each positively weighted line once: %q", got, want)
+	}
+}
@@ -0,0 +1,71 @@
+package weightedrand
+
+import (
+	"math/bits"
+	"math/rand"
+)
+
+// Flip returns true or false at random, with relative probabilities given by
+// trueWeight and falseWeight. This is the two-outcome special case of weighted
+// random selection, without the overhead of constructing a Chooser.
+//
+// As with Choices, weights < 1 can never be selected. Flip panics if neither
+// weight is >= 1.
+func Flip[W integer](trueWeight, falseWeight W) bool {
+	coin, err := NewCoin(trueWeight, falseWeight)
+	if err != nil {
+		panic("weightedrand: " + err.Error())
+	}
+	return coin.Flip()
+}
+
+// A Coin is a cached weighted Flip, for repeated use with the same weights. The
+// zero value is not usable; construct with NewCoin. Safe for concurrent usage.
+type Coin struct {
+	heads uint64 // weight of true
+	total uint64
+}
+
+// NewCoin initializes a Coin which returns true and false with relative
+// probabilities given by trueWeight and falseWeight.
+func NewCoin[W integer](trueWeight, falseWeight W) (Coin, error) {
+	var t, f uint64
+	if trueWeight >= 1 {
+		t = uint64(trueWeight)
+	}
+	if falseWeight >= 1 {
+		f = uint64(falseWeight)
+	}
+	if t == 0 && f == 0 {
+		return Coin{}, errNoValidChoices
+	}
+
+	total, carry := bits.Add64(t, f, 0)
+	if carry != 0 {
+		// Halving both weights preserves their ratio to within one part in 2^63.
+		t, f = t>>1, f>>1
+		total = t + f
+	}
+	return Coin{heads: t, total: total}, nil
+}
+
+// Flip returns true or false with the Coin's relative probabilities.
+func (c Coin) Flip() bool {
+	return uint64n(c.total) < c.heads
+}
+
+// uint64n returns a uniformly random number in [0,n) from global rand, using
+// Lemire's multiply and reject method. It panics if n == 0.
+func uint64n(n uint64) uint64 {
+	if n == 0 {
+		panic("weightedrand: invalid argument to uint64n")
+	}
+	hi, lo := bits.Mul64(rand.Uint64(), n)
+	if lo < n {
+		thresh := -n % n
+		for lo < thresh {
+			hi, lo = bits.Mul64(rand.Uint64(), n)
+		}
+	}
+	return hi
+}
@@ -0,0 +1,85 @@
+package weightedrand
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFlip(t *testing.T) {
+	const n = 100000
+	heads := 0
+	for i := 0; i < n; i++ {
+		if Flip(1, 3) {
+			heads++
+		}
+	}
+	if p := float64(heads) / n; math.Abs(p-0.25) > 0.01 {
+		t.Errorf("Flip(1, 3) returned true with frequency %v, want 0.25", p)
+	}
+
+	for i := 0; i < 100; i++ {
+		if !Flip(1, -5) || Flip(uint8(0), 200) {
+			t.Fatal("Flip returned outcome with weight < 1")
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic with no weights >= 1")
+		}
+	}()
+	Flip(0, 0)
+}
+
+func TestNewCoin(t *testing.T) {
+	tests := []struct {
+		name    string
+		t, f    uint64
+		want    Coin
+		wantErr error
+	}{
+		{name: "nominal", t: 1, f: 2, want: Coin{heads: 1, total: 3}},
+		{name: "no valid weights", t: 0, f: 0, wantErr: errNoValidChoices},
+		{name: "overflow", t: maxUint64, f: 3, want: Coin{heads: maxUint64 >> 1, total: maxUint64>>1 + 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewCoin(tt.t, tt.f)
+			if err != tt.wantErr || got != tt.want {
+				t.Errorf("NewCoin() = %+v, %v, want %+v, %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUint64n(t *testing.T) {
+	for _, n := range []uint64{1, 2, 3, 1 << 63, maxUint64} {
+		for i := 0; i < 1000; i++ {
+			if v := uint64n(n); v >= n {
+				t.Fatalf("uint64n(%d) = %d, out of range", n, v)
+			}
+		}
+	}
+}
+
+func BenchmarkFlip(b *testing.B) {
+	b.Run("Flip", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_ = Flip(1, 9)
+		}
+	})
+	b.Run("Coin", func(b *testing.B) {
+		coin, _ := NewCoin(1, 9)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			_ = coin.Flip()
+		}
+	})
+	b.Run("Chooser", func(b *testing.B) {
+		chooser, _ := NewChooser(NewChoice(true, 1), NewChoice(false, 9))
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			_ = chooser.Pick()
+		}
+	})
+}
@@ -0,0 +1,64 @@
+package weightedrand
+
+import "sort"
+
+// CompactReport describes the changes made by Compact.
+type CompactReport struct {
+	Input    int // number of choices provided
+	Output   int // number of choices returned
+	Zero     int // choices removed for having a weight of zero
+	Negative int // choices removed for having a negative weight
+	Merged   int // duplicate choices merged into an earlier choice for the same item
+}
+
+// Changed reports whether Compact modified the set of choices in any way, other
+// than by sorting.
+func (r CompactReport) Changed() bool {
+	return r.Zero > 0 || r.Negative > 0 || r.Merged > 0
+}
+
+// Compact returns a copy of choices with all choices that could never be picked
+// removed, and choices for duplicate items merged by summing their weights,
+// along with a report of what was changed. The order of first occurrence is
+// otherwise preserved, unless sortByWeight is set, in which case the result is
+// sorted by ascending weight.
+//
+// A duplicate whose merged weight would overflow W is kept as a separate choice
+// rather than being merged, leaving NewChooser to report any overflow of the
+// total weight.
+//
+// Compact is intended as a shared preprocessing stage for choices from
+// external sources prior to constructing a Chooser. It does not modify the
+// provided slice.
+func Compact[T comparable, W integer](choices []Choice[T, W], sortByWeight bool) ([]Choice[T, W], CompactReport) {
+	report := CompactReport{Input: len(choices)}
+	out := make([]Choice[T, W], 0, len(choices))
+	index := make(map[T]int, len(choices))
+	for _, c := range choices {
+		switch {
+		case c.Weight == 0:
+			report.Zero++
+			continue
+		case c.Weight < 0:
+			report.Negative++
+			continue
+		}
+		if i, ok := index[c.Item]; ok {
+			if sum := out[i].Weight + c.Weight; sum > out[i].Weight {
+				out[i].Weight = sum
+				report.Merged++
+				continue
+			}
+		}
+		index[c.Item] = len(out)
+		out = append(out, c)
+	}
+
+	if sortByWeight {
+		sort.SliceStable(out, func(i, j int) bool {
+			return out[i].Weight < out[j].Weight
+		})
+	}
+	report.Output = len(out)
+	return out, report
+}
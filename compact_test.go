@@ -0,0 +1,76 @@
+package weightedrand
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func ExampleCompact() {
+	choices, report := Compact([]Choice[string, int]{
+		NewChoice("a", 2),
+		NewChoice("b", 0),
+		NewChoice("c", 1),
+		NewChoice("a", 3),
+		NewChoice("d", -1),
+	}, true)
+	fmt.Println(choices)
+	fmt.Printf("%+v\n", report)
+	//Output:
+	// [{c 1} {a 5}]
+	// {Input:5 Output:2 Zero:1 Negative:1 Merged:1}
+}
+
+func TestCompact(t *testing.T) {
+	tests := []struct {
+		name         string
+		cs           []Choice[rune, int8]
+		sortByWeight bool
+		want         []Choice[rune, int8]
+		wantReport   CompactReport
+	}{
+		{
+			name:       "empty",
+			cs:         nil,
+			want:       []Choice[rune, int8]{},
+			wantReport: CompactReport{},
+		},
+		{
+			name:       "unchanged preserves order",
+			cs:         []Choice[rune, int8]{{'b', 2}, {'a', 1}},
+			want:       []Choice[rune, int8]{{'b', 2}, {'a', 1}},
+			wantReport: CompactReport{Input: 2, Output: 2},
+		},
+		{
+			name:         "sorted",
+			cs:           []Choice[rune, int8]{{'b', 2}, {'a', 1}, {'c', 2}},
+			sortByWeight: true,
+			want:         []Choice[rune, int8]{{'a', 1}, {'b', 2}, {'c', 2}},
+			wantReport:   CompactReport{Input: 3, Output: 3},
+		},
+		{
+			name:       "merge overflow kept separate",
+			cs:         []Choice[rune, int8]{{'a', 100}, {'a', 27}, {'a', 1}},
+			want:       []Choice[rune, int8]{{'a', 127}, {'a', 1}},
+			wantReport: CompactReport{Input: 3, Output: 2, Merged: 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := append([]Choice[rune, int8](nil), tt.cs...)
+			got, report := Compact(tt.cs, tt.sortByWeight)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Compact() = %v, want %v", got, tt.want)
+			}
+			if report != tt.wantReport {
+				t.Errorf("Compact() report = %+v, want %+v", report, tt.wantReport)
+			}
+			if report.Changed() != (report.Input != report.Output) {
+				t.Errorf("Changed() = %v for report %+v", report.Changed(), report)
+			}
+			if !reflect.DeepEqual(tt.cs, input) {
+				t.Errorf("Compact() modified input: %v, was %v", tt.cs, input)
+			}
+		})
+	}
+}
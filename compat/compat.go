@@ -0,0 +1,94 @@
+// Package compat provides the non-generic API of weightedrand v1, implemented
+// on top of v2, so that large codebases can migrate call sites incrementally.
+//
+// Existing code can switch its import path from github.com/mroth/weightedrand
+// to this package without further changes, then move to the generic v2 API
+// one call site at a time. New code should use v2 directly.
+package compat
+
+import (
+	"errors"
+	"math/rand"
+
+	"github.com/mroth/weightedrand/v2"
+)
+
+// Choice is a generic wrapper that can be used to add weights for any item.
+type Choice struct {
+	Item   interface{}
+	Weight uint
+}
+
+// NewChoice creates a new Choice with specified item and weight.
+func NewChoice(item interface{}, weight uint) Choice {
+	return Choice{Item: item, Weight: weight}
+}
+
+// A Chooser caches many possible Choices in a structure designed to improve
+// performance on repeated calls for weighted random selection.
+type Chooser struct {
+	c *weightedrand.Chooser[interface{}, uint]
+}
+
+const maxInt = int(^uint(0) >> 1)
+
+// Possible errors returned by NewChooser, preventing the creation of a Chooser
+// with unsafe runtime states. These match the exported errors of v1.
+var (
+	// If the sum of provided Choice weights exceed the maximum integer value
+	// for the current platform (e.g. math.MaxInt32 or math.MaxInt64), then
+	// the internal running total will overflow, resulting in an imbalanced
+	// distribution generating improper results.
+	ErrWeightOverflow = errors.New("sum of Choice Weights exceeds max int")
+	// If there are no Choices available to the Chooser with a weight >= 1,
+	// there are no valid choices and Pick would produce a runtime panic.
+	ErrNoValidChoices = errors.New("zero Choices with Weight >= 1")
+)
+
+// NewChooser initializes a new Chooser for picking from the provided choices.
+func NewChooser(choices ...Choice) (*Chooser, error) {
+	// Weights are validated here rather than by v2, whose errors are not
+	// exported, so that callers comparing against the v1 errors keep working.
+	var total uint
+	for _, c := range choices {
+		if c.Weight >= uint(maxInt) || uint(maxInt)-total <= c.Weight {
+			return nil, ErrWeightOverflow
+		}
+		total += c.Weight
+	}
+	if total < 1 {
+		return nil, ErrNoValidChoices
+	}
+
+	converted := make([]weightedrand.Choice[interface{}, uint], len(choices))
+	for i, c := range choices {
+		converted[i] = weightedrand.NewChoice(c.Item, c.Weight)
+	}
+	c, err := weightedrand.NewChooser(converted...)
+	if err != nil {
+		return nil, err
+	}
+	return &Chooser{c: c}, nil
+}
+
+// Pick returns a single weighted random Choice.Item from the Chooser.
+//
+// Utilizes global rand as the source of randomness.
+func (c Chooser) Pick() interface{} {
+	return c.c.Pick()
+}
+
+// PickSource returns a single weighted random Choice.Item from the Chooser,
+// utilizing the provided *rand.Rand source rs for randomness.
+//
+// It is the responsibility of the caller to ensure the provided rand.Source is
+// free from thread safety issues.
+func (c Chooser) PickSource(rs *rand.Rand) interface{} {
+	return c.c.PickSource(rs)
+}
+
+// Upgrade returns the underlying v2 Chooser, for call sites which have
+// migrated to the generic API.
+func (c Chooser) Upgrade() *weightedrand.Chooser[interface{}, uint] {
+	return c.c
+}
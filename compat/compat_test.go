@@ -0,0 +1,57 @@
+package compat
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+func ExampleNewChooser() {
+	chooser, _ := NewChooser(
+		NewChoice('🍒', 0),
+		NewChoice('🥑', 5),
+	)
+	result := chooser.Pick().(rune)
+	fmt.Println(string(result))
+	//Output: 🥑
+}
+
+func TestNewChooser(t *testing.T) {
+	tests := []struct {
+		name    string
+		cs      []Choice
+		wantErr error
+	}{
+		{name: "zero choices", cs: nil, wantErr: ErrNoValidChoices},
+		{name: "no choices with positive weight", cs: []Choice{{Item: 'a'}, {Item: 'b'}}, wantErr: ErrNoValidChoices},
+		{name: "weight overflow", cs: []Choice{{Item: 'a', Weight: uint(maxInt)/2 + 1}, {Item: 'b', Weight: uint(maxInt)/2 + 1}}, wantErr: ErrWeightOverflow},
+		{name: "single weight overflow", cs: []Choice{{Item: 'a', Weight: ^uint(0)}}, wantErr: ErrWeightOverflow},
+		{name: "nominal case", cs: []Choice{{Item: 'a', Weight: 1}, {Item: 'b', Weight: 2}}, wantErr: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewChooser(tt.cs...)
+			if err != tt.wantErr {
+				t.Errorf("NewChooser() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestChooser_PickSource(t *testing.T) {
+	chooser, err := NewChooser(NewChoice("a", 1), NewChoice("b", 0), NewChoice(3, 2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rs := rand.New(rand.NewSource(1))
+	counts := make(map[interface{}]int)
+	for i := 0; i < 3000; i++ {
+		counts[chooser.PickSource(rs)]++
+	}
+	if counts["b"] != 0 || counts["a"] == 0 || counts[3] <= counts["a"] {
+		t.Errorf("unexpected distribution of picks: %v", counts)
+	}
+	if got := chooser.Upgrade().Summary().Count; got != 2 {
+		t.Errorf("Upgrade().Summary().Count = %d, want 2", got)
+	}
+}
@@ -0,0 +1,237 @@
+// Package config loads a Chooser from a YAML or TOML configuration file using
+// a common documented schema, validating it so that mistakes are reported with
+// their location rather than silently skewing the distribution.
+//
+// In YAML the schema is:
+//
+//	choices:
+//	  - item: primary
+//	    weight: 90
+//	  - item: canary
+//	    weight: 10
+//
+// and in TOML:
+//
+//	[[choices]]
+//	item = "primary"
+//	weight = 90
+//
+//	[[choices]]
+//	item = "canary"
+//	weight = 10
+//
+// Every choice must have a non-empty, unique item and a non-negative integer
+// weight, and at least one weight must be positive. Unknown keys are rejected.
+//
+// Alternatively, weights may all be written as percentage strings summing to
+// exactly 100%, such as "99.5%" and "0.5%", which are converted to the
+// smallest integer weights in exactly the same proportions.
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/BurntSushi/toml"
+	"github.com/mroth/weightedrand/v2"
+	"github.com/mroth/weightedrand/v2/config/internal/percent"
+	"gopkg.in/yaml.v3"
+)
+
+// ErrFormat is returned by Load for files whose extension is not one of .yaml,
+// .yml or .toml.
+var ErrFormat = errors.New("config: unknown file format")
+
+// Load reads the configuration file at path, in YAML or TOML according to its
+// extension, and returns a Chooser for its choices.
+func Load(path string) (*weightedrand.Chooser[string, int], error) {
+	var parse func([]byte) (*weightedrand.Chooser[string, int], error)
+	switch filepath.Ext(path) {
+	case ".yaml", ".yml":
+		parse = ParseYAML
+	case ".toml":
+		parse = ParseTOML
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrFormat, path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	c, err := parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return c, nil
+}
+
+type yamlConfig struct {
+	Choices []struct {
+		Item   yaml.Node `yaml:"item"`
+		Weight yaml.Node `yaml:"weight"`
+	} `yaml:"choices"`
+}
+
+// ParseYAML parses a YAML configuration and returns a Chooser for its choices.
+// Errors identify the line of the offending value.
+func ParseYAML(data []byte) (*weightedrand.Chooser[string, int], error) {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	var cfg yamlConfig
+	if err := dec.Decode(&cfg); err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+
+	b := newBuilder()
+	for i, c := range cfg.Choices {
+		if c.Item.Kind == 0 || c.Weight.Kind == 0 {
+			return nil, fmt.Errorf("config: choice %d: item and weight are required", i)
+		}
+		if c.Item.Kind != yaml.ScalarNode {
+			return nil, fmt.Errorf("config: line %d: item must be a string", c.Item.Line)
+		}
+		weight, err := parseWeight(c.Weight)
+		if err == nil {
+			err = b.add(c.Item.Value, weight)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("config: line %d: %w", c.Weight.Line, err)
+		}
+	}
+	return b.build()
+}
+
+func parseWeight(n yaml.Node) (weight, error) {
+	var w weight
+	if n.Kind == yaml.ScalarNode {
+		switch n.ShortTag() {
+		case "!!int":
+			if n.Decode(&w.n) == nil {
+				return w, nil
+			}
+		case "!!str":
+			if percent.Is(n.Value) {
+				return parsePercent(n.Value)
+			}
+		}
+	}
+	return w, fmt.Errorf("weight %q is not an integer or percentage", n.Value)
+}
+
+// weight is an integer weight or a percentage, as written in a configuration.
+type weight struct {
+	n       int
+	percent *percent.Percent
+}
+
+func parsePercent(s string) (weight, error) {
+	p, err := percent.Parse(s)
+	if err != nil {
+		return weight{}, err
+	}
+	return weight{percent: &p}, nil
+}
+
+type tomlConfig struct {
+	Choices []struct {
+		Item   *string     `toml:"item"`
+		Weight *tomlWeight `toml:"weight"`
+	} `toml:"choices"`
+}
+
+type tomlWeight weight
+
+// UnmarshalTOML implements toml.Unmarshaler, accepting either an integer or a
+// percentage string.
+func (w *tomlWeight) UnmarshalTOML(v interface{}) error {
+	switch v := v.(type) {
+	case int64:
+		if int64(int(v)) == v {
+			w.n = int(v)
+			return nil
+		}
+	case string:
+		if percent.Is(v) {
+			pw, err := parsePercent(v)
+			*w = tomlWeight(pw)
+			return err
+		}
+		return fmt.Errorf("weight %q is not an integer or percentage", v)
+	}
+	return fmt.Errorf("weight %v is not an integer or percentage", v)
+}
+
+// ParseTOML parses a TOML configuration and returns a Chooser for its choices.
+// Syntax and type errors identify the offending line, and other errors the
+// offending choice by its index.
+func ParseTOML(data []byte) (*weightedrand.Chooser[string, int], error) {
+	var cfg tomlConfig
+	md, err := toml.Decode(string(data), &cfg)
+	if err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+	if undecoded := md.Undecoded(); len(undecoded) > 0 {
+		return nil, fmt.Errorf("config: unknown key %q", undecoded[0].String())
+	}
+
+	b := newBuilder()
+	for i, c := range cfg.Choices {
+		if c.Item == nil || c.Weight == nil {
+			return nil, fmt.Errorf("config: choice %d: item and weight are required", i)
+		}
+		if err := b.add(*c.Item, weight(*c.Weight)); err != nil {
+			return nil, fmt.Errorf("config: choice %d: %w", i, err)
+		}
+	}
+	return b.build()
+}
+
+// builder validates choices common to all formats.
+type builder struct {
+	choices  []weightedrand.Choice[string, int]
+	percents []percent.Percent
+	seen     map[string]bool
+}
+
+func newBuilder() *builder {
+	return &builder{seen: make(map[string]bool)}
+}
+
+func (b *builder) add(item string, w weight) error {
+	switch {
+	case item == "":
+		return errors.New("item must not be empty")
+	case b.seen[item]:
+		return fmt.Errorf("duplicate item %q", item)
+	case w.n < 0:
+		return fmt.Errorf("weight %d for item %q is negative", w.n, item)
+	case len(b.choices) > 0 && (w.percent != nil) != (len(b.percents) > 0):
+		return errors.New("weights mix percentages and integers")
+	}
+	b.seen[item] = true
+	if w.percent != nil {
+		b.percents = append(b.percents, *w.percent)
+	}
+	b.choices = append(b.choices, weightedrand.NewChoice(item, w.n))
+	return nil
+}
+
+func (b *builder) build() (*weightedrand.Chooser[string, int], error) {
+	if len(b.percents) > 0 {
+		weights, err := percent.Weights(b.percents)
+		if err != nil {
+			return nil, fmt.Errorf("config: %w", err)
+		}
+		for i, w := range weights {
+			b.choices[i].Weight = w
+		}
+	}
+	c, err := weightedrand.NewChooser(b.choices...)
+	if err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+	return c, nil
+}
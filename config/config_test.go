@@ -0,0 +1,118 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func ExampleParseYAML() {
+	chooser, err := ParseYAML([]byte(`
+choices:
+  - item: primary
+    weight: 90
+  - item: canary
+    weight: -10
+`))
+	fmt.Println(chooser, err)
+	//Output: <nil> config: line 6: weight -10 for item "canary" is negative
+}
+
+func TestParseYAML(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{name: "valid", input: "choices:\n  - item: a\n    weight: 1\n  - item: b\n    weight: 0\n"},
+		{name: "not an integer", input: "choices:\n  - item: a\n    weight: 1.5\n", wantErr: `line 3: weight "1.5" is not an integer`},
+		{name: "string weight", input: "choices:\n  - item: a\n    weight: lots\n", wantErr: `line 3: weight "lots" is not an integer`},
+		{name: "duplicate", input: "choices:\n  - {item: a, weight: 1}\n  - {item: a, weight: 2}\n", wantErr: `line 3: duplicate item "a"`},
+		{name: "empty item", input: "choices:\n  - {item: '', weight: 1}\n", wantErr: "line 2: item must not be empty"},
+		{name: "non-scalar item", input: "choices:\n  - {item: [a], weight: 1}\n", wantErr: "line 2: item must be a string"},
+		{name: "missing weight", input: "choices:\n  - item: a\n", wantErr: "choice 0: item and weight are required"},
+		{name: "unknown key", input: "choices:\n  - {item: a, weight: 1, wieght: 2}\n", wantErr: "line 2: field wieght not found"},
+		{name: "no positive weights", input: "choices:\n  - {item: a, weight: 0}\n", wantErr: "zero Choices"},
+		{name: "percentages", input: "choices:\n  - {item: a, weight: 99.5%}\n  - {item: b, weight: '0.5%'}\n"},
+		{name: "percentage sum", input: "choices:\n  - {item: a, weight: 33.3%}\n  - {item: b, weight: 66.6%}\n", wantErr: "sum is 99.9%"},
+		{name: "invalid percentage", input: "choices:\n  - {item: a, weight: -5%}\n", wantErr: `line 2: invalid percentage "-5%"`},
+		{name: "mixed percentages", input: "choices:\n  - {item: a, weight: 50%}\n  - {item: b, weight: 50}\n", wantErr: "line 3: weights mix percentages and integers"},
+		{name: "syntax", input: "choices: [\n", wantErr: "line"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseYAML([]byte(tt.input))
+			checkErr(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestParseTOML(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{name: "valid", input: "[[choices]]\nitem = \"a\"\nweight = 1\n[[choices]]\nitem = \"b\"\nweight = 0\n"},
+		{name: "not an integer", input: "[[choices]]\nitem = \"a\"\nweight = \"lots\"\n", wantErr: "line 3"},
+		{name: "negative", input: "[[choices]]\nitem = \"a\"\nweight = -1\n", wantErr: `choice 0: weight -1 for item "a" is negative`},
+		{name: "duplicate", input: "[[choices]]\nitem = \"a\"\nweight = 1\n[[choices]]\nitem = \"a\"\nweight = 1\n", wantErr: `choice 1: duplicate item "a"`},
+		{name: "missing item", input: "[[choices]]\nweight = 1\n", wantErr: "choice 0: item and weight are required"},
+		{name: "percentages", input: "[[choices]]\nitem = \"a\"\nweight = \"99.5%\"\n[[choices]]\nitem = \"b\"\nweight = \"0.5%\"\n"},
+		{name: "invalid percentage", input: "[[choices]]\nitem = \"a\"\nweight = \"half%\"\n", wantErr: `line 3 (last key "choices.weight"): invalid percentage "half%"`},
+		{name: "string weight", input: "[[choices]]\nitem = \"a\"\nweight = \"5\"\n", wantErr: `weight "5" is not an integer or percentage`},
+		{name: "mixed percentages", input: "[[choices]]\nitem = \"a\"\nweight = 1\n[[choices]]\nitem = \"b\"\nweight = \"50%\"\n", wantErr: "choice 1: weights mix percentages and integers"},
+		{name: "unknown key", input: "[[choices]]\nitem = \"a\"\nweight = 1\nwieght = 2\n", wantErr: `unknown key "choices.wieght"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseTOML([]byte(tt.input))
+			checkErr(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"a.yaml": "choices:\n  - {item: a, weight: 1}\n",
+		"a.toml": "[[choices]]\nitem = \"a\"\nweight = 1\n",
+		"a.json": "{}",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, name := range []string{"a.yaml", "a.toml"} {
+		c, err := Load(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("Load(%s) error = %v", name, err)
+		}
+		if got := c.Pick(); got != "a" {
+			t.Errorf("Load(%s).Pick() = %q, want a", name, got)
+		}
+	}
+	if _, err := Load(filepath.Join(dir, "a.json")); !errors.Is(err, ErrFormat) {
+		t.Errorf("Load(a.json) error = %v, want %v", err, ErrFormat)
+	}
+	if _, err := Load(filepath.Join(dir, "missing.yaml")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Load(missing.yaml) error = %v, want %v", err, os.ErrNotExist)
+	}
+}
+
+func checkErr(t *testing.T, err error, want string) {
+	t.Helper()
+	if want == "" {
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		return
+	}
+	if err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("error = %v, want containing %q", err, want)
+	}
+}
@@ -0,0 +1,14 @@
+module github.com/mroth/weightedrand/v2/config
+
+go 1.18
+
+require (
+	github.com/BurntSushi/toml v1.6.0
+	github.com/mroth/weightedrand/v2 v2.2.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+// Builds within this repository use the parent module as checked out. The
+// replace directive is ignored for users of this module, who get the version
+// required above, the first to provide all the APIs used here.
+replace github.com/mroth/weightedrand/v2 => ../
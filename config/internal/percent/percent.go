@@ -0,0 +1,127 @@
+// Package percent converts weights written as percentages, such as "25%" or
+// "0.5%", to integer weights in exactly the same proportions. Percentages are
+// parsed as decimals rather than floats, so no rounding drift is introduced.
+//
+// It is a copy of the weightedrand module's own internal percent package, as
+// internal packages cannot be imported across modules, and the two must be
+// kept in step so that every loader accepts the same percentages.
+package percent
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// maxPlaces is the most decimal places a percentage may have, so that 100% at
+// that scale, 10^17, fits in a uint64 with room to sum.
+const maxPlaces = 15
+
+// Possible errors returned by Parse and Weights.
+var (
+	ErrSyntax = errors.New("invalid percentage")
+	ErrSum    = errors.New("percentages do not sum to 100%")
+)
+
+// A Percent is an exact decimal percentage: digits / 10^places percent.
+type Percent struct {
+	digits uint64
+	places int
+}
+
+// Is reports whether s is written as a percentage, by its "%" suffix.
+func Is(s string) bool {
+	return strings.HasSuffix(s, "%")
+}
+
+// Parse parses s, a non-negative decimal number followed by "%", such as
+// "25%" or "0.5%". Exponents and signs are not accepted.
+func Parse(s string) (Percent, error) {
+	whole, frac, hasFrac := strings.Cut(strings.TrimSuffix(s, "%"), ".")
+	if !Is(s) || whole == "" || (hasFrac && frac == "") || len(frac) > maxPlaces ||
+		!isDigits(whole) || !isDigits(frac) {
+		return Percent{}, fmt.Errorf("%w %q", ErrSyntax, s)
+	}
+	digits, err := strconv.ParseUint(whole+frac, 10, 64)
+	if err != nil {
+		return Percent{}, fmt.Errorf("%w %q", ErrSyntax, s)
+	}
+	return Percent{digits: digits, places: len(frac)}, nil
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// String returns p formatted as it was parsed, without leading zeros.
+func (p Percent) String() string {
+	s := strconv.FormatUint(p.digits, 10)
+	if p.places == 0 {
+		return s + "%"
+	}
+	if len(s) <= p.places {
+		s = strings.Repeat("0", p.places-len(s)+1) + s
+	}
+	return s[:len(s)-p.places] + "." + s[len(s)-p.places:] + "%"
+}
+
+// Weights returns the smallest integer weights in exactly the proportions of
+// percents, which must sum to exactly 100%: each is scaled to the greatest
+// number of decimal places among them, then divided by their greatest common
+// divisor.
+func Weights(percents []Percent) ([]int, error) {
+	places := 0
+	for _, p := range percents {
+		if p.places > places {
+			places = p.places
+		}
+	}
+	total := 100 * pow10(places)
+
+	scaled := make([]uint64, len(percents))
+	var sum, gcd uint64
+	for i, p := range percents {
+		scale := pow10(places - p.places)
+		if p.digits > (total-sum)/scale {
+			return nil, ErrSum // this percentage alone takes the sum past 100%
+		}
+		scaled[i] = p.digits * scale
+		sum += scaled[i]
+		gcd = gcdUint64(gcd, scaled[i])
+	}
+	if sum != total {
+		return nil, fmt.Errorf("%w: sum is %v", ErrSum, Percent{digits: sum, places: places})
+	}
+
+	weights := make([]int, len(scaled))
+	for i, w := range scaled {
+		w /= gcd
+		if w > math.MaxInt {
+			return nil, fmt.Errorf("%w: too many decimal places for int weights", ErrSyntax)
+		}
+		weights[i] = int(w)
+	}
+	return weights, nil
+}
+
+func pow10(n int) uint64 {
+	p := uint64(1)
+	for ; n > 0; n-- {
+		p *= 10
+	}
+	return p
+}
+
+func gcdUint64(a, b uint64) uint64 {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
@@ -0,0 +1,77 @@
+package percent
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in, want string // want is empty for errors
+	}{
+		{in: "25%", want: "25%"},
+		{in: "0.5%", want: "0.5%"},
+		{in: "007.50%", want: "7.50%"},
+		{in: "0.000000000000001%", want: "0.000000000000001%"},
+		{in: "100%", want: "100%"},
+		{in: "25"},
+		{in: "%"},
+		{in: ".5%"},
+		{in: "5.%"},
+		{in: "-5%"},
+		{in: "+5%"},
+		{in: "1e2%"},
+		{in: "1.2.3%"},
+		{in: " 5%"},
+		{in: "0.0000000000000001%"},
+		{in: "99999999999999999999%"},
+	}
+	for _, tt := range tests {
+		p, err := Parse(tt.in)
+		if tt.want == "" {
+			if !errors.Is(err, ErrSyntax) {
+				t.Errorf("Parse(%q) = %v, %v; want error %v", tt.in, p, err, ErrSyntax)
+			}
+			continue
+		}
+		if err != nil || p.String() != tt.want {
+			t.Errorf("Parse(%q) = %v, %v; want %s", tt.in, p, err, tt.want)
+		}
+	}
+}
+
+func TestWeights(t *testing.T) {
+	tests := []struct {
+		percents []string
+		want     string
+		wantErr  error
+	}{
+		{percents: []string{"25%", "75%"}, want: "[1 3]"},
+		{percents: []string{"99.5%", "0.5%"}, want: "[199 1]"},
+		{percents: []string{"33.33%", "33.33%", "33.34%", "0%"}, want: "[3333 3333 3334 0]"},
+		{percents: []string{"100%"}, want: "[1]"},
+		{percents: []string{"0.000000000000001%", "99.999999999999999%"}, want: "[1 99999999999999999]"},
+		{percents: []string{"33.3%", "33.3%", "33.3%"}, wantErr: ErrSum},
+		{percents: []string{"60%", "50%"}, wantErr: ErrSum},
+		{percents: []string{"18446744073709551615%", "1%"}, wantErr: ErrSum},
+		{percents: []string{"0%"}, wantErr: ErrSum},
+		{percents: nil, wantErr: ErrSum},
+	}
+	for _, tt := range tests {
+		percents := make([]Percent, len(tt.percents))
+		for i, s := range tt.percents {
+			var err error
+			if percents[i], err = Parse(s); err != nil {
+				t.Fatal(err)
+			}
+		}
+		got, err := Weights(percents)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("Weights(%v) error = %v, want %v", tt.percents, err, tt.wantErr)
+		}
+		if err == nil && fmt.Sprint(got) != tt.want {
+			t.Errorf("Weights(%v) = %v, want %s", tt.percents, got, tt.want)
+		}
+	}
+}
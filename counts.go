@@ -0,0 +1,16 @@
+package weightedrand
+
+// NewChooserFromCounts initializes a new Chooser whose distribution matches the
+// empirical frequencies of a histogram of observed counts, such that each item
+// is picked with probability counts[item] / Σ counts.
+//
+// Items with equal counts are ordered arbitrarily, following map iteration, so
+// the sequence of picks from a seeded source is only reproducible for
+// histograms with distinct counts.
+func NewChooserFromCounts[T comparable, W integer](counts map[T]W) (*Chooser[T, W], error) {
+	choices := make([]Choice[T, W], 0, len(counts))
+	for item, n := range counts {
+		choices = append(choices, NewChoice(item, n))
+	}
+	return NewChooser(choices...)
+}
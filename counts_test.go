@@ -0,0 +1,33 @@
+package weightedrand
+
+import (
+	"fmt"
+	"testing"
+)
+
+func ExampleNewChooserFromCounts() {
+	requests := map[string]int{"GET /": 9_000, "POST /login": 0}
+	chooser, _ := NewChooserFromCounts(requests)
+	fmt.Println(chooser.Pick())
+	//Output: GET /
+}
+
+func TestNewChooserFromCounts(t *testing.T) {
+	counts := map[rune]uint{'a': 1, 'b': 2, 'c': 0, 'd': 7}
+	c, err := NewChooserFromCounts(counts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.max != 10 || len(c.data) != len(counts) {
+		t.Errorf("max = %d with %d choices, want 10 with %d", c.max, len(c.data), len(counts))
+	}
+	for _, choice := range c.data {
+		if counts[choice.Item] != choice.Weight {
+			t.Errorf("weight of %q = %d, want %d", choice.Item, choice.Weight, counts[choice.Item])
+		}
+	}
+
+	if _, err := NewChooserFromCounts(map[string]int{}); err != errNoValidChoices {
+		t.Errorf("NewChooserFromCounts() error = %v, wantErr %v", err, errNoValidChoices)
+	}
+}
@@ -0,0 +1,136 @@
+// Package decay provides a weighted random chooser where the weight of each
+// choice decays exponentially over time, so that recent events can be
+// favored over older ones without periodically rebuilding a Chooser by hand.
+package decay
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/mroth/weightedrand/v2"
+	"github.com/mroth/weightedrand/v2/internal/constraints"
+)
+
+// Choice is an item with an initial weight, which decays from the time Since.
+type Choice[T any, W constraints.Integer] struct {
+	Item   T
+	Weight W
+	Since  time.Time
+}
+
+// NewChoice creates a new Choice with specified item, weight and start time.
+func NewChoice[T any, W constraints.Integer](item T, weight W, since time.Time) Choice[T, W] {
+	return Choice[T, W]{Item: item, Weight: weight, Since: since}
+}
+
+// A Chooser picks from choices whose effective weight halves every half-life:
+//
+//	Weight * 2^(-(now - Since) / halfLife)
+//
+// As with weightedrand.Chooser, only choices with an effective weight >= 1 can
+// be picked. Choices whose effective weight has fallen below 1 are expired and
+// pruned.
+//
+// Since every choice decays at the same rate, relative probabilities only
+// change when choices are added or expire. The underlying table is therefore
+// recomputed lazily, on the first Pick after either happens.
+//
+// A Chooser is safe for concurrent usage.
+type Chooser[T any, W constraints.Integer] struct {
+	halfLife time.Duration
+	now      func() time.Time
+
+	mu      sync.Mutex
+	choices []Choice[T, W]
+	table   *weightedrand.Chooser[T, int] // nil if nothing pickable
+	expiry  time.Time                     // when the next choice will expire
+	dirty   bool                          // choices added since table was built
+}
+
+// NewChooser initializes a Chooser decaying with the given half-life, which
+// must be positive.
+func NewChooser[T any, W constraints.Integer](halfLife time.Duration, choices ...Choice[T, W]) *Chooser[T, W] {
+	if halfLife <= 0 {
+		panic("decay: non-positive half-life")
+	}
+	c := &Chooser[T, W]{halfLife: halfLife, now: time.Now}
+	c.Add(choices...)
+	return c
+}
+
+// Add adds choices to the Chooser.
+func (c *Chooser[T, W]) Add(choices ...Choice[T, W]) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, choice := range choices {
+		if choice.Weight >= 1 {
+			c.choices = append(c.choices, choice)
+			c.dirty = true
+		}
+	}
+}
+
+// Len returns the number of choices which have not yet expired.
+func (c *Chooser[T, W]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.refresh()
+	return len(c.choices)
+}
+
+// Pick returns a single weighted random item, according to the current
+// effective weights. If no choice has an effective weight >= 1, it returns the
+// zero value of T and false.
+func (c *Chooser[T, W]) Pick() (T, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.refresh()
+	if c.table == nil {
+		var zero T
+		return zero, false
+	}
+	return c.table.Pick(), true
+}
+
+// refresh rebuilds the table if choices have been added or expired since it
+// was last built. Callers must hold c.mu.
+func (c *Chooser[T, W]) refresh() {
+	now := c.now()
+	if !c.dirty && now.Before(c.expiry) {
+		return
+	}
+
+	live := c.choices[:0]
+	items := make([]T, 0, len(c.choices))
+	scores := make([]float64, 0, len(c.choices))
+	c.expiry = time.Time{}
+	for _, choice := range c.choices {
+		// Work in log space, so that choices far in the past or future do not
+		// overflow their effective weights.
+		halvings := float64(now.Sub(choice.Since)) / float64(c.halfLife)
+		log2Weight := math.Log2(float64(choice.Weight)) - halvings
+		if log2Weight < 0 {
+			continue // effective weight < 1, expired
+		}
+		live = append(live, choice)
+		items = append(items, choice.Item)
+		scores = append(scores, log2Weight*math.Ln2)
+
+		// The effective weight reaches 1 after log2(Weight) half-lives.
+		expires := choice.Since.Add(time.Duration(math.Log2(float64(choice.Weight)) * float64(c.halfLife)))
+		if c.expiry.IsZero() || expires.Before(c.expiry) {
+			c.expiry = expires
+		}
+	}
+	var zero Choice[T, W]
+	for i := len(live); i < len(c.choices); i++ {
+		c.choices[i] = zero // release expired items for garbage collection
+	}
+	c.choices = live
+	c.dirty = false
+
+	// Softmax over log weights at temperature 1 yields weights proportional to
+	// the effective weights, normalized to a fixed integer resolution.
+	c.table, _ = weightedrand.NewSoftmaxChooser(items, scores, 1)
+}
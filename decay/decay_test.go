@@ -0,0 +1,77 @@
+package decay
+
+import (
+	"testing"
+	"time"
+)
+
+// fakeClock returns a function reporting *t as the current time.
+func fakeClock(t *time.Time) func() time.Time {
+	return func() time.Time { return *t }
+}
+
+func TestChooser(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := start
+	c := NewChooser(time.Hour, NewChoice("old", 8, start), NewChoice("zero", 0, start))
+	c.now = fakeClock(&now)
+
+	if n := c.Len(); n != 1 {
+		t.Errorf("Len() = %d, want 1 (zero weights are ignored)", n)
+	}
+	if got, ok := c.Pick(); !ok || got != "old" {
+		t.Errorf("Pick() = %q, %v, want %q, true", got, ok, "old")
+	}
+
+	// After two half-lives "old" has an effective weight of 2, half of "new".
+	now = start.Add(2 * time.Hour)
+	c.Add(NewChoice("new", 4, now))
+	counts := make(map[string]int)
+	for i := 0; i < 10000; i++ {
+		item, _ := c.Pick()
+		counts[item]++
+	}
+	if counts["old"] < 3000 || counts["old"] > 3700 {
+		t.Errorf("expected roughly a third of picks to be old, got %v", counts)
+	}
+
+	// After three half-lives "old" expires, while "new" is still within its
+	// second half-life.
+	now = start.Add(3*time.Hour + time.Minute)
+	for i := 0; i < 100; i++ {
+		if got, _ := c.Pick(); got != "new" {
+			t.Fatalf("Pick() = %q, want %q", got, "new")
+		}
+	}
+	if n := c.Len(); n != 1 {
+		t.Errorf("Len() = %d after expiry, want 1", n)
+	}
+
+	// Eventually, everything expires.
+	now = now.Add(time.Hour)
+	if got, ok := c.Pick(); ok {
+		t.Errorf("Pick() = %q, %v, want zero value and false", got, ok)
+	}
+}
+
+// Choices in the distant past or future must not overflow effective weights.
+func TestChooser_extremeTimes(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewChooser(time.Second,
+		NewChoice("future", 1, now.Add(24*time.Hour)),
+		NewChoice("present", 1<<20, now),
+	)
+	c.now = fakeClock(&now)
+	if got, ok := c.Pick(); !ok || got != "future" {
+		t.Errorf("Pick() = %q, %v, want %q, true", got, ok, "future")
+	}
+}
+
+func TestNewChooser_panic(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for non-positive half-life")
+		}
+	}()
+	NewChooser[string, int](0)
+}
@@ -0,0 +1,235 @@
+// Package dispatch routes tasks across groups of worker channels in proportion
+// to the weights of the groups, falling back to other workers when the chosen
+// workers' channels are full. Weights may be updated while tasks are being
+// dispatched.
+package dispatch
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/mroth/weightedrand/v2"
+	"github.com/mroth/weightedrand/v2/internal/constraints"
+)
+
+// Possible errors returned by a Dispatcher.
+var (
+	ErrClosed       = errors.New("dispatch: dispatcher closed")
+	ErrEmptyGroup   = errors.New("dispatch: worker group has no channels")
+	ErrUnknownGroup = errors.New("dispatch: unknown worker group")
+)
+
+// A Dispatcher owns a set of weighted groups of worker channels, and routes
+// submitted tasks across them. It is safe for concurrent usage.
+type Dispatcher[T any, W constraints.Integer] struct {
+	groups [][]chan T
+	routes atomic.Value // *routing[W], replaced on weight updates
+
+	update  sync.Mutex // serializes weight updates
+	weights []W        // guarded by update
+
+	mu     sync.RWMutex // guards closed against concurrent sends
+	closed bool
+
+	done      chan struct{} // closed by Close, to release blocked submissions
+	closeOnce sync.Once
+}
+
+// routing is an immutable snapshot of how tasks are routed between groups.
+type routing[W constraints.Integer] struct {
+	chooser  *weightedrand.Chooser[int, W]
+	fallback [][]int // per group, the other groups in descending weight order
+}
+
+// New initializes a Dispatcher owning the provided worker channels, which
+// workers should receive tasks from, each as a group of its own. The channels
+// are closed by Close, and should be buffered, as the buffer size determines
+// when a worker is considered busy.
+func New[T any, W constraints.Integer](workers ...weightedrand.Choice[chan T, W]) (*Dispatcher[T, W], error) {
+	groups := make([]weightedrand.Choice[[]chan T, W], len(workers))
+	for i, w := range workers {
+		groups[i] = weightedrand.NewChoice([]chan T{w.Item}, w.Weight)
+	}
+	return NewGroups(groups...)
+}
+
+// NewGroups initializes a Dispatcher owning the provided groups of worker
+// channels. Tasks are routed to a group in proportion to its weight, and then
+// to the least loaded channel within the group. Groups are identified by their
+// index for SetWeight.
+func NewGroups[T any, W constraints.Integer](groups ...weightedrand.Choice[[]chan T, W]) (*Dispatcher[T, W], error) {
+	d := &Dispatcher[T, W]{
+		groups:  make([][]chan T, len(groups)),
+		weights: make([]W, len(groups)),
+		done:    make(chan struct{}),
+	}
+	for i, g := range groups {
+		if len(g.Item) == 0 {
+			return nil, ErrEmptyGroup
+		}
+		d.groups[i] = append([]chan T(nil), g.Item...)
+		d.weights[i] = g.Weight
+	}
+	r, err := newRouting(d.weights)
+	if err != nil {
+		return nil, err
+	}
+	d.routes.Store(r)
+	return d, nil
+}
+
+func newRouting[W constraints.Integer](weights []W) (*routing[W], error) {
+	choices := make([]weightedrand.Choice[int, W], len(weights))
+	for i, w := range weights {
+		choices[i] = weightedrand.NewChoice(i, w)
+	}
+	chooser, err := weightedrand.NewChooser(choices...)
+	if err != nil {
+		return nil, err
+	}
+
+	byWeight := make([]int, 0, len(weights))
+	for i, w := range weights {
+		if w >= 1 {
+			byWeight = append(byWeight, i)
+		}
+	}
+	sort.SliceStable(byWeight, func(a, b int) bool {
+		return weights[byWeight[a]] > weights[byWeight[b]]
+	})
+	fallback := make([][]int, len(weights))
+	for _, i := range byWeight {
+		for _, j := range byWeight {
+			if i != j {
+				fallback[i] = append(fallback[i], j)
+			}
+		}
+	}
+	return &routing[W]{chooser: chooser, fallback: fallback}, nil
+}
+
+// SetWeight updates the weight of the group at index i, taking effect for
+// subsequently submitted tasks. If the update would leave no group with a
+// weight >= 1, or overflow the total weight, an error is returned and the
+// previous weights remain in effect.
+func (d *Dispatcher[T, W]) SetWeight(i int, weight W) error {
+	d.update.Lock()
+	defer d.update.Unlock()
+	if i < 0 || i >= len(d.weights) {
+		return ErrUnknownGroup
+	}
+	weights := append([]W(nil), d.weights...)
+	weights[i] = weight
+	r, err := newRouting(weights)
+	if err != nil {
+		return err
+	}
+	d.weights = weights
+	d.routes.Store(r)
+	return nil
+}
+
+// Weights returns the current weight of each group.
+func (d *Dispatcher[T, W]) Weights() []W {
+	d.update.Lock()
+	defer d.update.Unlock()
+	return append([]W(nil), d.weights...)
+}
+
+// Submit routes task to a weighted random group of workers. If the channels of
+// that group are all full, the task is instead given to the highest weighted
+// group with room. If every worker is busy, Submit blocks until the least
+// loaded worker of the originally chosen group accepts the task, ctx is done,
+// or the Dispatcher is closed.
+func (d *Dispatcher[T, W]) Submit(ctx context.Context, task T) error {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if d.closed {
+		return ErrClosed
+	}
+
+	r := d.routes.Load().(*routing[W])
+	i := r.chooser.Pick()
+	if d.trySend(r, i, task) {
+		return nil
+	}
+	select {
+	case d.groups[i][leastLoaded(d.groups[i])] <- task:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-d.done:
+		return ErrClosed
+	}
+}
+
+// TrySubmit is like Submit, but never blocks, instead reporting whether any
+// worker accepted the task.
+func (d *Dispatcher[T, W]) TrySubmit(task T) bool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	r := d.routes.Load().(*routing[W])
+	return !d.closed && d.trySend(r, r.chooser.Pick(), task)
+}
+
+// trySend attempts a non-blocking send to group i, then to each of its
+// fallbacks in turn. Callers must hold d.mu.
+func (d *Dispatcher[T, W]) trySend(r *routing[W], i int, task T) bool {
+	if trySendGroup(d.groups[i], task) {
+		return true
+	}
+	for _, j := range r.fallback[i] {
+		if trySendGroup(d.groups[j], task) {
+			return true
+		}
+	}
+	return false
+}
+
+// trySendGroup attempts a non-blocking send to each channel of group in turn,
+// starting with the least loaded.
+func trySendGroup[T any](group []chan T, task T) bool {
+	first := leastLoaded(group)
+	for k := range group {
+		select {
+		case group[(first+k)%len(group)] <- task:
+			return true
+		default:
+		}
+	}
+	return false
+}
+
+// leastLoaded returns the index of the channel of group with the most free
+// buffer space.
+func leastLoaded[T any](group []chan T) int {
+	best := 0
+	for i := 1; i < len(group); i++ {
+		if cap(group[i])-len(group[i]) > cap(group[best])-len(group[best]) {
+			best = i
+		}
+	}
+	return best
+}
+
+// Close closes all worker channels, after waiting for any in-progress
+// submissions to complete. Submissions blocked waiting for a busy worker, and
+// all subsequent submissions, fail with ErrClosed.
+func (d *Dispatcher[T, W]) Close() {
+	// Release blocked submissions first, since they hold d.mu.
+	d.closeOnce.Do(func() { close(d.done) })
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.closed {
+		return
+	}
+	d.closed = true
+	for _, group := range d.groups {
+		for _, ch := range group {
+			close(ch)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package dispatch
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mroth/weightedrand/v2"
+)
+
+func TestNew(t *testing.T) {
+	if _, err := New(weightedrand.NewChoice(make(chan int), 0)); err == nil {
+		t.Error("expected error for no workers with positive weight")
+	}
+}
+
+func TestDispatcher_Submit(t *testing.T) {
+	const n = 10000
+	light, heavy := make(chan int, n), make(chan int, n)
+	d, err := New(weightedrand.NewChoice(light, 1), weightedrand.NewChoice(heavy, 3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < n; i++ {
+		if err := d.Submit(context.Background(), i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(light) < 2000 || len(heavy) < 7000 {
+		t.Errorf("expected roughly 1:3 split, got %d:%d", len(light), len(heavy))
+	}
+}
+
+func TestDispatcher_fallback(t *testing.T) {
+	busy, idle, unused := make(chan int), make(chan int, 100), make(chan int, 100)
+	d, err := New(
+		weightedrand.NewChoice(busy, 1000),
+		weightedrand.NewChoice(idle, 1),
+		weightedrand.NewChoice(unused, 0),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 100; i++ {
+		if !d.TrySubmit(i) {
+			t.Fatal("TrySubmit() = false with an idle worker available")
+		}
+	}
+	if len(idle) != 100 || len(unused) != 0 {
+		t.Errorf("expected all tasks to fall back to idle worker, got idle=%d unused=%d", len(idle), len(unused))
+	}
+
+	// with every worker full, Submit blocks until the context is done
+	if d.TrySubmit(0) {
+		t.Error("TrySubmit() = true with all workers busy")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := d.Submit(ctx, 0); err != context.DeadlineExceeded {
+		t.Errorf("Submit() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestNewGroups(t *testing.T) {
+	a1, a2, b := make(chan int, 100), make(chan int, 100), make(chan int, 100)
+	d, err := NewGroups(
+		weightedrand.NewChoice([]chan int{a1, a2}, 1),
+		weightedrand.NewChoice([]chan int{b}, 0),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 100; i++ {
+		if !d.TrySubmit(i) {
+			t.Fatal("TrySubmit() = false with idle workers available")
+		}
+	}
+	// Tasks are spread evenly across the least loaded workers of the group.
+	if len(a1) != 50 || len(a2) != 50 || len(b) != 0 {
+		t.Errorf("got a1=%d a2=%d b=%d, want 50, 50, 0", len(a1), len(a2), len(b))
+	}
+
+	if _, err := NewGroups(weightedrand.NewChoice([]chan int{}, 1)); err != ErrEmptyGroup {
+		t.Errorf("NewGroups() error = %v, want %v", err, ErrEmptyGroup)
+	}
+}
+
+func TestDispatcher_SetWeight(t *testing.T) {
+	a, b := make(chan int, 1000), make(chan int, 1000)
+	d, err := New(weightedrand.NewChoice(a, 1), weightedrand.NewChoice(b, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := d.SetWeight(1, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.SetWeight(0, 0); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 100; i++ {
+		d.TrySubmit(i)
+	}
+	if len(a) != 0 || len(b) != 100 {
+		t.Errorf("got a=%d b=%d after reweighting, want 0, 100", len(a), len(b))
+	}
+
+	// Invalid updates leave the previous weights in effect.
+	if err := d.SetWeight(1, 0); err == nil {
+		t.Error("SetWeight() to no positive weights error = nil")
+	}
+	if err := d.SetWeight(2, 1); err != ErrUnknownGroup {
+		t.Errorf("SetWeight() error = %v, want %v", err, ErrUnknownGroup)
+	}
+	if got := d.Weights(); got[0] != 0 || got[1] != 1 {
+		t.Errorf("Weights() = %v, want [0 1]", got)
+	}
+}
+
+func TestDispatcher_Close(t *testing.T) {
+	ch := make(chan int, 1)
+	d, _ := New(weightedrand.NewChoice(ch, 1))
+	d.Close()
+	d.Close() // idempotent
+	if _, ok := <-ch; ok {
+		t.Error("expected worker channel to be closed")
+	}
+	if err := d.Submit(context.Background(), 1); err != ErrClosed {
+		t.Errorf("Submit() error = %v, want %v", err, ErrClosed)
+	}
+	if d.TrySubmit(1) {
+		t.Error("TrySubmit() = true after Close")
+	}
+}
+
+func TestDispatcher_Close_blockedSubmit(t *testing.T) {
+	ch := make(chan int) // unbuffered and never received from, so always busy
+	d, _ := New(weightedrand.NewChoice(ch, 1))
+
+	submitted := make(chan error)
+	go func() { submitted <- d.Submit(context.Background(), 1) }()
+	time.Sleep(10 * time.Millisecond) // let Submit block
+
+	closed := make(chan struct{})
+	go func() {
+		d.Close()
+		close(closed)
+	}()
+	select {
+	case err := <-submitted:
+		if err != ErrClosed {
+			t.Errorf("blocked Submit() error = %v, want %v", err, ErrClosed)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close did not release blocked Submit")
+	}
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close deadlocked behind blocked Submit")
+	}
+}
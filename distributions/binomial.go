@@ -0,0 +1,126 @@
+// Package distributions provides samplers for the discrete probability
+// distributions underlying weighted random selection, which are needed to
+// simulate many selections at once without making each one.
+package distributions
+
+import (
+	"math"
+	"math/rand"
+)
+
+// Binomial returns a random number of successes in n independent trials, each
+// with probability p of success, using global rand as the source of
+// randomness. Probabilities outside [0,1] are clamped to it, and a NaN
+// probability causes a panic.
+//
+// Small means are drawn by summing geometric waiting times between successes,
+// which takes O(np) time, and others by the constant expected time BTRS
+// algorithm of Hörmann, "The generation of binomial random variates" (1993).
+func Binomial(n uint64, p float64) uint64 {
+	return BinomialFunc(n, p, rand.Float64)
+}
+
+// BinomialFunc is like Binomial, but draws uniform random numbers in the
+// half-open interval [0,1) from uniform, such as the Float64 method of a
+// *rand.Rand from math/rand or math/rand/v2.
+func BinomialFunc(n uint64, p float64, uniform func() float64) uint64 {
+	switch {
+	case math.IsNaN(p):
+		panic("distributions: NaN probability")
+	case n == 0 || p <= 0:
+		return 0
+	case p >= 1:
+		return n
+	case p > 0.5:
+		return n - BinomialFunc(n, 1-p, uniform)
+	case float64(n)*p < 10:
+		return binomialInversion(n, p, uniform)
+	default:
+		k := binomialBTRS(float64(n), p, uniform)
+		if k > n { // guard against rounding for n beyond 2^53
+			k = n
+		}
+		return k
+	}
+}
+
+func binomialInversion(n uint64, p float64, uniform func() float64) uint64 {
+	logq := math.Log1p(-p)
+	var k, sum uint64
+	for {
+		// 1-uniform() is in (0,1], so the geometric wait is finite and >= 1,
+		// unless the waiting time overflows in which case it exceeds n.
+		wait := math.Ceil(math.Log(1-uniform()) / logq)
+		if wait < 1 {
+			wait = 1
+		}
+		if wait > float64(n-sum) {
+			return k
+		}
+		sum += uint64(wait)
+		k++
+	}
+}
+
+func binomialBTRS(n, p float64, uniform func() float64) uint64 {
+	q := 1 - p
+	spq := math.Sqrt(n * p * q)
+	b := 1.15 + 2.53*spq
+	a := -0.0873 + 0.0248*b + 0.01*p
+	c := n*p + 0.5
+	vr := 0.92 - 4.2/b
+	alpha := (2.83 + 5.1/b) * spq
+	m := math.Floor((n + 1) * p)
+
+	for {
+		u := uniform() - 0.5
+		v := uniform()
+		us := 0.5 - math.Abs(u)
+		k := math.Floor((2*a/us+b)*u + c)
+		if k < 0 || k > n {
+			continue
+		}
+		if us >= 0.07 && v <= vr {
+			return toUint64(k)
+		}
+		// Accept if v is below the log of the ratio of the probabilities of k
+		// and the mode m. The factorials are expanded by Stirling's formula
+		// around m, so that no term suffers cancellation even when n is far
+		// beyond the precision of a float64.
+		v = math.Log(v * alpha / (a/(us*us) + b))
+		d := k - m
+		bound := -(k+0.5)*math.Log1p(d/(m+1)) -
+			(n-k+0.5)*math.Log1p(-d/(n-m+1)) -
+			d*math.Log((m+1)*q/(p*(n-m+1))) +
+			stirlingTail(m) + stirlingTail(n-m) - stirlingTail(k) - stirlingTail(n-k)
+		if v <= bound {
+			return toUint64(k)
+		}
+	}
+}
+
+// toUint64 converts a non-negative integer x to a uint64, saturating at
+// math.MaxUint64 rather than overflowing.
+func toUint64(x float64) uint64 {
+	if x >= 1<<64 {
+		return math.MaxUint64
+	}
+	return uint64(x)
+}
+
+// stirlingTail returns log(k!) - [(k+0.5)log(k+1) - (k+1) + log(2π)/2], the
+// error of Stirling's approximation of log(k!), for non-negative integer k.
+func stirlingTail(k float64) float64 {
+	if k < float64(len(stirlingTails)) {
+		return stirlingTails[int(k)]
+	}
+	kp1sq := (k + 1) * (k + 1)
+	return (1.0/12 - (1.0/360-1.0/1260/kp1sq)/kp1sq) / (k + 1)
+}
+
+var stirlingTails = [...]float64{
+	0.0810614667953272, 0.0413406959554092, 0.0276779256849983,
+	0.02079067210376509, 0.0166446911898211, 0.0138761288230707,
+	0.0118967099458917, 0.0104112652619720, 0.00925546218271273,
+	0.00833056343336287,
+}
@@ -0,0 +1,128 @@
+package distributions
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestBinomial(t *testing.T) {
+	tests := []struct {
+		n uint64
+		p float64
+	}{
+		{n: 0, p: 0.5},
+		{n: 10, p: 0},
+		{n: 10, p: 1},
+		{n: 1, p: 0.3},
+		{n: 20, p: 0.2},       // inversion
+		{n: 100, p: 0.95},     // inversion of complement
+		{n: 1000, p: 0.3},     // BTRS
+		{n: 1 << 40, p: 1e-3}, // BTRS with large n
+		{n: 1e15, p: 0.37},
+		{n: math.MaxUint64, p: 0.5},
+	}
+	rng := rand.New(rand.NewSource(1))
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("n=%d/p=%v", tt.n, tt.p), func(t *testing.T) {
+			const draws = 20000
+			n := float64(tt.n)
+			var sum, sumSq float64
+			for i := 0; i < draws; i++ {
+				k := BinomialFunc(tt.n, tt.p, rng.Float64)
+				if k > tt.n {
+					t.Fatalf("binomial() = %d, exceeds n", k)
+				}
+				x := float64(k) - n*tt.p
+				sum += x
+				sumSq += x * x
+			}
+			variance := n * tt.p * (1 - tt.p)
+			// The mean of the draws should be within 5 standard errors.
+			if se := math.Sqrt(variance / draws); math.Abs(sum/draws) > 5*se+1e-9*n {
+				t.Errorf("mean = %v, want %v", n*tt.p+sum/draws, n*tt.p)
+			}
+			if variance > 0 {
+				if got := sumSq / draws; math.Abs(got-variance)/variance > 0.05 {
+					t.Errorf("variance = %v, want %v", got, variance)
+				}
+			}
+		})
+	}
+}
+
+// The frequency of each outcome should match the binomial probability mass
+// function, for parameters exercising both methods.
+func TestBinomial_pmf(t *testing.T) {
+	tests := []struct {
+		n uint64
+		p float64
+	}{
+		{n: 30, p: 0.2},  // inversion
+		{n: 50, p: 0.4},  // BTRS
+		{n: 60, p: 0.75}, // BTRS of complement
+	}
+	rng := rand.New(rand.NewSource(2))
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("n=%d/p=%v", tt.n, tt.p), func(t *testing.T) {
+			const draws = 200000
+			counts := make([]int, tt.n+1)
+			for i := 0; i < draws; i++ {
+				counts[BinomialFunc(tt.n, tt.p, rng.Float64)]++
+			}
+			for k, count := range counts {
+				lc, _ := math.Lgamma(float64(tt.n) + 1)
+				lk, _ := math.Lgamma(float64(k) + 1)
+				lnk, _ := math.Lgamma(float64(int(tt.n)-k) + 1)
+				pmf := math.Exp(lc - lk - lnk + float64(k)*math.Log(tt.p) + float64(int(tt.n)-k)*math.Log1p(-tt.p))
+				want := pmf * draws
+				if math.Abs(float64(count)-want) > 5*math.Sqrt(want)+1 {
+					t.Errorf("count of %d = %d, want %.0f", k, count, want)
+				}
+			}
+		})
+	}
+}
+
+func TestStirlingTail(t *testing.T) {
+	for k := 0.0; k < 30; k++ {
+		lf, _ := math.Lgamma(k + 1)
+		want := lf - ((k+0.5)*math.Log(k+1) - (k + 1) + math.Log(2*math.Pi)/2)
+		if got := stirlingTail(k); math.Abs(got-want) > 1e-10 {
+			t.Errorf("stirlingTail(%v) = %v, want %v", k, got, want)
+		}
+	}
+}
+
+func BenchmarkBinomial(b *testing.B) {
+	for _, n := range []uint64{10, 1e3, 1e9} {
+		b.Run(fmt.Sprintf("n=%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_ = Binomial(n, 0.3)
+			}
+		})
+	}
+}
+
+func ExampleBinomial() {
+	// Simulate a billion flips of a coin biased 3:1 towards heads.
+	heads := Binomial(1e9, 0.75)
+	fmt.Println(heads > 749e6 && heads < 751e6)
+	// Output: true
+}
+
+func TestBinomial_clamp(t *testing.T) {
+	if got := Binomial(10, -0.5); got != 0 {
+		t.Errorf("Binomial(10, -0.5) = %d, want 0", got)
+	}
+	if got := Binomial(10, 1.5); got != 10 {
+		t.Errorf("Binomial(10, 1.5) = %d, want 10", got)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("Binomial(10, NaN) did not panic")
+		}
+	}()
+	Binomial(10, math.NaN())
+}
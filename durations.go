@@ -0,0 +1,71 @@
+package weightedrand
+
+import "math/bits"
+
+// NewChooserFromDurations initializes a new Chooser which picks each item with
+// probability proportional to its duration, such as an observed service time.
+// Durations < 1 are ignored.
+//
+// Any ~int64 type, such as time.Duration, may be used directly as a Choice
+// Weight, but sums of nanoseconds overflow an int after a few seconds on
+// 32-bit platforms, and after a few centuries on 64-bit ones. Rather than
+// failing, durations are first divided by their greatest common divisor, which
+// preserves their proportions exactly, and then if their sum still cannot be
+// represented, scaled down by the smallest sufficient power of two, rounding
+// to the nearest unit but never below one. The weights reported by the
+// Chooser's Choices are these reduced weights.
+func NewChooserFromDurations[T comparable, D ~int64](durations map[T]D) (*Chooser[T, D], error) {
+	choices := make([]Choice[T, D], 0, len(durations))
+	var g uint64
+	for item, d := range durations {
+		choices = append(choices, NewChoice(item, d))
+		if d > 0 {
+			g = gcd(g, uint64(d))
+		}
+	}
+	if g == 0 {
+		return nil, errNoValidChoices
+	}
+
+	var hi, lo uint64 // 128-bit sum of the reduced durations
+	var n uint64      // number of positive durations
+	for i := range choices {
+		if choices[i].Weight > 0 {
+			choices[i].Weight /= D(g)
+			var carry uint64
+			lo, carry = bits.Add64(lo, uint64(choices[i].Weight), 0)
+			hi += carry
+			n++
+		}
+	}
+
+	// Rounding up may add at most one unit per duration, so find the
+	// smallest shift for which the scaled sum plus n fits.
+	var shift uint
+	for hi != 0 || lo >= maxInt-n {
+		lo = lo>>1 | hi<<63
+		hi >>= 1
+		shift++
+	}
+	if shift > 0 {
+		for i := range choices {
+			if w := choices[i].Weight; w > 0 {
+				w = w>>shift + (w>>(shift-1))&1 // round half up without overflow
+				if w < 1 {
+					w = 1
+				}
+				choices[i].Weight = w
+			}
+		}
+	}
+	return NewChooser(choices...)
+}
+
+// gcd returns the greatest common divisor of a and b, or the other if
+// either is zero.
+func gcd(a, b uint64) uint64 {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
@@ -0,0 +1,88 @@
+package weightedrand
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+)
+
+func ExampleNewChooserFromDurations() {
+	latencies := map[string]time.Duration{
+		"db":    40 * time.Millisecond,
+		"cache": 0,
+	}
+	chooser, _ := NewChooserFromDurations(latencies)
+	fmt.Println(chooser.Pick())
+	// Output: db
+}
+
+func TestNewChooserFromDurations(t *testing.T) {
+	type weights map[string]time.Duration
+	tests := []struct {
+		name      string
+		durations weights
+		want      weights
+		wantErr   error
+	}{
+		{
+			name:      "reduced by gcd",
+			durations: weights{"a": 2 * time.Second, "b": 3 * time.Second, "c": -time.Second},
+			want:      weights{"a": 2, "b": 3, "c": -time.Second},
+		},
+		{
+			name:      "coprime",
+			durations: weights{"a": 7, "b": 10, "c": 0},
+			want:      weights{"a": 7, "b": 10, "c": 0},
+		},
+		{
+			name:      "sum overflows",
+			durations: weights{"a": math.MaxInt64, "b": math.MaxInt64 - 1, "c": 1},
+			want:      weights{"a": 1 << 61, "b": 1 << 61, "c": 1},
+		},
+		{name: "none positive", durations: weights{"a": 0, "b": -1}, wantErr: errNoValidChoices},
+		{name: "empty", durations: weights{}, wantErr: errNoValidChoices},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewChooserFromDurations(tt.durations)
+			if err != tt.wantErr {
+				t.Fatalf("NewChooserFromDurations() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			for _, choice := range c.Choices() {
+				if want := tt.want[choice.Item]; choice.Weight != want {
+					t.Errorf("weight of %s = %d, want %d", choice.Item, choice.Weight, want)
+				}
+			}
+		})
+	}
+}
+
+// Many centuries-long durations overflow even a 64-bit sum, but keep their
+// proportions after scaling.
+func TestNewChooserFromDurations_proportions(t *testing.T) {
+	const unit = 20 * 365 * 24 * time.Hour
+	durations := map[int]time.Duration{0: 1, 1: 1 * unit, 2: 2 * unit, 3: 3 * unit}
+	for i := 4; i < 100; i++ {
+		durations[i] = 14 * unit
+	}
+	c, err := NewChooserFromDurations(durations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	weights := make(map[int]time.Duration)
+	for _, choice := range c.Choices() {
+		weights[choice.Item] = choice.Weight
+	}
+	if weights[0] != 1 {
+		t.Errorf("weight of shortest duration = %d, want 1", weights[0])
+	}
+	for i := 2; i <= 3; i++ {
+		if got := float64(weights[i]) / float64(weights[1]); math.Abs(got-float64(i)) > 1e-9 {
+			t.Errorf("weight ratio %d:1 = %v, want %d", i, got, i)
+		}
+	}
+}
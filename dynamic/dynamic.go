@@ -0,0 +1,244 @@
+// Package dynamic provides a weighted random chooser whose choices can be
+// added, updated and removed after construction, including choices which
+// automatically expire after a time-to-live or a number of picks.
+//
+// Unlike weightedrand.Chooser, which is immutable and optimized for repeated
+// picks from a fixed set, choices are stored in a Fenwick tree so that both
+// picks and updates take O(log n) time.
+//
+// A Window similarly chooses among only the most recent offers of a stream of
+// weighted items, such as to weight decisions by recent traffic, and a
+// NoRepeat chooses among all but its own most recent picks.
+package dynamic
+
+import (
+	"container/heap"
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/mroth/weightedrand/v2"
+	"github.com/mroth/weightedrand/v2/internal/constraints"
+)
+
+const maxInt = int(^uint(0) >> 1)
+
+// ErrWeightOverflow is returned when adding a choice would cause the sum of
+// all weights to exceed the maximum int value for the current platform.
+var ErrWeightOverflow = errors.New("dynamic: sum of weights exceeds max int")
+
+// A Chooser is a mutable set of weighted choices. As with weightedrand.Chooser,
+// only choices with a weight >= 1 can be picked. It is safe for concurrent
+// usage.
+type Chooser[T comparable, W constraints.Integer] struct {
+	now func() time.Time
+
+	mu      sync.Mutex
+	items   []T
+	weights []W
+	expires []time.Time // zero if the choice never expires
+	limits  []int       // remaining picks, or zero if unlimited
+	index   map[T]int
+	tree    fenwick
+	expiry  expiryHeap[T]
+}
+
+// NewChooser initializes a new Chooser with the provided initial choices.
+// Choices with duplicate items are resolved in favor of the last.
+func NewChooser[T comparable, W constraints.Integer](choices ...weightedrand.Choice[T, W]) (*Chooser[T, W], error) {
+	c := &Chooser[T, W]{now: time.Now, index: make(map[T]int, len(choices))}
+	for _, choice := range choices {
+		if err := c.Add(choice.Item, choice.Weight); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// Add adds item with the given weight, or updates its weight if already
+// present. Any previous expiry or capacity for the item is cleared.
+func (c *Chooser[T, W]) Add(item T, weight W) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.set(item, weight, time.Time{}, 0)
+}
+
+// AddLimited is like Add, but the item will automatically be removed once it
+// has been picked capacity times, such as for a pool of prizes. Since picking
+// and decrementing the capacity are atomic, concurrent picks can never select
+// an item more than capacity times. If capacity < 1, item is removed.
+func (c *Chooser[T, W]) AddLimited(item T, weight W, capacity int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if capacity < 1 {
+		if i, ok := c.index[item]; ok {
+			c.remove(i)
+		}
+		return nil
+	}
+	return c.set(item, weight, time.Time{}, capacity)
+}
+
+// Remaining returns the number of times item may still be picked if it was
+// added with AddLimited. If item is unknown or was added without a capacity,
+// it returns false.
+func (c *Chooser[T, W]) Remaining(item T) (int, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.prune()
+	i, ok := c.index[item]
+	if !ok || c.limits[i] == 0 {
+		return 0, false
+	}
+	return c.limits[i], true
+}
+
+// AddWithTTL is like Add, but the item will automatically be removed once ttl
+// has elapsed.
+func (c *Chooser[T, W]) AddWithTTL(item T, weight W, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	expires := c.now().Add(ttl)
+	if err := c.set(item, weight, expires, 0); err != nil {
+		return err
+	}
+	heap.Push(&c.expiry, expiryEntry[T]{item: item, expires: expires})
+	return nil
+}
+
+// Remove removes item, reporting whether it was present.
+func (c *Chooser[T, W]) Remove(item T) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	i, ok := c.index[item]
+	if ok {
+		c.remove(i)
+	}
+	return ok
+}
+
+// Len returns the number of choices, including any with a weight < 1.
+func (c *Chooser[T, W]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.prune()
+	return len(c.items)
+}
+
+// Pick returns a single weighted random item. If there are no choices with a
+// weight >= 1, it returns the zero value of T and false.
+func (c *Chooser[T, W]) Pick() (T, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.prune()
+	total := c.tree.total()
+	if total < 1 {
+		var zero T
+		return zero, false
+	}
+	i := c.tree.search(rand.Intn(total))
+	item := c.items[i]
+	if c.limits[i] > 0 {
+		c.limits[i]--
+		if c.limits[i] == 0 {
+			c.remove(i)
+		}
+	}
+	return item, true
+}
+
+// effective returns the internal weight for w, ignoring weights < 1.
+func effective[W constraints.Integer](w W) int {
+	if w < 1 {
+		return 0
+	}
+	return int(w)
+}
+
+// set adds or updates item, with a capacity of limit picks if limit > 0.
+// Callers must hold c.mu.
+func (c *Chooser[T, W]) set(item T, weight W, expires time.Time, limit int) error {
+	if weight >= 1 && uint64(weight) > uint64(maxInt) {
+		return ErrWeightOverflow
+	}
+	w := effective(weight)
+	i, ok := c.index[item]
+	old := 0
+	if ok {
+		old = effective(c.weights[i])
+	}
+	if w-old > maxInt-c.tree.total() {
+		return ErrWeightOverflow
+	}
+
+	if ok {
+		c.weights[i] = weight
+		c.expires[i] = expires
+		c.limits[i] = limit
+		c.tree.add(i, w-old)
+		return nil
+	}
+	c.index[item] = len(c.items)
+	c.items = append(c.items, item)
+	c.weights = append(c.weights, weight)
+	c.expires = append(c.expires, expires)
+	c.limits = append(c.limits, limit)
+	c.tree.push(w)
+	return nil
+}
+
+// remove removes the choice at index i by moving the last choice into its
+// place. Callers must hold c.mu.
+func (c *Chooser[T, W]) remove(i int) {
+	last := len(c.items) - 1
+	delete(c.index, c.items[i])
+	if i != last {
+		c.tree.add(i, effective(c.weights[last])-effective(c.weights[i]))
+		c.items[i] = c.items[last]
+		c.weights[i] = c.weights[last]
+		c.expires[i] = c.expires[last]
+		c.limits[i] = c.limits[last]
+		c.index[c.items[i]] = i
+	}
+	var zero T
+	c.items[last] = zero // release for garbage collection
+	c.items = c.items[:last]
+	c.weights = c.weights[:last]
+	c.expires = c.expires[:last]
+	c.limits = c.limits[:last]
+	c.tree.pop()
+}
+
+// prune removes all expired choices. Callers must hold c.mu.
+func (c *Chooser[T, W]) prune() {
+	now := c.now()
+	for len(c.expiry) > 0 && !c.expiry[0].expires.After(now) {
+		e := heap.Pop(&c.expiry).(expiryEntry[T])
+		// The item may since have been removed, or re-added with a different
+		// expiry, in which case this entry is obsolete.
+		if i, ok := c.index[e.item]; ok && c.expires[i].Equal(e.expires) {
+			c.remove(i)
+		}
+	}
+}
+
+type expiryEntry[T any] struct {
+	item    T
+	expires time.Time
+}
+
+// expiryHeap is a min-heap of expiry times, implementing heap.Interface.
+type expiryHeap[T any] []expiryEntry[T]
+
+func (h expiryHeap[T]) Len() int            { return len(h) }
+func (h expiryHeap[T]) Less(i, j int) bool  { return h[i].expires.Before(h[j].expires) }
+func (h expiryHeap[T]) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *expiryHeap[T]) Push(x interface{}) { *h = append(*h, x.(expiryEntry[T])) }
+func (h *expiryHeap[T]) Pop() interface{} {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
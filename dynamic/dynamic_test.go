@@ -0,0 +1,150 @@
+package dynamic
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mroth/weightedrand/v2"
+)
+
+func TestNewChooser(t *testing.T) {
+	c, err := NewChooser(
+		weightedrand.NewChoice("a", 1),
+		weightedrand.NewChoice("b", -1),
+		weightedrand.NewChoice("a", 0),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := c.Len(); n != 2 {
+		t.Errorf("Len() = %d, want 2", n)
+	}
+	if got, ok := c.Pick(); ok {
+		t.Errorf("Pick() = %q, %v with no positive weights, want false", got, ok)
+	}
+
+	const maxUint64 = 1<<64 - 1
+	if _, err := NewChooser(weightedrand.NewChoice("a", uint64(maxUint64))); err != ErrWeightOverflow {
+		t.Errorf("NewChooser() error = %v, want %v", err, ErrWeightOverflow)
+	}
+	if _, err := NewChooser(weightedrand.NewChoice("a", maxInt), weightedrand.NewChoice("b", 1)); err != ErrWeightOverflow {
+		t.Errorf("NewChooser() error = %v, want %v", err, ErrWeightOverflow)
+	}
+}
+
+func TestChooser_Pick(t *testing.T) {
+	c, _ := NewChooser[string, int]()
+	for i, item := range []string{"a", "b", "c", "d"} {
+		if err := c.Add(item, i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c.Remove("b")
+	c.Add("d", 2)
+	if c.Remove("z") {
+		t.Error("Remove() reported unknown item as present")
+	}
+
+	counts := make(map[string]int)
+	for i := 0; i < 30000; i++ {
+		item, ok := c.Pick()
+		if !ok {
+			t.Fatal("Pick() returned false")
+		}
+		counts[item]++
+	}
+	if counts["a"] != 0 || counts["b"] != 0 {
+		t.Errorf("picked zero weight or removed items: %v", counts)
+	}
+	if counts["c"] < 14000 || counts["d"] < 14000 {
+		t.Errorf("expected c and d to be picked evenly, got %v", counts)
+	}
+}
+
+func TestChooser_AddWithTTL(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c, _ := NewChooser[string, int]()
+	c.now = func() time.Time { return now }
+
+	c.AddWithTTL("promo", 1000, time.Hour)
+	c.AddWithTTL("renewed", 1000, time.Hour)
+	c.AddWithTTL("made-permanent", 1000, time.Hour)
+	c.Add("base", 1)
+	now = now.Add(30 * time.Minute)
+	c.AddWithTTL("renewed", 1000, time.Hour)
+	c.Add("made-permanent", 1000)
+
+	now = now.Add(45 * time.Minute)
+	if n := c.Len(); n != 3 {
+		t.Errorf("Len() = %d, want 3", n)
+	}
+	for i := 0; i < 1000; i++ {
+		if item, _ := c.Pick(); item == "promo" {
+			t.Fatal("picked expired item")
+		}
+	}
+
+	now = now.Add(time.Hour)
+	if n := c.Len(); n != 2 {
+		t.Errorf("Len() = %d after renewal expired, want 2", n)
+	}
+}
+
+func TestChooser_AddLimited(t *testing.T) {
+	c, _ := NewChooser[string, int]()
+	if err := c.AddLimited("prize", 1, 3); err != nil {
+		t.Fatal(err)
+	}
+	if n, ok := c.Remaining("prize"); !ok || n != 3 {
+		t.Errorf("Remaining() = %d, %v, want 3, true", n, ok)
+	}
+	for i := 0; i < 3; i++ {
+		if got, ok := c.Pick(); !ok || got != "prize" {
+			t.Fatalf("Pick() = %q, %v, want prize, true", got, ok)
+		}
+	}
+	if got, ok := c.Pick(); ok {
+		t.Errorf("Pick() = %q after capacity exhausted, want false", got)
+	}
+	if _, ok := c.Remaining("prize"); ok {
+		t.Error("Remaining() reported exhausted item")
+	}
+
+	// Add clears the capacity, and a capacity < 1 removes the item
+	_ = c.AddLimited("a", 1, 1)
+	_ = c.Add("a", 1)
+	if _, ok := c.Remaining("a"); ok {
+		t.Error("Remaining() reported capacity cleared by Add")
+	}
+	_ = c.AddLimited("a", 1, 0)
+	if c.Len() != 0 {
+		t.Errorf("Len() = %d after AddLimited with zero capacity, want 0", c.Len())
+	}
+}
+
+func TestChooser_AddLimited_concurrent(t *testing.T) {
+	c, _ := NewChooser(weightedrand.NewChoice("consolation", 1))
+	_ = c.AddLimited("grand prize", 1_000, 10)
+
+	var mu sync.Mutex
+	won := 0
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				if item, _ := c.Pick(); item == "grand prize" {
+					mu.Lock()
+					won++
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if won != 10 {
+		t.Errorf("grand prize won %d times, want exactly 10", won)
+	}
+}
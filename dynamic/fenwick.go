@@ -0,0 +1,64 @@
+package dynamic
+
+// fenwick is a Fenwick (binary indexed) tree of non-negative weights,
+// supporting point updates, appends and weighted search in O(log n).
+//
+// Internally the tree is 1-indexed, with tree[0] unused.
+type fenwick struct {
+	tree []int
+}
+
+func (f *fenwick) len() int { return len(f.tree) - 1 }
+
+// prefix returns the sum of the first n weights.
+func (f *fenwick) prefix(n int) int {
+	sum := 0
+	for ; n > 0; n -= n & -n {
+		sum += f.tree[n]
+	}
+	return sum
+}
+
+// total returns the sum of all weights.
+func (f *fenwick) total() int { return f.prefix(f.len()) }
+
+// add adds delta to the weight at index i.
+func (f *fenwick) add(i, delta int) {
+	for n := i + 1; n < len(f.tree); n += n & -n {
+		f.tree[n] += delta
+	}
+}
+
+// push appends a new weight w.
+func (f *fenwick) push(w int) {
+	if len(f.tree) == 0 {
+		f.tree = append(f.tree, 0)
+	}
+	n := len(f.tree) // 1-based index of new node
+	// The new node covers the range (n - lowbit(n), n].
+	node := w + f.prefix(n-1) - f.prefix(n-(n&-n))
+	f.tree = append(f.tree, node)
+}
+
+// pop removes the last weight. Since no other node covers a range ending at
+// the final index, the remaining tree stays valid.
+func (f *fenwick) pop() {
+	f.tree = f.tree[:len(f.tree)-1]
+}
+
+// search returns the smallest index i such that the sum of weights [0..i] is
+// greater than r, for 0 <= r < total().
+func (f *fenwick) search(r int) int {
+	pos := 0
+	step := 1
+	for step<<1 < len(f.tree) {
+		step <<= 1
+	}
+	for ; step > 0; step >>= 1 {
+		if next := pos + step; next < len(f.tree) && f.tree[next] <= r {
+			pos = next
+			r -= f.tree[next]
+		}
+	}
+	return pos // 1-based pos of last node with prefix <= r, thus 0-based answer
+}
@@ -0,0 +1,55 @@
+package dynamic
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// naiveSearch is the linear reference implementation of fenwick.search.
+func naiveSearch(weights []int, r int) int {
+	for i, w := range weights {
+		if r < w {
+			return i
+		}
+		r -= w
+	}
+	return len(weights)
+}
+
+func TestFenwick(t *testing.T) {
+	var f fenwick
+	var weights []int
+	check := func() {
+		t.Helper()
+		sum := 0
+		for i, w := range weights {
+			sum += w
+			if got := f.prefix(i + 1); got != sum {
+				t.Fatalf("prefix(%d) = %d, want %d", i+1, got, sum)
+			}
+		}
+		for r := 0; r < sum; r++ {
+			if got, want := f.search(r), naiveSearch(weights, r); got != want {
+				t.Fatalf("search(%d) = %d, want %d (weights %v)", r, got, want, weights)
+			}
+		}
+	}
+
+	for i := 0; i < 200; i++ {
+		switch op := rand.Intn(4); {
+		case op == 0 && len(weights) > 0:
+			f.pop()
+			weights = weights[:len(weights)-1]
+		case op == 1 && len(weights) > 0:
+			j := rand.Intn(len(weights))
+			delta := rand.Intn(5) - weights[j]
+			f.add(j, delta)
+			weights[j] += delta
+		default:
+			w := rand.Intn(5)
+			f.push(w)
+			weights = append(weights, w)
+		}
+		check()
+	}
+}
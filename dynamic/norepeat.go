@@ -0,0 +1,93 @@
+package dynamic
+
+import (
+	"errors"
+	"math/rand"
+	"sync"
+
+	"github.com/mroth/weightedrand/v2"
+	"github.com/mroth/weightedrand/v2/internal/constraints"
+)
+
+// ErrRecentWindow is returned by NewNoRepeat when the number of recent picks to
+// exclude is negative, or not less than the number of choices which can be
+// picked, so that at some point there would be nothing left to pick.
+var ErrRecentWindow = errors.New("dynamic: recent picks to exclude must be fewer than choices with weight >= 1")
+
+// A NoRepeat is a weighted random chooser which never picks any of its last k
+// picks again, such as for ad rotation where the same creative must not appear
+// twice within k impressions. Each pick is among the other choices, with
+// probability proportional to their weights.
+//
+// Rather than rejecting and retrying repeated picks, the recent picks are
+// removed from a Fenwick tree of weights until they leave the window, so each
+// pick takes O(log n) time however heavily the recent picks are weighted. As
+// with weightedrand.Chooser, only choices with a weight >= 1 can be picked. A
+// NoRepeat is safe for concurrent usage.
+type NoRepeat[T comparable, W constraints.Integer] struct {
+	mu      sync.Mutex
+	items   []T
+	weights []int
+	tree    fenwick
+	recent  []int // ring of the indices of the last k picks
+	next    int   // position in recent of the next pick
+	count   int   // number of picks in recent
+}
+
+// NewNoRepeat initializes a NoRepeat excluding the last k picks, from the
+// provided choices. Choices with duplicate items are resolved in favor of the
+// last.
+func NewNoRepeat[T comparable, W constraints.Integer](k int, choices ...weightedrand.Choice[T, W]) (*NoRepeat[T, W], error) {
+	index := make(map[T]int, len(choices))
+	c := &NoRepeat[T, W]{}
+	for _, choice := range choices {
+		if choice.Weight >= 1 && uint64(choice.Weight) > uint64(maxInt) {
+			return nil, ErrWeightOverflow
+		}
+		if i, ok := index[choice.Item]; ok {
+			c.weights[i] = effective(choice.Weight)
+			continue
+		}
+		index[choice.Item] = len(c.items)
+		c.items = append(c.items, choice.Item)
+		c.weights = append(c.weights, effective(choice.Weight))
+	}
+
+	pickable, total := 0, 0
+	for _, w := range c.weights {
+		if w > 0 {
+			pickable++
+		}
+		if w > maxInt-total {
+			return nil, ErrWeightOverflow
+		}
+		total += w
+		c.tree.push(w)
+	}
+	if k < 0 || k >= pickable {
+		return nil, ErrRecentWindow
+	}
+	c.recent = make([]int, k)
+	return c, nil
+}
+
+// Pick returns a single weighted random item, other than any of the last k
+// picked.
+func (c *NoRepeat[T, W]) Pick() T {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	i := c.tree.search(rand.Intn(c.tree.total()))
+	if k := len(c.recent); k > 0 {
+		if c.count == k {
+			// The oldest pick leaves the window.
+			old := c.recent[c.next]
+			c.tree.add(old, c.weights[old])
+		} else {
+			c.count++
+		}
+		c.recent[c.next] = i
+		c.next = (c.next + 1) % k
+		c.tree.add(i, -c.weights[i])
+	}
+	return c.items[i]
+}
@@ -0,0 +1,129 @@
+package dynamic
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"testing"
+
+	"github.com/mroth/weightedrand/v2"
+)
+
+func ExampleNoRepeat() {
+	ads, _ := NewNoRepeat(1,
+		weightedrand.NewChoice("shoes", 1_000_000_000),
+		weightedrand.NewChoice("hats", 1),
+	)
+	for i := 0; i < 4; i++ {
+		fmt.Println(ads.Pick())
+	}
+	// Output:
+	// shoes
+	// hats
+	// shoes
+	// hats
+}
+
+func TestNewNoRepeat(t *testing.T) {
+	choices := []weightedrand.Choice[string, int]{
+		weightedrand.NewChoice("a", 1),
+		weightedrand.NewChoice("b", 2),
+		weightedrand.NewChoice("c", 0),
+		weightedrand.NewChoice("b", 3),
+	}
+	tests := []struct {
+		k       int
+		wantErr error
+	}{
+		{k: -1, wantErr: ErrRecentWindow},
+		{k: 0},
+		{k: 1},
+		{k: 2, wantErr: ErrRecentWindow}, // only a and b can be picked
+	}
+	for _, tt := range tests {
+		if _, err := NewNoRepeat(tt.k, choices...); err != tt.wantErr {
+			t.Errorf("NewNoRepeat(%d) error = %v, want %v", tt.k, err, tt.wantErr)
+		}
+	}
+
+	_, err := NewNoRepeat(0, weightedrand.NewChoice("a", maxInt), weightedrand.NewChoice("b", 1))
+	if err != ErrWeightOverflow {
+		t.Errorf("NewNoRepeat() error = %v, want %v", err, ErrWeightOverflow)
+	}
+}
+
+func TestNoRepeat_Pick(t *testing.T) {
+	const k, n = 3, 100000
+	weights := []int{1, 2, 3, 4, 50, 0}
+	choices := make([]weightedrand.Choice[int, int], len(weights))
+	for i, w := range weights {
+		choices[i] = weightedrand.NewChoice(i, w)
+	}
+	c, err := NewNoRepeat(k, choices...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var last []int
+	counts := make([]int, len(weights))
+	for i := 0; i < n; i++ {
+		item := c.Pick()
+		for _, prev := range last {
+			if item == prev {
+				t.Fatalf("Pick() = %d, repeated within last %d picks %v", item, k, last)
+			}
+		}
+		if last = append(last, item); len(last) > k {
+			last = last[1:]
+		}
+		counts[item]++
+	}
+	if counts[5] != 0 {
+		t.Errorf("picked zero weight item %d times", counts[5])
+	}
+	// The heavy item can be picked at most once in any k+1 picks, and is
+	// picked nearly every time it is not excluded.
+	if got := counts[4]; got > n/(k+1)+1 || got < n/5 {
+		t.Errorf("heavy item picked %d times, want nearly %d", got, n/(k+1))
+	}
+}
+
+func TestNoRepeat_zero(t *testing.T) {
+	// Excluding no picks is an ordinary weighted choice.
+	c, err := NewNoRepeat(0, weightedrand.NewChoice("a", 1), weightedrand.NewChoice("b", 3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	const n = 100000
+	b := 0
+	for i := 0; i < n; i++ {
+		if c.Pick() == "b" {
+			b++
+		}
+	}
+	if got := float64(b) / n; math.Abs(got-0.75) > 0.01 {
+		t.Errorf("frequency of b = %.3f, want 0.75", got)
+	}
+}
+
+func TestNoRepeat_concurrent(t *testing.T) {
+	c, err := NewNoRepeat(2,
+		weightedrand.NewChoice("a", 1),
+		weightedrand.NewChoice("b", 1),
+		weightedrand.NewChoice("c", 1),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				c.Pick()
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,130 @@
+package dynamic
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/mroth/weightedrand/v2/internal/constraints"
+)
+
+// A Window is a weighted random chooser over only the most recently offered
+// items: the last size offers, and if maxAge is positive only those made within
+// maxAge, such as to favor destinations by their recent traffic. Older offers
+// drop out automatically as new ones are made or time passes.
+//
+// Each offer counts separately, so an item offered many times is picked with
+// probability proportional to the sum of the weights of its offers within the
+// window. As with weightedrand.Chooser, only offers with a weight >= 1 can be
+// picked.
+//
+// Offers occupy slots of a ring of the given size, in a Fenwick tree, so that
+// both offers and picks take O(log size) time. A Window is safe for concurrent
+// usage.
+type Window[T any, W constraints.Integer] struct {
+	maxAge time.Duration
+	now    func() time.Time
+
+	mu      sync.Mutex
+	items   []T
+	weights []int       // effective weight of each slot, or zero once expired
+	times   []time.Time // time of each offer, if maxAge > 0
+	tree    fenwick
+	next    int // slot of the next offer
+	count   int // number of unexpired offers, ending at the slot before next
+}
+
+// NewWindow initializes an empty Window over the last size offers, within
+// maxAge if positive. For a window bounded only by time, size must be large
+// enough to hold all the offers made within maxAge. It panics if size is not
+// positive.
+func NewWindow[T any, W constraints.Integer](size int, maxAge time.Duration) *Window[T, W] {
+	if size <= 0 {
+		panic("dynamic: non-positive window size")
+	}
+	w := &Window[T, W]{
+		maxAge:  maxAge,
+		now:     time.Now,
+		items:   make([]T, size),
+		weights: make([]int, size),
+	}
+	if maxAge > 0 {
+		w.times = make([]time.Time, size)
+	}
+	for i := 0; i < size; i++ {
+		w.tree.push(0)
+	}
+	return w
+}
+
+// Offer adds item with the given weight to the window, displacing the oldest
+// offer if the window is full.
+func (w *Window[T, W]) Offer(item T, weight W) error {
+	if weight >= 1 && uint64(weight) > uint64(maxInt) {
+		return ErrWeightOverflow
+	}
+	wt := effective(weight)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.prune()
+	i := w.next
+	if wt-w.weights[i] > maxInt-w.tree.total() {
+		return ErrWeightOverflow
+	}
+	w.tree.add(i, wt-w.weights[i])
+	w.items[i] = item
+	w.weights[i] = wt
+	if w.times != nil {
+		w.times[i] = w.now()
+	}
+	w.next = (i + 1) % len(w.items)
+	if w.count < len(w.items) {
+		w.count++
+	}
+	return nil
+}
+
+// Len returns the number of offers within the window, including any with a
+// weight < 1.
+func (w *Window[T, W]) Len() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.prune()
+	return w.count
+}
+
+// Pick returns a single weighted random item from the offers within the
+// window. If there are no such offers with a weight >= 1, it returns the zero
+// value of T and false.
+func (w *Window[T, W]) Pick() (T, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.prune()
+	total := w.tree.total()
+	if total < 1 {
+		var zero T
+		return zero, false
+	}
+	return w.items[w.tree.search(rand.Intn(total))], true
+}
+
+// prune expires the offers older than maxAge, oldest first. Callers must hold
+// w.mu.
+func (w *Window[T, W]) prune() {
+	if w.times == nil {
+		return
+	}
+	cutoff := w.now().Add(-w.maxAge)
+	for w.count > 0 {
+		i := (w.next - w.count + len(w.items)) % len(w.items)
+		if w.times[i].After(cutoff) {
+			return
+		}
+		var zero T
+		w.tree.add(i, -w.weights[i])
+		w.items[i] = zero // release for garbage collection
+		w.weights[i] = 0
+		w.count--
+	}
+}
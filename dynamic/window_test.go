@@ -0,0 +1,126 @@
+package dynamic
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+)
+
+func ExampleWindow() {
+	// Route by the traffic of the last 3 requests.
+	w := NewWindow[string, int](3, 0)
+	for _, backend := range []string{"old", "new", "new", "new"} {
+		w.Offer(backend, 1)
+	}
+	fmt.Println(w.Pick())
+	// Output: new true
+}
+
+func TestNewWindow(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("NewWindow(0) did not panic")
+		}
+	}()
+	NewWindow[string, int](0, 0)
+}
+
+func TestWindow_size(t *testing.T) {
+	w := NewWindow[int, int](4, 0)
+	if _, ok := w.Pick(); ok {
+		t.Error("Pick() from empty window returned true")
+	}
+	w.Offer(-1, 0)
+	if _, ok := w.Pick(); ok {
+		t.Error("Pick() returned true with only zero weight offers")
+	}
+
+	// Offer items 0..9 with weight i+1; only the last four remain.
+	for i := 0; i < 10; i++ {
+		if err := w.Offer(i, i+1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := w.Len(); n != 4 {
+		t.Errorf("Len() = %d, want 4", n)
+	}
+	const n = 100000
+	counts := make(map[int]int)
+	for i := 0; i < n; i++ {
+		item, ok := w.Pick()
+		if !ok {
+			t.Fatal("Pick() returned false")
+		}
+		counts[item]++
+	}
+	for item := 0; item < 10; item++ {
+		want := 0.0
+		if item >= 6 {
+			want = float64(item+1) / (7 + 8 + 9 + 10)
+		}
+		if got := float64(counts[item]) / n; math.Abs(got-want) > 0.01 {
+			t.Errorf("item %d frequency = %.3f, want %.3f", item, got, want)
+		}
+	}
+}
+
+func TestWindow_maxAge(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	w := NewWindow[string, int](10, time.Minute)
+	w.now = func() time.Time { return now }
+
+	w.Offer("stale", 100)
+	now = now.Add(30 * time.Second)
+	w.Offer("recent", 1)
+	w.Offer("recent", 0)
+	if n := w.Len(); n != 3 {
+		t.Errorf("Len() = %d, want 3", n)
+	}
+
+	now = now.Add(30 * time.Second)
+	if n := w.Len(); n != 2 {
+		t.Errorf("Len() = %d after first offer expired, want 2", n)
+	}
+	for i := 0; i < 1000; i++ {
+		if item, _ := w.Pick(); item != "recent" {
+			t.Fatalf("Pick() = %q, want only unexpired item", item)
+		}
+	}
+
+	now = now.Add(time.Minute)
+	if _, ok := w.Pick(); ok {
+		t.Error("Pick() returned true after all offers expired")
+	}
+	if n := w.Len(); n != 0 {
+		t.Errorf("Len() = %d, want 0", n)
+	}
+
+	// The ring wraps around correctly after expiry.
+	for i := 0; i < 25; i++ {
+		w.Offer(fmt.Sprint(i), 1)
+	}
+	if n := w.Len(); n != 10 {
+		t.Errorf("Len() = %d after refilling, want 10", n)
+	}
+}
+
+func TestWindow_overflow(t *testing.T) {
+	w := NewWindow[string, uint64](2, 0)
+	if err := w.Offer("huge", math.MaxUint64); err != ErrWeightOverflow {
+		t.Errorf("Offer() error = %v, want %v", err, ErrWeightOverflow)
+	}
+	if err := w.Offer("a", uint64(maxInt)); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Offer("b", 1); err != ErrWeightOverflow {
+		t.Errorf("Offer() error = %v, want %v", err, ErrWeightOverflow)
+	}
+	// Once the heavy offer is displaced, the total is in range again.
+	if err := w.Offer("c", 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Offer("d", 1); err != nil {
+		t.Errorf("Offer() displacing the heavy offer error = %v", err)
+	}
+}
@@ -0,0 +1,143 @@
+// Package ewma provides latency-aware weighted routing, where the weight of
+// each item is continuously adjusted by an exponentially weighted moving
+// average of the latencies reported for it, so that slower items receive
+// proportionally less traffic.
+package ewma
+
+import (
+	"errors"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/mroth/weightedrand/v2"
+	"github.com/mroth/weightedrand/v2/internal/constraints"
+)
+
+// ErrSmoothing is returned by New for a smoothing factor outside (0, 1].
+var ErrSmoothing = errors.New("ewma: smoothing factor must be in (0, 1]")
+
+// RebuildInterval is the minimum interval between rebuilds of a Router's
+// underlying Chooser, bounding the cost of frequent observations.
+const RebuildInterval = 100 * time.Millisecond
+
+// A Router picks items with probability proportional to their base weight
+// divided by the moving average of their observed latency. It is safe for
+// concurrent usage.
+//
+// Items which have not yet been observed are optimistically assumed to be as
+// fast as the fastest observed item, so that they receive traffic from which
+// their latency can be learned. The underlying Chooser is rebuilt lazily on
+// the first Pick at least RebuildInterval after an observation.
+type Router[T comparable] struct {
+	alpha float64
+	now   func() time.Time
+
+	mu      sync.Mutex
+	items   []T
+	index   map[T]int
+	logBase []float64 // log of the base weight of each item
+	avg     []float64 // moving average latency in nanoseconds, 0 if unobserved
+	table   *weightedrand.Chooser[T, int]
+	dirty   bool
+	built   time.Time
+}
+
+// New initializes a Router over choices, whose weights are the base weights of
+// the items before adjustment for latency. Choices with a weight < 1 are
+// excluded. The smoothing factor alpha, in the range (0, 1], is the weight of
+// each new observation in the moving average, so higher values respond more
+// quickly to changes in latency.
+func New[T comparable, W constraints.Integer](alpha float64, choices ...weightedrand.Choice[T, W]) (*Router[T], error) {
+	if !(alpha > 0 && alpha <= 1) {
+		return nil, ErrSmoothing
+	}
+	r := &Router[T]{alpha: alpha, now: time.Now, index: make(map[T]int, len(choices))}
+	for _, c := range choices {
+		if c.Weight < 1 {
+			continue
+		}
+		if _, ok := r.index[c.Item]; ok {
+			continue
+		}
+		r.index[c.Item] = len(r.items)
+		r.items = append(r.items, c.Item)
+		r.logBase = append(r.logBase, math.Log(float64(c.Weight)))
+	}
+	r.avg = make([]float64, len(r.items))
+	if err := r.rebuild(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Pick returns a weighted random item, according to the current latency
+// adjusted weights.
+func (r *Router[T]) Pick() T {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.dirty && r.now().Sub(r.built) >= RebuildInterval {
+		_ = r.rebuild() // cannot fail once New succeeded
+	}
+	return r.table.Pick()
+}
+
+// Observe records the latency d of a request to item. Unknown items are
+// ignored.
+func (r *Router[T]) Observe(item T, d time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i, ok := r.index[item]
+	if !ok {
+		return
+	}
+	ns := math.Max(float64(d), 1) // a zero latency would take all traffic
+	if r.avg[i] == 0 {
+		r.avg[i] = ns
+	} else {
+		r.avg[i] += r.alpha * (ns - r.avg[i])
+	}
+	r.dirty = true
+}
+
+// Latency returns the moving average latency of item, or false if it is
+// unknown or has not been observed.
+func (r *Router[T]) Latency(item T) (time.Duration, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i, ok := r.index[item]
+	if !ok || r.avg[i] == 0 {
+		return 0, false
+	}
+	return time.Duration(r.avg[i]), true
+}
+
+// rebuild recomputes the Chooser from the current averages. Callers must hold
+// r.mu.
+func (r *Router[T]) rebuild() error {
+	fastest := math.Inf(1)
+	for _, a := range r.avg {
+		if a > 0 {
+			fastest = math.Min(fastest, a)
+		}
+	}
+	if math.IsInf(fastest, 1) {
+		fastest = 1 // nothing observed yet, so use the base weights alone
+	}
+
+	// Work in log space, where base weight / latency is a difference, and a
+	// softmax at temperature 1 yields weights proportional to the quotient.
+	scores := make([]float64, len(r.items))
+	for i, a := range r.avg {
+		if a == 0 {
+			a = fastest
+		}
+		scores[i] = r.logBase[i] - math.Log(a)
+	}
+	table, err := weightedrand.NewSoftmaxChooser(r.items, scores, 1)
+	if err != nil {
+		return err
+	}
+	r.table, r.dirty, r.built = table, false, r.now()
+	return nil
+}
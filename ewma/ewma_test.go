@@ -0,0 +1,75 @@
+package ewma
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/mroth/weightedrand/v2"
+)
+
+// fakeClock returns a function reporting *t as the current time.
+func fakeClock(t *time.Time) func() time.Time {
+	return func() time.Time { return *t }
+}
+
+// frequencies returns the fraction of n picks of each item.
+func frequencies(r *Router[string], n int) map[string]float64 {
+	freq := make(map[string]float64)
+	for i := 0; i < n; i++ {
+		freq[r.Pick()] += 1 / float64(n)
+	}
+	return freq
+}
+
+func TestNew(t *testing.T) {
+	for _, alpha := range []float64{0, -1, 1.5, math.NaN()} {
+		if _, err := New(alpha, weightedrand.NewChoice("a", 1)); err != ErrSmoothing {
+			t.Errorf("New(%v) error = %v, want %v", alpha, err, ErrSmoothing)
+		}
+	}
+	if _, err := New(0.5, weightedrand.NewChoice("a", 0)); err == nil {
+		t.Error("New() with no positive weights error = nil")
+	}
+}
+
+func TestRouter(t *testing.T) {
+	r, err := New(0.5,
+		weightedrand.NewChoice("fast", 1),
+		weightedrand.NewChoice("slow", 1),
+		weightedrand.NewChoice("new", 2),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	r.now = fakeClock(&now)
+
+	r.Observe("fast", 10*time.Millisecond)
+	r.Observe("slow", 30*time.Millisecond)
+	r.Observe("unknown", time.Second) // ignored
+	if _, ok := r.Latency("new"); ok {
+		t.Error("Latency() of unobserved item ok = true")
+	}
+
+	// Observations are not applied until RebuildInterval has passed.
+	freq := frequencies(r, 10000)
+	if math.Abs(freq["new"]-0.5) > 0.02 {
+		t.Errorf("frequency of new before rebuild = %.3f, want 0.5", freq["new"])
+	}
+
+	// Weights are base/latency, with new assumed as fast as fast: 3:1:6.
+	now = now.Add(RebuildInterval)
+	freq = frequencies(r, 10000)
+	for item, want := range map[string]float64{"fast": 0.3, "slow": 0.1, "new": 0.6} {
+		if math.Abs(freq[item]-want) > 0.02 {
+			t.Errorf("frequency of %s = %.3f, want %.1f", item, freq[item], want)
+		}
+	}
+
+	// The moving average moves halfway to each new observation.
+	r.Observe("slow", 10*time.Millisecond)
+	if got, _ := r.Latency("slow"); got != 20*time.Millisecond {
+		t.Errorf("Latency() = %v, want 20ms", got)
+	}
+}
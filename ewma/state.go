@@ -0,0 +1,66 @@
+package ewma
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// StateVersion is the version of the State format written by Snapshot.
+const StateVersion = 1
+
+// ErrState is returned when restoring a State which is invalid or of an
+// unsupported version.
+var ErrState = errors.New("ewma: invalid state")
+
+// State is the moving average latency of each observed item of a Router, so
+// that learned latencies can survive process restarts. Its JSON encoding is
+// stable, provided the items themselves encode stably.
+type State[T comparable] struct {
+	Version   int          `json:"version"`
+	Latencies []Latency[T] `json:"latencies"`
+}
+
+// Latency is the moving average latency of an item.
+type Latency[T comparable] struct {
+	Item    T             `json:"item"`
+	Average time.Duration `json:"average_ns"`
+}
+
+// Snapshot returns the moving average latency of each item of the Router which
+// has been observed.
+func (r *Router[T]) Snapshot() State[T] {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := State[T]{Version: StateVersion, Latencies: []Latency[T]{}}
+	for i, item := range r.items {
+		if r.avg[i] > 0 {
+			s.Latencies = append(s.Latencies, Latency[T]{Item: item, Average: time.Duration(r.avg[i])})
+		}
+	}
+	return s
+}
+
+// Restore replaces the moving average latency of the items of the Router which
+// are in s, taking effect immediately. Items of s which are unknown to the
+// Router are ignored. If s is invalid, an error wrapping ErrState is returned
+// and nothing is restored.
+func (r *Router[T]) Restore(s State[T]) error {
+	if s.Version != StateVersion {
+		return fmt.Errorf("%w: unsupported version %d", ErrState, s.Version)
+	}
+	for _, l := range s.Latencies {
+		if l.Average <= 0 {
+			return fmt.Errorf("%w: latency of %v", ErrState, l.Item)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, l := range s.Latencies {
+		if i, ok := r.index[l.Item]; ok {
+			r.avg[i] = float64(l.Average)
+		}
+	}
+	return r.rebuild()
+}
@@ -0,0 +1,61 @@
+package ewma
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mroth/weightedrand/v2"
+)
+
+func TestRouter_SnapshotRestore(t *testing.T) {
+	choices := []weightedrand.Choice[string, int]{
+		weightedrand.NewChoice("a", 1),
+		weightedrand.NewChoice("b", 1),
+		weightedrand.NewChoice("unobserved", 1),
+	}
+	trained, err := New(0.5, choices...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	trained.Observe("a", 10*time.Millisecond)
+	trained.Observe("b", 40*time.Millisecond)
+
+	data, err := json.Marshal(trained.Snapshot())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"version":1,"latencies":[{"item":"a","average_ns":10000000},{"item":"b","average_ns":40000000}]}`; string(data) != want {
+		t.Errorf("encoded state = %s, want %s", data, want)
+	}
+
+	var s State[string]
+	if err := json.Unmarshal(data, &s); err != nil {
+		t.Fatal(err)
+	}
+	restored, err := New(0.5, choices...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := restored.Restore(s); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(restored.Snapshot(), trained.Snapshot()) {
+		t.Errorf("Snapshot() after Restore = %+v, want %+v", restored.Snapshot(), trained.Snapshot())
+	}
+	if restored.dirty {
+		t.Error("Restore() did not rebuild immediately")
+	}
+
+	invalid := []State[string]{
+		{Version: 2},
+		{Version: StateVersion, Latencies: []Latency[string]{{Item: "a", Average: -1}}},
+	}
+	for _, s := range invalid {
+		if err := restored.Restore(s); !errors.Is(err, ErrState) {
+			t.Errorf("Restore(%+v) error = %v, want %v", s, err, ErrState)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package weightedrand
+
+import "math/bits"
+
+// eytzinger is a copy of a Chooser's cumulative totals in Eytzinger (breadth
+// first) order: the root of an implicit binary search tree at index 1, and the
+// children of the node at index k at 2k and 2k+1. The first levels of every
+// search share a handful of cache lines at the front of the array, rather than
+// being spread across the whole array as in binary search of sorted totals.
+//
+// The tree is padded to be perfect, of 2^height-1 nodes, with totals of maxInt
+// following the real ones, so that the index of a node in the sorted totals
+// can be computed from its position rather than stored alongside it.
+type eytzinger struct {
+	keys   []int // totals from index 1
+	height uint
+}
+
+// WithEytzinger additionally stores the cumulative weight table of the Chooser
+// in Eytzinger (breadth first) order, so that picks search it with fewer cache
+// misses, at the cost of between 2 and 3 times the memory for the table. On
+// amd64, BenchmarkPick found this faster than binary search for tables of a
+// million or more choices, but slower than the guide table with which
+// Choosers of more than half a million choices are indexed by default, so it
+// is chiefly of use on platforms where that comparison differs. It is no
+// faster for small tables.
+func WithEytzinger() Option {
+	return func(cfg *config) { cfg.eytzinger = true }
+}
+
+// build lays out totals in Eytzinger order, reusing the array of any previous
+// layout when it has sufficient capacity.
+func (e *eytzinger) build(totals []int) {
+	e.height = uint(bits.Len(uint(len(totals))))
+	n := 1 << e.height
+	if cap(e.keys) < n {
+		e.keys = make([]int, n)
+	}
+	e.keys = e.keys[:n]
+
+	// An in-order traversal of the tree visits the nodes in sorted order. The
+	// depth of the recursion is logarithmic in the number of totals.
+	var fill func(k, i int) int
+	fill = func(k, i int) int {
+		if k < n {
+			i = fill(2*k, i)
+			if i < len(totals) {
+				e.keys[k] = totals[i]
+			} else {
+				e.keys[k] = maxInt
+			}
+			i = fill(2*k+1, i+1)
+		}
+		return i
+	}
+	fill(1, 0)
+}
+
+// search returns the index in the sorted totals of the first total >= x, for x
+// in [1, max].
+//
+// Unlike search over sorted totals, this branches on each comparison: the
+// children of every node are adjacent, so the speculative loads of a
+// mispredicted branch fetch the cache line needed by the correct one as well,
+// acting as a prefetch of the next level.
+func (e *eytzinger) search(x int) int {
+	keys := e.keys
+	k := 1
+	for k < len(keys) {
+		if keys[k] < x {
+			k = 2*k + 1
+		} else {
+			k = 2 * k
+		}
+	}
+	// The path taken is recorded in the bits of k, with a 1 for each right
+	// turn. The first total >= x is the node at which the path last turned
+	// left, found by discarding the trailing right turns and that left turn.
+	k >>= uint(bits.TrailingZeros(^uint(k))) + 1
+
+	// In a perfect tree, the nodes at depth d are evenly spaced in sorted
+	// order, each 2^(height-d) apart.
+	d := uint(bits.Len(uint(k))) - 1
+	return (2*(k-1<<d)+1)<<(e.height-d-1) - 1
+}
@@ -0,0 +1,74 @@
+package weightedrand
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestEytzinger_search(t *testing.T) {
+	var e eytzinger
+	for n := 1; n <= 130; n++ {
+		// Include repeated totals, as left by choices with a weight of zero.
+		a := make([]int, n)
+		total := 0
+		for i := range a {
+			total += rand.Intn(3)
+			a[i] = total
+		}
+		if a[n-1] == 0 {
+			a[n-1] = 1
+		}
+		e.build(a) // reuses the arrays of smaller tables
+		for x := 1; x <= a[n-1]; x++ {
+			if got, want := e.search(x), sort.SearchInts(a, x); got != want {
+				t.Fatalf("search(%v, %d) = %d, want %d", a, x, got, want)
+			}
+		}
+	}
+}
+
+func TestWithEytzinger(t *testing.T) {
+	choices := mockFrequencyChoices(t, testChoices)
+	chooser, err := NewChooserWithOptions(choices, WithEytzinger())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if chooser.eytz == nil {
+		t.Fatal("Chooser has no Eytzinger layout")
+	}
+	counts := make(map[int]int)
+	for i := 0; i < testIterations; i++ {
+		counts[chooser.Pick()]++
+	}
+	verifyFrequencyCounts(t, counts, choices)
+
+	// The layout must follow Reset.
+	if err := chooser.Reset(NewChoice(-1, 0), NewChoice(7, 1)); err != nil {
+		t.Fatal(err)
+	}
+	if len(chooser.eytz.keys) != 4 {
+		t.Errorf("len(keys) = %d after Reset, want 4", len(chooser.eytz.keys))
+	}
+	for i := 0; i < 10; i++ {
+		if got := chooser.Pick(); got != 7 {
+			t.Fatalf("Pick() = %d after Reset, want 7", got)
+		}
+	}
+}
+
+func BenchmarkPickEytzinger(b *testing.B) {
+	for n := BMMinChoices; n <= BMMaxChoices; n *= 10 {
+		b.Run(fmt.Sprintf("size=%s", fmt1eN(n)), func(b *testing.B) {
+			chooser, err := NewChooserWithOptions(mockChoices(n), WithEytzinger())
+			if err != nil {
+				b.Fatal(err)
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_ = chooser.Pick()
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+// Package fakepick provides a scripted weightedrand.Picker for tests, which
+// returns a predefined sequence of items rather than weighted random ones, and
+// records how it was used so that tests can make assertions about it:
+//
+//	p := fakepick.New("a", "b")
+//	result := codeUnderTest(p) // accepting a weightedrand.Picker[string]
+//	p.AssertExhausted(t)
+package fakepick
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/mroth/weightedrand/v2"
+)
+
+// TB is the subset of testing.TB used by this package.
+type TB interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+}
+
+// A Picker returns the items of its script in order. It is safe for concurrent
+// usage.
+type Picker[T any] struct {
+	mu     sync.Mutex
+	script []T
+	loop   bool
+	picked []T
+}
+
+var _ weightedrand.Picker[int] = (*Picker[int])(nil)
+
+// New returns a Picker returning the items of script in order. Picking beyond
+// the end of the script panics, so that unexpected picks fail loudly.
+func New[T any](script ...T) *Picker[T] {
+	return &Picker[T]{script: append([]T(nil), script...)}
+}
+
+// Loop returns a Picker returning the items of script in order, starting over
+// from the beginning when it reaches the end. It panics if script is empty.
+func Loop[T any](script ...T) *Picker[T] {
+	if len(script) == 0 {
+		panic("fakepick: empty script")
+	}
+	p := New(script...)
+	p.loop = true
+	return p
+}
+
+// Pick returns the next item of the script.
+func (p *Picker[T]) Pick() T {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	n := len(p.picked)
+	if !p.loop && n >= len(p.script) {
+		panic(fmt.Sprintf("fakepick: script of %d items exhausted", len(p.script)))
+	}
+	item := p.script[n%len(p.script)]
+	p.picked = append(p.picked, item)
+	return item
+}
+
+// Calls returns the number of times Pick has been called.
+func (p *Picker[T]) Calls() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.picked)
+}
+
+// Picked returns the items returned by Pick so far, in order.
+func (p *Picker[T]) Picked() []T {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]T(nil), p.picked...)
+}
+
+// Remaining returns the number of items of the script not yet picked, which is
+// always the full length of the script for a Loop.
+func (p *Picker[T]) Remaining() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.loop {
+		return len(p.script)
+	}
+	return len(p.script) - len(p.picked)
+}
+
+// AssertCalls reports an error to t unless Pick has been called exactly n
+// times.
+func (p *Picker[T]) AssertCalls(t TB, n int) {
+	t.Helper()
+	if got := p.Calls(); got != n {
+		t.Errorf("fakepick: Pick called %d times, want %d", got, n)
+	}
+}
+
+// AssertExhausted reports an error to t unless every item of the script has
+// been picked.
+func (p *Picker[T]) AssertExhausted(t TB) {
+	t.Helper()
+	if n := p.Remaining(); n > 0 && !p.loop {
+		t.Errorf("fakepick: %d of %d scripted items not picked", n, len(p.script))
+	}
+}
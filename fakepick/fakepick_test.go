@@ -0,0 +1,73 @@
+package fakepick
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/mroth/weightedrand/v2"
+)
+
+// recorder is a TB recording reported errors.
+type recorder struct{ errors []string }
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestPicker(t *testing.T) {
+	p := New("a", "b", "c")
+	var picker weightedrand.Picker[string] = p
+	if got := []string{picker.Pick(), picker.Pick()}; !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("picks = %v, want [a b]", got)
+	}
+	if p.Calls() != 2 || p.Remaining() != 1 {
+		t.Errorf("Calls(), Remaining() = %d, %d, want 2, 1", p.Calls(), p.Remaining())
+	}
+
+	var r recorder
+	p.AssertCalls(&r, 2)
+	p.AssertExhausted(&r)
+	if len(r.errors) != 1 {
+		t.Errorf("assertion errors = %q, want 1 for not exhausted", r.errors)
+	}
+
+	p.Pick()
+	p.AssertExhausted(t)
+	defer func() {
+		if recover() == nil {
+			t.Error("Pick() beyond script did not panic")
+		}
+	}()
+	p.Pick()
+}
+
+func TestLoop(t *testing.T) {
+	p := Loop(1, 2)
+	for i := 0; i < 5; i++ {
+		p.Pick()
+	}
+	if got := p.Picked(); !reflect.DeepEqual(got, []int{1, 2, 1, 2, 1}) {
+		t.Errorf("Picked() = %v", got)
+	}
+	p.AssertExhausted(t)
+	p.AssertCalls(t, 5)
+}
+
+// greeting is an example of code depending on a weightedrand.Picker.
+func greeting(p weightedrand.Picker[string]) string {
+	return p.Pick() + ", world"
+}
+
+func ExampleNew() {
+	p := New("hello", "goodbye")
+	fmt.Println(greeting(p))
+	fmt.Println(greeting(p))
+	fmt.Println(p.Remaining())
+	// Output:
+	// hello, world
+	// goodbye, world
+	// 0
+}
@@ -0,0 +1,84 @@
+package weightedrand
+
+import (
+	"context"
+	"reflect"
+)
+
+// FanIn multiplexes values from several channels onto a single channel, with a
+// weighted preference between them, unlike the uniform random choice a select
+// statement makes amongst ready channels. Whenever several channels have a
+// value ready, the next value is received from each with probability
+// proportional to its weight amongst the ready channels, so under load higher
+// weighted channels are drained proportionally more often.
+//
+// Channels with a weight < 1 are never received from. The returned channel is
+// closed once all the other channels are closed, or ctx is done.
+func FanIn[T any, W integer](ctx context.Context, chans ...Choice[<-chan T, W]) (<-chan T, error) {
+	var open []Choice[<-chan T, W]
+	for _, c := range chans {
+		if c.Weight >= 1 {
+			open = append(open, c)
+		}
+	}
+	total, err := sumWeights(open)
+	if err != nil {
+		return nil, err
+	}
+	if total < 1 {
+		return nil, errNoValidChoices
+	}
+
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		for len(open) > 0 {
+			i, v, ok := receiveWeighted(ctx, open)
+			if i < 0 {
+				return // ctx done
+			}
+			if !ok {
+				open = append(open[:i], open[i+1:]...)
+				continue
+			}
+			select {
+			case out <- v:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out, nil
+}
+
+// receiveWeighted receives from the first ready channel in a weighted random
+// order of chans, or if none are ready, from whichever becomes ready first. It
+// returns the index of the channel received from, or -1 if ctx is done first.
+func receiveWeighted[T any, W integer](ctx context.Context, chans []Choice[<-chan T, W]) (int, T, bool) {
+	indices := make([]Choice[int, W], len(chans))
+	for i, c := range chans {
+		indices[i] = NewChoice(i, c.Weight)
+	}
+	for _, i := range WeightedTopK(len(indices), indices...) {
+		select {
+		case v, ok := <-chans[i].Item:
+			return i, v, ok
+		default:
+		}
+	}
+
+	cases := make([]reflect.SelectCase, len(chans)+1)
+	for i, c := range chans {
+		cases[i] = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(c.Item)}
+	}
+	cases[len(chans)] = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())}
+	i, rv, ok := reflect.Select(cases)
+	var v T
+	if i == len(chans) {
+		return -1, v, false
+	}
+	if ok {
+		v, _ = rv.Interface().(T) // nil interface values fail the assertion
+	}
+	return i, v, ok
+}
@@ -0,0 +1,76 @@
+package weightedrand
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestFanIn(t *testing.T) {
+	const n = 4000
+	high, low, never := make(chan string, n), make(chan string, n), make(chan string, 1)
+	for i := 0; i < n; i++ {
+		high <- "high"
+		low <- "low"
+	}
+	never <- "never"
+	close(high)
+	close(low)
+
+	out, err := FanIn(context.Background(),
+		NewChoice((<-chan string)(high), 3),
+		NewChoice((<-chan string)(low), 1),
+		NewChoice((<-chan string)(never), 0),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// While both channels have values ready, high should be preferred 3:1.
+	counts := make(map[string]int)
+	for i := 0; i < n; i++ {
+		counts[<-out]++
+	}
+	if got := float64(counts["high"]) / n; got < 0.72 || got > 0.78 {
+		t.Errorf("fraction received from high = %.3f, want 0.75", got)
+	}
+	// The remainder must be drained, and out closed once inputs are closed.
+	for v := range out {
+		counts[v]++
+	}
+	if counts["high"] != n || counts["low"] != n || counts["never"] != 0 {
+		t.Errorf("received %v, want %d of high and low each", counts, n)
+	}
+}
+
+func TestFanIn_blocking(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	in := make(chan int)
+	out, err := FanIn(ctx, NewChoice((<-chan int)(in), 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() { in <- 42 }()
+	if v := <-out; v != 42 {
+		t.Errorf("received %d, want 42", v)
+	}
+
+	cancel()
+	select {
+	case _, ok := <-out:
+		if ok {
+			t.Error("received value after cancel")
+		}
+	case <-time.After(time.Second):
+		t.Error("out not closed after ctx cancelled")
+	}
+}
+
+func TestFanIn_errors(t *testing.T) {
+	c := make(<-chan int)
+	if _, err := FanIn(context.Background(), NewChoice(c, 0)); err != errNoValidChoices {
+		t.Errorf("FanIn() error = %v, want %v", err, errNoValidChoices)
+	}
+	if _, err := FanIn(context.Background(), NewChoice(c, maxInt), NewChoice(c, 1)); err != errWeightOverflow {
+		t.Errorf("FanIn() error = %v, want %v", err, errWeightOverflow)
+	}
+}
@@ -0,0 +1,165 @@
+// Package filepick picks a weighted random sample of the files under a
+// directory, weighted by size, age or a sidecar file of weights, such as to
+// sample large corpora for spot-checks or to select a subset of tests.
+//
+// Directories are read in batches as they are walked, and only the sample is
+// held in memory, so directories with millions of entries can be sampled
+// without loading all of their names.
+package filepick
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"math"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mroth/weightedrand/v2"
+)
+
+// readBatch is the number of directory entries read at a time.
+const readBatch = 256
+
+// ErrNegative is returned by Pick for a negative number of files.
+var ErrNegative = errors.New("filepick: negative number of files")
+
+// A WeightFunc returns the weight of a regular file found while walking, where
+// name is its slash-separated path within the file system. Files with a weight
+// that is not positive are never picked, and an error stops the walk.
+type WeightFunc func(name string, d fs.DirEntry) (float64, error)
+
+// Uniform weights every file equally.
+func Uniform(name string, d fs.DirEntry) (float64, error) {
+	return 1, nil
+}
+
+// Size weights files by their size in bytes, so empty files are never picked.
+func Size(name string, d fs.DirEntry) (float64, error) {
+	info, err := d.Info()
+	if err != nil {
+		return 0, err
+	}
+	return float64(info.Size()), nil
+}
+
+// Age returns a WeightFunc weighting files by the time since they were last
+// modified as of now, in seconds, favoring the oldest files.
+func Age(now time.Time) WeightFunc {
+	return func(name string, d fs.DirEntry) (float64, error) {
+		info, err := d.Info()
+		if err != nil {
+			return 0, err
+		}
+		return now.Sub(info.ModTime()).Seconds(), nil
+	}
+}
+
+// Recency returns a WeightFunc favoring recently modified files, whose weight
+// halves for every halfLife since they were last modified as of now.
+func Recency(now time.Time, halfLife time.Duration) WeightFunc {
+	return func(name string, d fs.DirEntry) (float64, error) {
+		info, err := d.Info()
+		if err != nil {
+			return 0, err
+		}
+		age := now.Sub(info.ModTime())
+		if age < 0 {
+			age = 0
+		}
+		return math.Exp2(-float64(age) / float64(halfLife)), nil
+	}
+}
+
+// Sidecar returns a WeightFunc weighting files as listed in a sidecar file,
+// read from r. The sidecar has one file per line, formatted as a non-negative
+// weight and a slash-separated path within the walked file system separated by
+// a tab, as in the input of the weightedrand command. Blank lines and lines
+// beginning with # are ignored. Files which are not listed have a weight of
+// zero.
+func Sidecar(r io.Reader) (WeightFunc, error) {
+	weights := make(map[string]float64)
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if strings.TrimSpace(text) == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		weight, name, ok := strings.Cut(text, "\t")
+		if !ok {
+			return nil, fmt.Errorf("filepick: sidecar line %d: missing tab separating weight and path", line)
+		}
+		w, err := strconv.ParseFloat(strings.TrimSpace(weight), 64)
+		if err != nil || !(w >= 0) || math.IsInf(w, 1) {
+			return nil, fmt.Errorf("filepick: sidecar line %d: invalid weight %q", line, weight)
+		}
+		weights[path.Clean(name)] = w
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("filepick: sidecar: %w", err)
+	}
+	return func(name string, d fs.DirEntry) (float64, error) {
+		return weights[name], nil
+	}, nil
+}
+
+// Pick walks the regular files under root in fsys, recursing into
+// subdirectories, and returns the paths of n of them picked by weight without
+// replacement, in the order they would have been picked one at a time. Fewer
+// than n paths are returned if there are not enough files with a positive
+// weight.
+//
+// Files are offered to a weightedrand.Reservoir of n paths as they are walked,
+// so each directory entry is read once.
+func Pick(fsys fs.FS, root string, n int, weight WeightFunc) ([]string, error) {
+	if n < 0 {
+		return nil, ErrNegative
+	}
+	sample := weightedrand.NewReservoir[string](n)
+	if err := walk(fsys, root, weight, sample); err != nil {
+		return nil, err
+	}
+	return sample.Items(), nil
+}
+
+// walk offers every regular file under dir to the sample, reading directories
+// in batches of readBatch entries.
+func walk(fsys fs.FS, dir string, weight WeightFunc, sample *weightedrand.Reservoir[string]) error {
+	f, err := fsys.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	rd, ok := f.(fs.ReadDirFile)
+	if !ok {
+		return &fs.PathError{Op: "readdir", Path: dir, Err: errors.New("not implemented")}
+	}
+	for {
+		entries, err := rd.ReadDir(readBatch)
+		for _, d := range entries {
+			name := path.Join(dir, d.Name())
+			switch {
+			case d.IsDir():
+				if err := walk(fsys, name, weight, sample); err != nil {
+					return err
+				}
+			case d.Type().IsRegular():
+				w, err := weight(name, d)
+				if err != nil {
+					return err
+				}
+				sample.Offer(name, w)
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
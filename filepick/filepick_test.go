@@ -0,0 +1,171 @@
+package filepick
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"runtime"
+	"sort"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+var now = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func testFS() fstest.MapFS {
+	return fstest.MapFS{
+		"small.txt":        {Data: []byte("a")},
+		"empty.txt":        {},
+		"dir/large.txt":    {Data: []byte("abc"), ModTime: now.Add(-time.Hour)},
+		"dir/sub/old.txt":  {Data: []byte("ab"), ModTime: now.Add(-3 * time.Hour)},
+		"dir/sub/link.txt": {Data: []byte("small.txt"), Mode: fs.ModeSymlink},
+	}
+}
+
+func TestPick(t *testing.T) {
+	fsys := testFS()
+	got, err := Pick(fsys, ".", 10, Uniform)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(got)
+	if want := "[dir/large.txt dir/sub/old.txt empty.txt small.txt]"; fmt.Sprint(got) != want {
+		t.Errorf("Pick(Uniform) = %v, want every regular file: %s", got, want)
+	}
+
+	got, err = Pick(fsys, "dir", 10, Size)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(got)
+	if want := "[dir/large.txt dir/sub/old.txt]"; fmt.Sprint(got) != want {
+		t.Errorf("Pick(dir) = %v, want %s", got, want)
+	}
+
+	if got, err := Pick(fsys, ".", 0, Uniform); err != nil || len(got) != 0 {
+		t.Errorf("Pick(0) = %v, %v", got, err)
+	}
+	if _, err := Pick(fsys, ".", -1, Uniform); !errors.Is(err, ErrNegative) {
+		t.Errorf("Pick(-1) error = %v, want %v", err, ErrNegative)
+	}
+	if _, err := Pick(fsys, "missing", 1, Uniform); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Pick(missing) error = %v, want %v", err, fs.ErrNotExist)
+	}
+	errWeight := errors.New("weight")
+	_, err = Pick(fsys, ".", 1, func(string, fs.DirEntry) (float64, error) { return 0, errWeight })
+	if !errors.Is(err, errWeight) {
+		t.Errorf("Pick() error = %v, want %v", err, errWeight)
+	}
+}
+
+// frequencies returns how often each file is picked first, as a fraction.
+func frequencies(t *testing.T, fsys fs.FS, weight WeightFunc) map[string]float64 {
+	t.Helper()
+	const n = 20000
+	freq := make(map[string]float64)
+	for i := 0; i < n; i++ {
+		got, err := Pick(fsys, ".", 1, weight)
+		if err != nil {
+			t.Fatal(err)
+		}
+		freq[got[0]] += 1.0 / n
+	}
+	return freq
+}
+
+func checkFrequencies(t *testing.T, got, want map[string]float64) {
+	t.Helper()
+	for name, p := range want {
+		if math.Abs(got[name]-p) > 0.015 {
+			t.Errorf("frequency of %s = %.3f, want %.3f", name, got[name], p)
+		}
+	}
+	for name := range got {
+		if _, ok := want[name]; !ok {
+			t.Errorf("picked unexpected file %s", name)
+		}
+	}
+}
+
+func TestPick_hugeN(t *testing.T) {
+	// Memory grows with the files found, not with n.
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	before := stats.TotalAlloc
+	if _, err := Pick(testFS(), ".", 1<<40, Uniform); err != nil {
+		t.Fatal(err)
+	}
+	runtime.ReadMemStats(&stats)
+	if grown := stats.TotalAlloc - before; grown > 1<<20 {
+		t.Errorf("Pick(n=1<<40) allocated %d bytes for 4 files", grown)
+	}
+}
+
+func TestSize(t *testing.T) {
+	checkFrequencies(t, frequencies(t, testFS(), Size), map[string]float64{
+		"small.txt": 1.0 / 6, "dir/large.txt": 3.0 / 6, "dir/sub/old.txt": 2.0 / 6,
+	})
+}
+
+func TestAge(t *testing.T) {
+	fsys := testFS()
+	delete(fsys, "small.txt")
+	delete(fsys, "empty.txt")
+	checkFrequencies(t, frequencies(t, fsys, Age(now)), map[string]float64{
+		"dir/large.txt": 0.25, "dir/sub/old.txt": 0.75,
+	})
+}
+
+func TestRecency(t *testing.T) {
+	fsys := testFS()
+	delete(fsys, "small.txt")
+	delete(fsys, "empty.txt")
+	// With a half life of 2h, large.txt is weighted 2^-0.5 and old.txt 2^-1.5.
+	checkFrequencies(t, frequencies(t, fsys, Recency(now, 2*time.Hour)), map[string]float64{
+		"dir/large.txt": 2.0 / 3, "dir/sub/old.txt": 1.0 / 3,
+	})
+}
+
+func TestSidecar(t *testing.T) {
+	weight, err := Sidecar(strings.NewReader("# weight\tpath\n3\tsmall.txt\n\n1\t./dir/large.txt\n0\tempty.txt\n2\tmissing.txt\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkFrequencies(t, frequencies(t, testFS(), weight), map[string]float64{
+		"small.txt": 0.75, "dir/large.txt": 0.25,
+	})
+
+	for _, bad := range []string{"1 a\n", "x\ta\n", "-1\ta\n", "NaN\ta\n", "Inf\ta\n"} {
+		if _, err := Sidecar(strings.NewReader(bad)); err == nil {
+			t.Errorf("Sidecar(%q) succeeded, expected error", bad)
+		}
+	}
+}
+
+func TestPick_largeDirectory(t *testing.T) {
+	// Spans several batches of directory entries.
+	fsys := make(fstest.MapFS)
+	for i := 0; i < 3*readBatch+1; i++ {
+		fsys[fmt.Sprintf("corpus/%04d", i)] = &fstest.MapFile{}
+	}
+	got, err := Pick(fsys, "corpus", 5000, Uniform)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(fsys) {
+		t.Errorf("Pick() returned %d files, want %d", len(got), len(fsys))
+	}
+}
+
+func ExamplePick() {
+	fsys := fstest.MapFS{
+		"corpus/a.txt": {Data: []byte("hello")},
+		"corpus/b.txt": {},
+	}
+	names, _ := Pick(fsys, "corpus", 2, Size)
+	fmt.Println(names)
+	// Output: [corpus/a.txt]
+}
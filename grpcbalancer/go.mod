@@ -0,0 +1,21 @@
+module github.com/mroth/weightedrand/v2/grpcbalancer
+
+go 1.25.0
+
+require (
+	github.com/mroth/weightedrand/v2 v2.2.0
+	google.golang.org/grpc v1.84.0
+)
+
+require (
+	golang.org/x/net v0.57.0 // indirect
+	golang.org/x/sys v0.47.0 // indirect
+	golang.org/x/text v0.40.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20260706201446-f0a921348800 // indirect
+	google.golang.org/protobuf v1.36.11 // indirect
+)
+
+// Builds within this repository use the parent module as checked out. The
+// replace directive is ignored for users of this module, who get the version
+// required above, the first to provide all the APIs used here.
+replace github.com/mroth/weightedrand/v2 => ../
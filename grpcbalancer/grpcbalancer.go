@@ -0,0 +1,154 @@
+// Package grpcbalancer provides a gRPC load balancing policy which distributes
+// RPCs across ready SubConns at random in proportion to their weights, as
+// configured in the service config or attached to addresses by the resolver,
+// such as from the weights of Kubernetes EndpointSlices.
+//
+// Importing the package registers the policy under Name, so it can be enabled
+// by service config:
+//
+//	{"loadBalancingConfig": [{"weightedrand": {"weights": {"10.0.0.1:443": 3}}}]}
+//
+// It is a separate module so that weightedrand does not depend on gRPC.
+package grpcbalancer
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/mroth/weightedrand/v2"
+	"google.golang.org/grpc/balancer"
+	"google.golang.org/grpc/balancer/base"
+	"google.golang.org/grpc/resolver"
+	"google.golang.org/grpc/serviceconfig"
+)
+
+// Name is the name under which the policy is registered.
+const Name = "weightedrand"
+
+func init() {
+	balancer.Register(builder{})
+}
+
+// Config is the load balancing config of the policy.
+type Config struct {
+	serviceconfig.LoadBalancingConfig `json:"-"`
+
+	// Weights maps addresses, as in resolver.Address.Addr, to their weights.
+	// It is overridden by weights set on addresses with SetWeight.
+	Weights map[string]uint32 `json:"weights,omitempty"`
+	// DefaultWeight is the weight of addresses with no configured weight. If
+	// nil, it is 1.
+	DefaultWeight *uint32 `json:"defaultWeight,omitempty"`
+}
+
+type weightKey struct{}
+
+// SetWeight returns a copy of addr with its weight set, taking precedence over
+// any weight from the Config, for use by resolvers. A weight of zero drains
+// the address of new RPCs.
+func SetWeight(addr resolver.Address, weight uint32) resolver.Address {
+	addr.BalancerAttributes = addr.BalancerAttributes.WithValue(weightKey{}, weight)
+	return addr
+}
+
+// Weight returns the weight set on addr by SetWeight, if any.
+func Weight(addr resolver.Address) (uint32, bool) {
+	w, ok := addr.BalancerAttributes.Value(weightKey{}).(uint32)
+	return w, ok
+}
+
+type builder struct{}
+
+func (builder) Name() string { return Name }
+
+func (builder) Build(cc balancer.ClientConn, opts balancer.BuildOptions) balancer.Balancer {
+	pb := &pickerBuilder{}
+	return &weightedBalancer{
+		Balancer: base.NewBalancerBuilder(Name, pb, base.Config{HealthCheck: true}).Build(cc, opts),
+		pb:       pb,
+	}
+}
+
+func (builder) ParseConfig(js json.RawMessage) (serviceconfig.LoadBalancingConfig, error) {
+	cfg := &Config{}
+	if err := json.Unmarshal(js, cfg); err != nil {
+		return nil, fmt.Errorf("grpcbalancer: invalid config: %w", err)
+	}
+	return cfg, nil
+}
+
+// weightedBalancer is the base balancer, which manages SubConns and their
+// connectivity, but with weights taken from each resolver update before
+// its picker is rebuilt, since the base balancer only reports the address
+// of each SubConn as it was when the SubConn was created.
+type weightedBalancer struct {
+	balancer.Balancer
+	pb *pickerBuilder
+}
+
+func (b *weightedBalancer) UpdateClientConnState(s balancer.ClientConnState) error {
+	cfg, _ := s.BalancerConfig.(*Config)
+	b.pb.update(cfg, s.ResolverState.Addresses)
+	return b.Balancer.UpdateClientConnState(s)
+}
+
+// pickerBuilder builds pickers from the weights of the latest resolver update.
+type pickerBuilder struct {
+	mu      sync.Mutex
+	weights map[string]uint32
+}
+
+func (pb *pickerBuilder) update(cfg *Config, addrs []resolver.Address) {
+	def := uint32(1)
+	if cfg != nil && cfg.DefaultWeight != nil {
+		def = *cfg.DefaultWeight
+	}
+	weights := make(map[string]uint32, len(addrs))
+	for _, addr := range addrs {
+		w, ok := Weight(addr)
+		if !ok && cfg != nil {
+			w, ok = cfg.Weights[addr.Addr]
+		}
+		if !ok {
+			w = def
+		}
+		weights[addr.Addr] = w
+	}
+	pb.mu.Lock()
+	pb.weights = weights
+	pb.mu.Unlock()
+}
+
+// Build returns a picker choosing among the ready SubConns by weight. If none
+// of them have a weight >= 1, such as when every address is being drained, it
+// chooses among them uniformly rather than failing RPCs.
+func (pb *pickerBuilder) Build(info base.PickerBuildInfo) balancer.Picker {
+	if len(info.ReadySCs) == 0 {
+		return base.NewErrPicker(balancer.ErrNoSubConnAvailable)
+	}
+	pb.mu.Lock()
+	defer pb.mu.Unlock()
+	choices := make([]weightedrand.Choice[balancer.SubConn, uint32], 0, len(info.ReadySCs))
+	for sc, sci := range info.ReadySCs {
+		choices = append(choices, weightedrand.NewChoice(sc, pb.weights[sci.Address.Addr]))
+	}
+	chooser, err := weightedrand.NewChooser(choices...)
+	if err != nil {
+		for i := range choices {
+			choices[i].Weight = 1
+		}
+		if chooser, err = weightedrand.NewChooser(choices...); err != nil {
+			return base.NewErrPicker(err)
+		}
+	}
+	return picker{chooser}
+}
+
+type picker struct {
+	chooser *weightedrand.Chooser[balancer.SubConn, uint32]
+}
+
+func (p picker) Pick(balancer.PickInfo) (balancer.PickResult, error) {
+	return balancer.PickResult{SubConn: p.chooser.Pick()}, nil
+}
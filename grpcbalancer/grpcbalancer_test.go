@@ -0,0 +1,204 @@
+package grpcbalancer
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"sync"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/balancer"
+	"google.golang.org/grpc/balancer/base"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/resolver"
+	"google.golang.org/grpc/resolver/manual"
+)
+
+// backends starts n gRPC servers, returning their addresses and a function
+// reporting how many RPCs each has served since it was last called.
+func backends(t *testing.T, n int) ([]string, func() []int) {
+	t.Helper()
+	var mu sync.Mutex
+	counts := make([]int, n)
+	addrs := make([]string, n)
+	for i := range addrs {
+		lis, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		i := i
+		srv := grpc.NewServer(grpc.UnaryInterceptor(func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+			mu.Lock()
+			counts[i]++
+			mu.Unlock()
+			return handler(ctx, req)
+		}))
+		healthpb.RegisterHealthServer(srv, health.NewServer())
+		go srv.Serve(lis)
+		t.Cleanup(srv.Stop)
+		addrs[i] = lis.Addr().String()
+	}
+	return addrs, func() []int {
+		mu.Lock()
+		defer mu.Unlock()
+		got := append([]int(nil), counts...)
+		for i := range counts {
+			counts[i] = 0
+		}
+		return got
+	}
+}
+
+// call makes n RPCs over cc.
+func call(t *testing.T, cc *grpc.ClientConn, n int) {
+	t.Helper()
+	client := healthpb.NewHealthClient(cc)
+	for i := 0; i < n; i++ {
+		if _, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{}, grpc.WaitForReady(true)); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// warmUp makes RPCs until every backend has served one, so that all SubConns
+// are ready, then resets the counts.
+func warmUp(t *testing.T, cc *grpc.ClientConn, counts func() []int) {
+	t.Helper()
+	var served []int
+	for i := 0; i < 1000; i++ {
+		call(t, cc, 1)
+		c := counts()
+		if served == nil {
+			served = c
+		}
+		ready := true
+		for j := range c {
+			served[j] += c[j]
+			ready = ready && served[j] > 0
+		}
+		if ready {
+			return
+		}
+	}
+	t.Fatalf("backends never all served an RPC: %v", served)
+}
+
+func checkShares(t *testing.T, counts []int, want []float64) {
+	t.Helper()
+	var total int
+	for _, c := range counts {
+		total += c
+	}
+	for i, w := range want {
+		if got := float64(counts[i]) / float64(total); math.Abs(got-w) > 0.05 {
+			t.Errorf("backend %d served %.3f of RPCs, want %.3f (counts %v)", i, got, w, counts)
+		}
+	}
+}
+
+func TestBalancer(t *testing.T) {
+	addrs, counts := backends(t, 3)
+	r := manual.NewBuilderWithScheme("test")
+	config := fmt.Sprintf(`{"loadBalancingConfig": [{%q: {"weights": {%q: 3}}}]}`, Name, addrs[2])
+	cc, err := grpc.NewClient(r.Scheme()+":///test",
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithResolvers(r),
+		grpc.WithDefaultServiceConfig(config),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cc.Close()
+	cc.Connect()
+
+	// Weights set by the resolver take precedence over the config.
+	r.UpdateState(resolver.State{Addresses: []resolver.Address{
+		SetWeight(resolver.Address{Addr: addrs[0]}, 6),
+		{Addr: addrs[1]},
+		{Addr: addrs[2]},
+	}})
+	warmUp(t, cc, counts)
+	call(t, cc, 3000)
+	checkShares(t, counts(), []float64{0.6, 0.1, 0.3})
+
+	// Live update: drain the first backend.
+	r.UpdateState(resolver.State{Addresses: []resolver.Address{
+		SetWeight(resolver.Address{Addr: addrs[0]}, 0),
+		{Addr: addrs[1]},
+		{Addr: addrs[2]},
+	}})
+	call(t, cc, 2000)
+	checkShares(t, counts(), []float64{0, 0.25, 0.75})
+}
+
+func TestParseConfig(t *testing.T) {
+	cfg, err := builder{}.ParseConfig([]byte(`{"weights": {"a:1": 5}, "defaultWeight": 0}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := cfg.(*Config)
+	if c.Weights["a:1"] != 5 || c.DefaultWeight == nil || *c.DefaultWeight != 0 {
+		t.Errorf("ParseConfig() = %+v", c)
+	}
+	if _, err := (builder{}).ParseConfig([]byte(`{"weights": [1]}`)); err == nil {
+		t.Error("ParseConfig() of invalid config succeeded")
+	}
+}
+
+func TestSetWeight(t *testing.T) {
+	addr := resolver.Address{Addr: "a:1"}
+	if _, ok := Weight(addr); ok {
+		t.Error("Weight() of address without weight reported ok")
+	}
+	if w, ok := Weight(SetWeight(addr, 7)); !ok || w != 7 {
+		t.Errorf("Weight() = %d, %v, want 7, true", w, ok)
+	}
+}
+
+type fakeSubConn struct {
+	balancer.SubConn
+	name string
+}
+
+func TestPickerBuilder(t *testing.T) {
+	zero := uint32(0)
+	pb := &pickerBuilder{}
+	addrs := []resolver.Address{{Addr: "a:1"}, {Addr: "b:1"}}
+	ready := map[balancer.SubConn]base.SubConnInfo{
+		&fakeSubConn{name: "a"}: {Address: addrs[0]},
+		&fakeSubConn{name: "b"}: {Address: addrs[1]},
+	}
+
+	// With every address drained, picks fall back to uniform.
+	pb.update(&Config{DefaultWeight: &zero}, addrs)
+	p := pb.Build(base.PickerBuildInfo{ReadySCs: ready})
+	seen := make(map[string]bool)
+	for i := 0; i < 100; i++ {
+		res, err := p.Pick(balancer.PickInfo{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		seen[res.SubConn.(*fakeSubConn).name] = true
+	}
+	if len(seen) != 2 {
+		t.Errorf("picked %v, want both SubConns", seen)
+	}
+
+	pb.update(&Config{DefaultWeight: &zero, Weights: map[string]uint32{"b:1": 1}}, addrs)
+	p = pb.Build(base.PickerBuildInfo{ReadySCs: ready})
+	for i := 0; i < 100; i++ {
+		res, _ := p.Pick(balancer.PickInfo{})
+		if name := res.SubConn.(*fakeSubConn).name; name != "b" {
+			t.Fatalf("picked drained SubConn %s", name)
+		}
+	}
+
+	p = pb.Build(base.PickerBuildInfo{})
+	if _, err := p.Pick(balancer.PickInfo{}); err != balancer.ErrNoSubConnAvailable {
+		t.Errorf("Pick() with no ready SubConns error = %v, want %v", err, balancer.ErrNoSubConnAvailable)
+	}
+}
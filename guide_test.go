@@ -0,0 +1,71 @@
+package weightedrand
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestGuide_search(t *testing.T) {
+	for _, n := range []int{128, 1000, 4099} {
+		for _, maxWeight := range []int{1, 3, 1000, maxInt / 8192} {
+			// Include repeated totals, as left by choices with a weight of zero.
+			a := make([]int, n)
+			total := 0
+			for i := range a {
+				if rand.Intn(4) > 0 {
+					total += rand.Intn(maxWeight) + 1
+				}
+				a[i] = total
+			}
+			var g guide
+			if !g.build(a, total) {
+				t.Fatalf("n=%d: build() = false, want true", n)
+			}
+			xs := []int{1, total}
+			for i := 0; i < 10000; i++ {
+				xs = append(xs, rand.Intn(total)+1)
+			}
+			for _, x := range xs {
+				if got, want := g.search(a, x), sort.SearchInts(a, x); got != want {
+					t.Fatalf("n=%d max=%d: search(%d) = %d, want %d", n, total, x, got, want)
+				}
+			}
+		}
+	}
+
+	var g guide
+	if g.build(make([]int, 1000), 1) {
+		t.Error("build() with max 1 = true, want false")
+	}
+	if g.build([]int{1, 2, 3}, 3) {
+		t.Error("build() with 3 totals = true, want false")
+	}
+}
+
+func TestChooser_guide(t *testing.T) {
+	choices := make([]Choice[int, int], guideMinLen)
+	for i := range choices {
+		choices[i] = NewChoice(i, i%3)
+	}
+	chooser, err := NewChooser(choices...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if chooser.guide == nil {
+		t.Fatal("Chooser of guideMinLen choices has no guide")
+	}
+	for i := 0; i < 1000; i++ {
+		x := rand.Intn(chooser.max) + 1
+		if got, want := chooser.search(x), sort.SearchInts(chooser.totals, x); got != want {
+			t.Fatalf("search(%d) = %d, want %d", x, got, want)
+		}
+	}
+
+	if err := chooser.Reset(NewChoice(0, 1)); err != nil {
+		t.Fatal(err)
+	}
+	if chooser.guide != nil {
+		t.Error("Chooser has a guide after Reset to a single choice")
+	}
+}
@@ -0,0 +1,229 @@
+// Package httpbalance routes HTTP requests among several backends at random in
+// proportion to their weights, such as to shift a small share of traffic to a
+// new deployment, either as an http.RoundTripper for clients or as an
+// httputil.ReverseProxy.
+package httpbalance
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/mroth/weightedrand/v2"
+	"github.com/mroth/weightedrand/v2/balance"
+	"github.com/mroth/weightedrand/v2/internal/constraints"
+)
+
+// ErrNilURL is returned when a Backend has no URL.
+var ErrNilURL = errors.New("httpbalance: backend with nil URL")
+
+// A Backend is an upstream to which requests may be routed.
+type Backend struct {
+	// URL is the base URL of the backend. Routed requests have their scheme
+	// and host replaced by those of URL, and their path appended to its path.
+	URL *url.URL
+	// Transport sends requests to the backend. If nil, http.DefaultTransport
+	// is used.
+	Transport http.RoundTripper
+}
+
+// An Option configures a Transport.
+type Option func(*config)
+
+type config struct {
+	eject  time.Duration
+	sticky func(*http.Request) string
+}
+
+// WithEjection excludes a backend from selection for d after it responds with
+// a 5xx status or its Transport returns an error, with its share of requests
+// redistributed among the others. If every backend is excluded, requests are
+// routed among all of them by weight, rather than failing outright.
+func WithEjection(d time.Duration) Option {
+	return func(cfg *config) { cfg.eject = d }
+}
+
+// WithStickiness routes all requests with the same non-empty key, as returned
+// by key, to the same backend, such as to keep each client on one version of a
+// service during a canary rollout. Keys are hashed into buckets sized by the
+// weights of the backends, so over many keys each backend still receives its
+// weighted share. While a key's backend is ejected, its requests are routed as
+// if they had no key.
+func WithStickiness(key func(*http.Request) string) Option {
+	return func(cfg *config) { cfg.sticky = key }
+}
+
+// router picks among backends by weight, excluding those which are ejected.
+type router struct {
+	backends []Backend
+	eject    time.Duration
+	sticky   func(*http.Request) string
+	until    []int64 // per backend ejection deadline in Unix nanoseconds, accessed atomically
+
+	pick      func() (int, bool) // excluding ejected backends
+	pickAny   func() int         // including ejected backends
+	pickByKey func(string) int   // including ejected backends
+	markDown  func(int)
+	markUp    func(int)
+}
+
+func newRouter[W constraints.Integer](backends []weightedrand.Choice[Backend, W], opts []Option) (*router, error) {
+	var cfg config
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	r := &router{
+		backends: make([]Backend, len(backends)),
+		eject:    cfg.eject,
+		sticky:   cfg.sticky,
+		until:    make([]int64, len(backends)),
+	}
+	indices := make([]weightedrand.Choice[int, W], len(backends))
+	for i, b := range backends {
+		if b.Item.URL == nil {
+			return nil, ErrNilURL
+		}
+		r.backends[i] = b.Item
+		indices[i] = weightedrand.NewChoice(i, b.Weight)
+	}
+	bal, err := balance.New(indices...)
+	if err != nil {
+		return nil, err
+	}
+	all, err := weightedrand.NewChooser(indices...)
+	if err != nil {
+		return nil, err
+	}
+	r.pick, r.pickAny, r.pickByKey = bal.Pick, all.Pick, all.PickByKey
+	r.markDown, r.markUp = bal.MarkDown, bal.MarkUp
+	return r, nil
+}
+
+// next returns the index of the backend for req.
+func (r *router) next(req *http.Request) int {
+	if r.sticky != nil {
+		if key := r.sticky(req); key != "" {
+			i := r.pickByKey(key)
+			if atomic.LoadInt64(&r.until[i]) <= time.Now().UnixNano() {
+				return i
+			}
+		}
+	}
+	if i, ok := r.pick(); ok {
+		return i
+	}
+	return r.pickAny()
+}
+
+// observe ejects backend i if ejection is enabled and the response failed.
+func (r *router) observe(i int, resp *http.Response, err error) {
+	if r.eject <= 0 || (err == nil && resp.StatusCode < 500) {
+		return
+	}
+	until := time.Now().Add(r.eject).UnixNano()
+	atomic.StoreInt64(&r.until[i], until)
+	r.markDown(i)
+	time.AfterFunc(r.eject, func() {
+		// A later failure may have extended the ejection.
+		if atomic.LoadInt64(&r.until[i]) <= until {
+			r.markUp(i)
+		}
+	})
+}
+
+// rewrite points u at backend b, in place.
+func rewrite(u *url.URL, b *url.URL) {
+	u.Scheme = b.Scheme
+	u.Host = b.Host
+	u.Path, u.RawPath = joinURLPath(b, u)
+	if b.RawQuery != "" && u.RawQuery != "" {
+		u.RawQuery = b.RawQuery + "&" + u.RawQuery
+	} else if b.RawQuery != "" {
+		u.RawQuery = b.RawQuery
+	}
+}
+
+// joinURLPath joins the paths of a and b as httputil.ReverseProxy does.
+func joinURLPath(a, b *url.URL) (path, rawpath string) {
+	if a.RawPath == "" && b.RawPath == "" {
+		return singleJoiningSlash(a.Path, b.Path), ""
+	}
+	apath, bpath := a.EscapedPath(), b.EscapedPath()
+	aslash, bslash := strings.HasSuffix(apath, "/"), strings.HasPrefix(bpath, "/")
+	switch {
+	case aslash && bslash:
+		return a.Path + b.Path[1:], apath + bpath[1:]
+	case !aslash && !bslash:
+		return a.Path + "/" + b.Path, apath + "/" + bpath
+	}
+	return a.Path + b.Path, apath + bpath
+}
+
+func singleJoiningSlash(a, b string) string {
+	aslash, bslash := strings.HasSuffix(a, "/"), strings.HasPrefix(b, "/")
+	switch {
+	case aslash && bslash:
+		return a + b[1:]
+	case !aslash && !bslash:
+		return a + "/" + b
+	}
+	return a + b
+}
+
+// A Transport is an http.RoundTripper which sends each request to a weighted
+// random Backend. It is safe for concurrent usage.
+type Transport struct {
+	r *router
+}
+
+// NewTransport initializes a Transport routing among backends in proportion
+// to their weights.
+func NewTransport[W constraints.Integer](backends []weightedrand.Choice[Backend, W], opts ...Option) (*Transport, error) {
+	r, err := newRouter(backends, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &Transport{r: r}, nil
+}
+
+// RoundTrip sends a copy of req to a weighted random Backend, with its URL
+// and Host rewritten to those of the backend.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	i := t.r.next(req)
+	b := t.r.backends[i]
+	out := req.Clone(req.Context())
+	rewrite(out.URL, b.URL)
+	out.Host = ""
+	rt := b.Transport
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	resp, err := rt.RoundTrip(out)
+	t.r.observe(i, resp, err)
+	return resp, err
+}
+
+// NewReverseProxy returns a reverse proxy which forwards each request to a
+// weighted random backend, as chosen by a Transport. As well as the headers
+// added by httputil.ReverseProxy, the Host header of each request is rewritten
+// to that of its backend. The proxy's fields, such as its ErrorHandler, may be
+// customized before use, but its Director and Transport do the routing.
+func NewReverseProxy[W constraints.Integer](backends []weightedrand.Choice[Backend, W], opts ...Option) (*httputil.ReverseProxy, error) {
+	t, err := NewTransport(backends, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &httputil.ReverseProxy{
+		Director: func(req *http.Request) {
+			if _, ok := req.Header["User-Agent"]; !ok {
+				// Prevent the default User-Agent of net/http being set.
+				req.Header.Set("User-Agent", "")
+			}
+		},
+		Transport: t,
+	}, nil
+}
@@ -0,0 +1,237 @@
+package httpbalance
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mroth/weightedrand/v2"
+)
+
+// backend starts a server responding with status and the request's path, and
+// counting requests.
+func backend(t *testing.T, name string, status *int32, count *int64) *url.URL {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(count, 1)
+		w.WriteHeader(int(atomic.LoadInt32(status)))
+		fmt.Fprint(w, name+" "+r.Host+" "+r.URL.RequestURI())
+	}))
+	t.Cleanup(srv.Close)
+	u, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return u
+}
+
+func get(t *testing.T, client *http.Client, url string) (int, string) {
+	t.Helper()
+	resp, err := client.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode, string(body)
+}
+
+func TestTransport(t *testing.T) {
+	ok := int32(http.StatusOK)
+	var stable, canary int64
+	stableURL := backend(t, "stable", &ok, &stable)
+	canaryURL := backend(t, "canary", &ok, &canary)
+	canaryURL.Path = "/v2"
+
+	tr, err := NewTransport([]weightedrand.Choice[Backend, int]{
+		{Item: Backend{URL: stableURL}, Weight: 95},
+		{Item: Backend{URL: canaryURL}, Weight: 5},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &http.Client{Transport: tr}
+
+	const n = 2000
+	for i := 0; i < n; i++ {
+		_, body := get(t, client, "http://service.invalid/items?id=1")
+		switch body {
+		case "stable " + stableURL.Host + " /items?id=1":
+		case "canary " + canaryURL.Host + " /v2/items?id=1":
+		default:
+			t.Fatalf("unexpected response %q", body)
+		}
+	}
+	if got := float64(atomic.LoadInt64(&canary)) / n; math.Abs(got-0.05) > 0.02 {
+		t.Errorf("canary received %.3f of requests, want 0.05", got)
+	}
+}
+
+func TestTransport_ejection(t *testing.T) {
+	ok, failing := int32(http.StatusOK), int32(http.StatusInternalServerError)
+	var good, bad int64
+	goodURL := backend(t, "good", &ok, &good)
+	badURL := backend(t, "bad", &failing, &bad)
+
+	const eject = 100 * time.Millisecond
+	tr, err := NewTransport([]weightedrand.Choice[Backend, int]{
+		{Item: Backend{URL: goodURL}, Weight: 1},
+		{Item: Backend{URL: badURL}, Weight: 1},
+	}, WithEjection(eject))
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &http.Client{Transport: tr}
+
+	for i := 0; i < 100; i++ {
+		get(t, client, "http://service.invalid/")
+	}
+	if bad := atomic.LoadInt64(&bad); bad != 1 {
+		t.Errorf("failing backend received %d requests while ejected, want 1", bad)
+	}
+
+	// After the ejection expires the backend is tried again.
+	time.Sleep(2 * eject)
+	atomic.StoreInt32(&failing, http.StatusOK)
+	atomic.StoreInt64(&bad, 0)
+	for i := 0; i < 100; i++ {
+		get(t, client, "http://service.invalid/")
+	}
+	if bad := atomic.LoadInt64(&bad); bad < 20 {
+		t.Errorf("recovered backend received %d of 100 requests", bad)
+	}
+}
+
+// With every backend ejected, requests are still routed rather than failed.
+func TestTransport_allEjected(t *testing.T) {
+	failing := int32(http.StatusBadGateway)
+	var count int64
+	u := backend(t, "only", &failing, &count)
+	tr, err := NewTransport([]weightedrand.Choice[Backend, int]{{Item: Backend{URL: u}, Weight: 1}}, WithEjection(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &http.Client{Transport: tr}
+	for i := 0; i < 3; i++ {
+		if code, _ := get(t, client, "http://service.invalid/"); code != http.StatusBadGateway {
+			t.Errorf("status = %d, want %d", code, http.StatusBadGateway)
+		}
+	}
+	if count := atomic.LoadInt64(&count); count != 3 {
+		t.Errorf("backend received %d requests, want 3", count)
+	}
+}
+
+func TestNewTransport(t *testing.T) {
+	_, err := NewTransport([]weightedrand.Choice[Backend, int]{{Item: Backend{}, Weight: 1}})
+	if err != ErrNilURL {
+		t.Errorf("NewTransport() error = %v, want %v", err, ErrNilURL)
+	}
+	u, _ := url.Parse("http://a.invalid")
+	if _, err := NewTransport([]weightedrand.Choice[Backend, int]{{Item: Backend{URL: u}, Weight: 0}}); err == nil {
+		t.Error("NewTransport() with no positive weights succeeded")
+	}
+}
+
+func TestRewrite(t *testing.T) {
+	tests := []struct {
+		backend, request, want string
+	}{
+		{"http://b:1", "http://x/a?q=1", "http://b:1/a?q=1"},
+		{"https://b/base/", "http://x/a", "https://b/base/a"},
+		{"https://b/base", "http://x/a", "https://b/base/a"},
+		{"http://b/base?k=v", "http://x/a?q=1", "http://b/base/a?k=v&q=1"},
+		{"http://b/p%2Fq", "http://x/a%2Fb", "http://b/p%2Fq/a%2Fb"},
+	}
+	for _, tt := range tests {
+		b, _ := url.Parse(tt.backend)
+		u, _ := url.Parse(tt.request)
+		rewrite(u, b)
+		if got := u.String(); got != tt.want {
+			t.Errorf("rewrite(%s, %s) = %s, want %s", tt.request, tt.backend, got, tt.want)
+		}
+	}
+}
+
+func ExampleNewTransport() {
+	stable, _ := url.Parse("https://api.example.com")
+	shadow, _ := url.Parse("https://api-next.example.com")
+	tr, _ := NewTransport([]weightedrand.Choice[Backend, int]{
+		{Item: Backend{URL: stable}, Weight: 95},
+		{Item: Backend{URL: shadow}, Weight: 5},
+	}, WithEjection(30*time.Second))
+	client := &http.Client{Transport: tr}
+	_ = client // client.Get("/v1/items") goes to the shadow 5% of the time
+}
+
+func TestNewReverseProxy(t *testing.T) {
+	ok := int32(http.StatusOK)
+	var a, b int64
+	aURL := backend(t, "a", &ok, &a)
+	bURL := backend(t, "b", &ok, &b)
+	proxy, err := NewReverseProxy([]weightedrand.Choice[Backend, int]{
+		{Item: Backend{URL: aURL}, Weight: 1},
+		{Item: Backend{URL: bURL}, Weight: 1},
+	}, WithStickiness(func(r *http.Request) string { return r.Header.Get("X-User") }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	front := httptest.NewServer(proxy)
+	defer front.Close()
+
+	// Each user is always routed to the same backend.
+	assigned := make(map[string]string)
+	for i := 0; i < 200; i++ {
+		user := fmt.Sprint("user", i%20)
+		req, _ := http.NewRequest(http.MethodGet, front.URL+"/x", nil)
+		req.Header.Set("X-User", user)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		name := string(body[:1])
+		if prev, ok := assigned[user]; ok && prev != name {
+			t.Fatalf("%s routed to %s, then %s", user, prev, name)
+		}
+		assigned[user] = name
+		if want := map[string]string{"a": aURL.Host, "b": bURL.Host}[name]; string(body) != name+" "+want+" /x" {
+			t.Errorf("response %q, want Host and path rewritten", body)
+		}
+	}
+	if atomic.LoadInt64(&a) == 0 || atomic.LoadInt64(&b) == 0 {
+		t.Errorf("users were not spread across backends: a=%d b=%d", a, b)
+	}
+}
+
+// While a sticky key's backend is ejected, its requests go elsewhere.
+func TestWithStickiness_ejected(t *testing.T) {
+	ok, failing := int32(http.StatusOK), int32(http.StatusServiceUnavailable)
+	var good, bad int64
+	goodURL := backend(t, "good", &ok, &good)
+	badURL := backend(t, "bad", &failing, &bad)
+	tr, err := NewTransport([]weightedrand.Choice[Backend, int]{
+		{Item: Backend{URL: goodURL}, Weight: 1},
+		{Item: Backend{URL: badURL}, Weight: 1},
+	}, WithEjection(time.Hour), WithStickiness(func(r *http.Request) string { return r.URL.Query().Get("user") }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &http.Client{Transport: tr}
+	for i := 0; i < 50; i++ {
+		get(t, client, fmt.Sprintf("http://service.invalid/?user=%d", i))
+	}
+	if bad := atomic.LoadInt64(&bad); bad != 1 {
+		t.Errorf("ejected backend received %d requests, want 1", bad)
+	}
+}
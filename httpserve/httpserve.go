@@ -0,0 +1,125 @@
+// Package httpserve provides an http.Handler serving weighted random picks from
+// a set of named Choosers as JSON, so that services not written in Go can
+// share the same weighted tables.
+//
+// Picks are requested with GET and the query parameters chooser, naming the
+// Chooser to pick from, and optionally n, the number of picks (default 1):
+//
+//	GET /pick?chooser=experiment1&n=5
+//
+// which responds with:
+//
+//	{"chooser":"experiment1","picks":["b","a","b","b","c"]}
+package httpserve
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/mroth/weightedrand/v2"
+	"github.com/mroth/weightedrand/v2/internal/constraints"
+)
+
+// DefaultMaxPicks is the default limit on picks per request.
+const DefaultMaxPicks = 1000
+
+// Handler serves picks from its registered Choosers. It is safe for concurrent
+// usage, and Choosers may be registered or removed while serving.
+type Handler struct {
+	// MaxPicks limits the number of picks per request. If zero,
+	// DefaultMaxPicks is used.
+	MaxPicks int
+
+	mu       sync.RWMutex
+	choosers map[string]func() interface{}
+}
+
+// NewHandler returns a Handler with no registered Choosers.
+func NewHandler() *Handler {
+	return &Handler{choosers: make(map[string]func() interface{})}
+}
+
+// Register adds c to h under name, replacing any existing Chooser of that
+// name. Items are encoded in responses with encoding/json.
+func Register[T any, W constraints.Integer](h *Handler, name string, c *weightedrand.Chooser[T, W]) {
+	RegisterPicker[T](h, name, c)
+}
+
+// RegisterPicker is like Register, but accepts any weightedrand.Picker, such as
+// a reloading or adaptive chooser from another package.
+func RegisterPicker[T any](h *Handler, name string, p weightedrand.Picker[T]) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.choosers[name] = func() interface{} { return p.Pick() }
+}
+
+// Remove removes the Chooser registered under name, if any.
+func (h *Handler) Remove(name string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.choosers, name)
+}
+
+type response struct {
+	Chooser string        `json:"chooser"`
+	Picks   []interface{} `json:"picks"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// ServeHTTP implements http.Handler.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	query := r.URL.Query()
+	name := query.Get("chooser")
+	h.mu.RLock()
+	pick, ok := h.choosers[name]
+	h.mu.RUnlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, "unknown chooser: "+strconv.Quote(name))
+		return
+	}
+
+	n := 1
+	if s := query.Get("n"); s != "" {
+		var err error
+		if n, err = strconv.Atoi(s); err != nil || n < 1 {
+			writeError(w, http.StatusBadRequest, "n must be a positive integer")
+			return
+		}
+	}
+	maxPicks := h.MaxPicks
+	if maxPicks == 0 {
+		maxPicks = DefaultMaxPicks
+	}
+	if n > maxPicks {
+		writeError(w, http.StatusBadRequest, "n exceeds limit of "+strconv.Itoa(maxPicks))
+		return
+	}
+
+	resp := response{Chooser: name, Picks: make([]interface{}, n)}
+	for i := range resp.Picks {
+		resp.Picks[i] = pick()
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func writeError(w http.ResponseWriter, code int, msg string) {
+	writeJSON(w, code, errorResponse{Error: msg})
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store") // every response is random
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,83 @@
+package httpserve
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mroth/weightedrand/v2"
+)
+
+func TestHandler(t *testing.T) {
+	h := NewHandler()
+	h.MaxPicks = 10
+	c, err := weightedrand.NewChooser(weightedrand.NewChoice("a", 1), weightedrand.NewChoice("b", 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	Register(h, "experiment1", c)
+	Register(h, "removed", c)
+	h.Remove("removed")
+
+	tests := []struct {
+		name      string
+		method    string
+		target    string
+		wantCode  int
+		wantPicks int
+	}{
+		{name: "default n", method: "GET", target: "/pick?chooser=experiment1", wantCode: 200, wantPicks: 1},
+		{name: "n", method: "GET", target: "/pick?chooser=experiment1&n=5", wantCode: 200, wantPicks: 5},
+		{name: "unknown", method: "GET", target: "/pick?chooser=removed", wantCode: 404},
+		{name: "invalid n", method: "GET", target: "/pick?chooser=experiment1&n=x", wantCode: 400},
+		{name: "zero n", method: "GET", target: "/pick?chooser=experiment1&n=0", wantCode: 400},
+		{name: "excessive n", method: "GET", target: "/pick?chooser=experiment1&n=11", wantCode: 400},
+		{name: "method", method: "POST", target: "/pick?chooser=experiment1", wantCode: 405},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.target, nil))
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantCode, rec.Body)
+			}
+			if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+				t.Errorf("Content-Type = %q", ct)
+			}
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+			var resp struct {
+				Chooser string
+				Picks   []string
+			}
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatal(err)
+			}
+			if resp.Chooser != "experiment1" || len(resp.Picks) != tt.wantPicks {
+				t.Errorf("response = %+v", resp)
+			}
+			for _, p := range resp.Picks {
+				if p != "a" {
+					t.Errorf("pick = %q, want %q", p, "a")
+				}
+			}
+		})
+	}
+}
+
+// constant is a Picker always returning the same item.
+type constant string
+
+func (c constant) Pick() string { return string(c) }
+
+func TestRegisterPicker(t *testing.T) {
+	h := NewHandler()
+	RegisterPicker[string](h, "fixed", constant("x"))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/pick?chooser=fixed&n=2", nil))
+	if got, want := rec.Body.String(), `{"chooser":"fixed","picks":["x","x"]}`+"\n"; got != want {
+		t.Errorf("body = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,55 @@
+package weightedrand
+
+// Interleave merges several ordered streams of items into one, choosing the
+// source of each successive item by weighted random selection amongst the
+// streams which have items remaining, while preserving the order of items
+// within each stream. This is the usual way to blend several ranked sources
+// into a single feed.
+//
+// Streams are keyed by K, with the weight of each given by weights. Streams
+// with a weight < 1, including those missing from weights, are omitted from
+// the result. Once a stream is exhausted the remaining streams are chosen
+// amongst in proportion to their weights, so the result always contains every
+// item of every stream with a weight >= 1.
+func Interleave[K comparable, T any, W integer](streams map[K][]T, weights map[K]W) ([]T, error) {
+	var n int
+	var choices []Choice[K, W]
+	for k, s := range streams {
+		if w := weights[k]; w >= 1 && len(s) > 0 {
+			choices = append(choices, NewChoice(k, w))
+			n += len(s)
+		}
+	}
+	if len(choices) == 0 {
+		return nil, nil
+	}
+
+	// The Chooser takes ownership of its choices, so each is built from a copy
+	// of the remaining streams. Since the weights of a subset of the streams
+	// can only sum to less, only the first construction can fail.
+	chooser, err := NewChooser(append([]Choice[K, W](nil), choices...)...)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]T, 0, n)
+	pos := make(map[K]int, len(choices))
+	for {
+		k := chooser.Pick()
+		out = append(out, streams[k][pos[k]])
+		pos[k]++
+		if pos[k] < len(streams[k]) {
+			continue
+		}
+
+		for i, c := range choices {
+			if c.Item == k {
+				choices = append(choices[:i], choices[i+1:]...)
+				break
+			}
+		}
+		if len(choices) == 0 {
+			return out, nil
+		}
+		chooser, _ = NewChooser(append([]Choice[K, W](nil), choices...)...)
+	}
+}
@@ -0,0 +1,71 @@
+package weightedrand
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func ExampleInterleave() {
+	feed, _ := Interleave(
+		map[string][]string{
+			"friends": {"f1", "f2", "f3"},
+			"ads":     {"ad1", "ad2"},
+		},
+		map[string]int{"friends": 1, "ads": 0},
+	)
+	fmt.Println(feed)
+	// Output: [f1 f2 f3]
+}
+
+func TestInterleave(t *testing.T) {
+	streams := map[string][]string{
+		"a":       {"a0", "a1", "a2", "a3", "a4", "a5", "a6", "a7"},
+		"b":       {"b0", "b1"},
+		"c":       {"c0", "c1", "c2", "c3"},
+		"empty":   {},
+		"zero":    {"z0"},
+		"missing": {"m0"},
+	}
+	weights := map[string]int{"a": 3, "b": 1, "c": 1, "empty": 5, "zero": 0, "unknown": 1}
+
+	var aFirst int
+	for trial := 0; trial < 1000; trial++ {
+		got, err := Interleave(streams, weights)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != 14 {
+			t.Fatalf("len(Interleave()) = %d, want 14: %v", len(got), got)
+		}
+		next := make(map[byte]int)
+		for _, item := range got {
+			stream := item[0]
+			if want := fmt.Sprintf("%c%d", stream, next[stream]); item != want {
+				t.Fatalf("Interleave() = %v, out of stream order at %s", got, item)
+			}
+			next[stream]++
+		}
+		if strings.HasPrefix(got[0], "a") {
+			aFirst++
+		}
+	}
+	// Stream a should lead 3/5 of the time.
+	if aFirst < 540 || aFirst > 660 {
+		t.Errorf("stream a led %d of 1000 times, want ~600", aFirst)
+	}
+}
+
+func TestInterleave_errors(t *testing.T) {
+	got, err := Interleave(map[string][]int{"a": {1}}, map[string]int{})
+	if len(got) != 0 || err != nil {
+		t.Errorf("Interleave() with no weighted streams = %v, %v", got, err)
+	}
+	_, err = Interleave(
+		map[string][]int{"a": {1}, "b": {2}},
+		map[string]int{"a": maxInt, "b": maxInt},
+	)
+	if err != errWeightOverflow {
+		t.Errorf("Interleave() error = %v, want %v", err, errWeightOverflow)
+	}
+}
@@ -0,0 +1,8 @@
+// Package constraints defines the type constraints shared by weightedrand and
+// its subpackages.
+package constraints
+
+// Integer is satisfied by any type which may be used as a Choice Weight.
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 | ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
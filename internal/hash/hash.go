@@ -0,0 +1,28 @@
+// Package hash provides the stable, non-cryptographic hashing shared by the
+// key-based selection of weightedrand and its subpackages. Its results must
+// never change, since callers rely on keys mapping to the same choices across
+// processes and releases.
+package hash
+
+// String returns a well mixed 64-bit hash of s: its FNV-1a hash, as computed by
+// hash/fnv but without allocating, passed through Mix.
+func String(s string) uint64 {
+	const (
+		offset64 = 14695981039346656037
+		prime64  = 1099511628211
+	)
+	h := uint64(offset64)
+	for i := 0; i < len(s); i++ {
+		h ^= uint64(s[i])
+		h *= prime64
+	}
+	return Mix(h)
+}
+
+// Mix is the splitmix64 finalizer, which spreads the entropy of z across all
+// bits, since FNV leaves the high bits of short keys poorly mixed.
+func Mix(z uint64) uint64 {
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
@@ -0,0 +1,16 @@
+package hash
+
+import (
+	"hash/fnv"
+	"testing"
+)
+
+func TestString(t *testing.T) {
+	for _, s := range []string{"", "a", "user-1234", "\xff\x00"} {
+		h := fnv.New64a()
+		h.Write([]byte(s))
+		if got, want := String(s), Mix(h.Sum64()); got != want {
+			t.Errorf("String(%q) = %#x, want %#x", s, got, want)
+		}
+	}
+}
@@ -0,0 +1,234 @@
+// Package randv2 provides pure Go implementations of the PCG and ChaCha8
+// generators of math/rand/v2, along with its Uint64N, for toolchains older than
+// Go 1.22 which lack that package. For any seed they produce exactly the same
+// sequences as math/rand/v2, so choices seeded on one toolchain are reproduced
+// on another.
+package randv2
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+// A Source is a source of uniformly distributed random uint64 values.
+type Source interface {
+	Uint64() uint64
+}
+
+// Rand is a source of random numbers.
+type Rand struct {
+	src Source
+}
+
+// New returns a new Rand that uses random values from src.
+func New(src Source) *Rand {
+	return &Rand{src: src}
+}
+
+// Uint64 returns a pseudo-random 64-bit value as a uint64.
+func (r *Rand) Uint64() uint64 {
+	return r.src.Uint64()
+}
+
+// Uint64N returns, as a uint64, a non-negative pseudo-random number in the
+// half-open interval [0,n). It panics if n == 0.
+//
+// The reduction is that of Lemire, rejecting the biased low products, as in
+// math/rand/v2, which also follows it on 32-bit platforms.
+func (r *Rand) Uint64N(n uint64) uint64 {
+	if n == 0 {
+		panic("invalid argument to Uint64N")
+	}
+	if n&(n-1) == 0 {
+		return r.src.Uint64() & (n - 1)
+	}
+	hi, lo := bits.Mul64(r.src.Uint64(), n)
+	if lo < n {
+		thresh := -n % n
+		for lo < thresh {
+			hi, lo = bits.Mul64(r.src.Uint64(), n)
+		}
+	}
+	return hi
+}
+
+// A PCG is a PCG generator with 128 bits of internal state, using the DXSM
+// output function.
+type PCG struct {
+	hi uint64
+	lo uint64
+}
+
+// NewPCG returns a new PCG seeded with the given values.
+func NewPCG(seed1, seed2 uint64) *PCG {
+	return &PCG{seed1, seed2}
+}
+
+// Uint64 returns a uniformly distributed random uint64 value.
+func (p *PCG) Uint64() uint64 {
+	const (
+		mulHi = 2549297995355413924
+		mulLo = 4865540595714422341
+		incHi = 6364136223846793005
+		incLo = 1442695040888963407
+	)
+
+	// State update: a 128-bit multiply and add.
+	hi, lo := bits.Mul64(p.lo, mulLo)
+	hi += p.hi*mulLo + p.lo*mulHi
+	lo, c := bits.Add64(lo, incLo, 0)
+	hi, _ = bits.Add64(hi, incHi, c)
+	p.lo = lo
+	p.hi = hi
+
+	// DXSM output function, with a 64-bit multiplier.
+	const cheapMul = 0xda942042e4dd58b5
+	hi ^= hi >> 32
+	hi *= cheapMul
+	hi ^= hi >> 48
+	hi *= (lo | 1)
+	return hi
+}
+
+// The ChaCha8 generator emits the output of four interleaved ChaCha8 blocks at
+// a time, and rekeys itself from the last four words of every sixteenth block,
+// which are withheld from its output.
+const (
+	ctrInc = 4  // increment counter by 4 between block calls
+	ctrMax = 16 // reseed when counter reaches 16
+	chunk  = 32 // each chunk produced by block is 32 uint64s
+	reseed = 4  // reseed with 4 words
+)
+
+// A ChaCha8 is a ChaCha8-based cryptographically strong random number
+// generator.
+type ChaCha8 struct {
+	buf  [chunk]uint64
+	seed [4]uint64
+	i    uint32
+	n    uint32
+	c    uint32
+}
+
+// NewChaCha8 returns a new ChaCha8 seeded with the given seed.
+func NewChaCha8(seed [32]byte) *ChaCha8 {
+	c := new(ChaCha8)
+	c.init([4]uint64{
+		binary.LittleEndian.Uint64(seed[0*8:]),
+		binary.LittleEndian.Uint64(seed[1*8:]),
+		binary.LittleEndian.Uint64(seed[2*8:]),
+		binary.LittleEndian.Uint64(seed[3*8:]),
+	})
+	return c
+}
+
+func (c *ChaCha8) init(seed [4]uint64) {
+	c.seed = seed
+	block(&c.seed, &c.buf, 0)
+	c.c = 0
+	c.i = 0
+	c.n = chunk
+}
+
+// Uint64 returns a uniformly distributed random uint64 value.
+func (c *ChaCha8) Uint64() uint64 {
+	if c.i >= c.n {
+		c.refill()
+	}
+	x := c.buf[c.i&(chunk-1)]
+	c.i++
+	return x
+}
+
+func (c *ChaCha8) refill() {
+	c.c += ctrInc
+	if c.c == ctrMax {
+		// Reseed with the withheld words of the last chunk.
+		copy(c.seed[:], c.buf[chunk-reseed:])
+		c.c = 0
+	}
+	block(&c.seed, &c.buf, c.c)
+	c.i = 0
+	c.n = chunk
+	if c.c == ctrMax-ctrInc {
+		c.n = chunk - reseed
+	}
+}
+
+// block computes four ChaCha8 blocks for the key seed, with the counters
+// counter through counter+3 and a zero nonce, and stores them in buf
+// interleaved, such that word j of all four blocks precedes word j+1 of any.
+func block(seed *[4]uint64, buf *[chunk]uint64, counter uint32) {
+	var b [16][4]uint32
+	for i := range b[0] {
+		b[0][i] = 0x61707865
+		b[1][i] = 0x3320646e
+		b[2][i] = 0x79622d32
+		b[3][i] = 0x6b206574
+		for j, s := range seed {
+			b[4+2*j][i] = uint32(s)
+			b[5+2*j][i] = uint32(s >> 32)
+		}
+		b[12][i] = counter + uint32(i)
+	}
+
+	for i := range b[0] {
+		b0, b1, b2, b3 := b[0][i], b[1][i], b[2][i], b[3][i]
+		b4, b5, b6, b7 := b[4][i], b[5][i], b[6][i], b[7][i]
+		b8, b9, b10, b11 := b[8][i], b[9][i], b[10][i], b[11][i]
+		b12, b13, b14, b15 := b[12][i], b[13][i], b[14][i], b[15][i]
+
+		for round := 0; round < 4; round++ {
+			b0, b4, b8, b12 = qr(b0, b4, b8, b12)
+			b1, b5, b9, b13 = qr(b1, b5, b9, b13)
+			b2, b6, b10, b14 = qr(b2, b6, b10, b14)
+			b3, b7, b11, b15 = qr(b3, b7, b11, b15)
+
+			b0, b5, b10, b15 = qr(b0, b5, b10, b15)
+			b1, b6, b11, b12 = qr(b1, b6, b11, b12)
+			b2, b7, b8, b13 = qr(b2, b7, b8, b13)
+			b3, b4, b9, b14 = qr(b3, b4, b9, b14)
+		}
+
+		// Unlike standard ChaCha, only the key words are added back in,
+		// since the constants and counter are public anyway.
+		b[0][i] = b0
+		b[1][i] = b1
+		b[2][i] = b2
+		b[3][i] = b3
+		b[4][i] += b4
+		b[5][i] += b5
+		b[6][i] += b6
+		b[7][i] += b7
+		b[8][i] += b8
+		b[9][i] += b9
+		b[10][i] += b10
+		b[11][i] += b11
+		b[12][i] = b12
+		b[13][i] = b13
+		b[14][i] = b14
+		b[15][i] = b15
+	}
+
+	for j := range buf {
+		k := 2 * j
+		buf[j] = uint64(b[k/4][k%4]) | uint64(b[(k+1)/4][(k+1)%4])<<32
+	}
+}
+
+// qr is the ChaCha quarter round.
+func qr(a, b, c, d uint32) (_a, _b, _c, _d uint32) {
+	a += b
+	d ^= a
+	d = bits.RotateLeft32(d, 16)
+	c += d
+	b ^= c
+	b = bits.RotateLeft32(b, 12)
+	a += b
+	d ^= a
+	d = bits.RotateLeft32(d, 8)
+	c += d
+	b ^= c
+	b = bits.RotateLeft32(b, 7)
+	return a, b, c, d
+}
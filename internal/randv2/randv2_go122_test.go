@@ -0,0 +1,49 @@
+//go:build go1.22
+
+package randv2
+
+import (
+	randv2 "math/rand/v2"
+	"testing"
+)
+
+// The fallbacks must reproduce math/rand/v2 exactly, for any seed and across
+// enough values to span several ChaCha8 reseeds.
+const parityValues = 10000
+
+func TestPCG_parity(t *testing.T) {
+	for _, seed := range [][2]uint64{{0, 0}, {1, 2}, {^uint64(0), 42}} {
+		got, want := NewPCG(seed[0], seed[1]), randv2.NewPCG(seed[0], seed[1])
+		for i := 0; i < parityValues; i++ {
+			if g, w := got.Uint64(), want.Uint64(); g != w {
+				t.Fatalf("seed %v: Uint64() #%d = %#x, want %#x", seed, i, g, w)
+			}
+		}
+	}
+}
+
+func TestChaCha8_parity(t *testing.T) {
+	for s := 0; s < 3; s++ {
+		var seed [32]byte
+		for i := range seed {
+			seed[i] = byte(s * (i + 1))
+		}
+		got, want := NewChaCha8(seed), randv2.NewChaCha8(seed)
+		for i := 0; i < parityValues; i++ {
+			if g, w := got.Uint64(), want.Uint64(); g != w {
+				t.Fatalf("seed %d: Uint64() #%d = %#x, want %#x", s, i, g, w)
+			}
+		}
+	}
+}
+
+func TestRand_Uint64N_parity(t *testing.T) {
+	got, want := New(NewPCG(3, 4)), randv2.New(randv2.NewPCG(3, 4))
+	for _, n := range []uint64{1, 2, 3, 7, 1000, 1 << 32, 1<<32 + 1, 1<<63 + 1, ^uint64(0)} {
+		for i := 0; i < parityValues/10; i++ {
+			if g, w := got.Uint64N(n), want.Uint64N(n); g != w {
+				t.Fatalf("Uint64N(%d) #%d = %d, want %d", n, i, g, w)
+			}
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package randv2
+
+import "testing"
+
+// Known answers, as produced by math/rand/v2 in Go 1.22, so that parity is
+// checked on toolchains without it. See also randv2_go122_test.go.
+
+func TestPCG(t *testing.T) {
+	p := NewPCG(1, 2)
+	want := []uint64{0xc4f5a58656eef510, 0x9dcec3ad077dec6c, 0xc8d04605312f8088}
+	for i, w := range want {
+		if got := p.Uint64(); got != w {
+			t.Errorf("Uint64() #%d = %#x, want %#x", i, got, w)
+		}
+	}
+}
+
+func TestChaCha8(t *testing.T) {
+	var seed [32]byte
+	copy(seed[:], "chacha8 seed for weightedrand!!!")
+	c := NewChaCha8(seed)
+	want := []uint64{0xc55c3c9ba18e4cc4, 0xf51a49ca714fdb5, 0x9a32efd0a827e5b8}
+	for i, w := range want {
+		if got := c.Uint64(); got != w {
+			t.Errorf("Uint64() #%d = %#x, want %#x", i, got, w)
+		}
+	}
+	// Past the first reseed, after 4*chunk-reseed values.
+	for i := 0; i < 1000; i++ {
+		c.Uint64()
+	}
+	if got, want := c.Uint64(), uint64(0x613a740564519438); got != want {
+		t.Errorf("Uint64() #1003 = %#x, want %#x", got, want)
+	}
+}
+
+func TestRand_Uint64N(t *testing.T) {
+	r := New(NewPCG(1, 2))
+	for _, tt := range []struct{ n, want uint64 }{
+		{7, 5},
+		{1 << 40, 743155035244},
+		{1<<63 + 1, 7235071295427690564},
+	} {
+		if got := r.Uint64N(tt.n); got != tt.want {
+			t.Errorf("Uint64N(%d) = %d, want %d", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestRand_Uint64N_zero(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Uint64N(0) did not panic")
+		}
+	}()
+	New(NewPCG(1, 2)).Uint64N(0)
+}
+
+func BenchmarkPCG(b *testing.B) {
+	p := NewPCG(1, 2)
+	for i := 0; i < b.N; i++ {
+		_ = p.Uint64()
+	}
+}
+
+func BenchmarkChaCha8(b *testing.B) {
+	c := NewChaCha8([32]byte{})
+	for i := 0; i < b.N; i++ {
+		_ = c.Uint64()
+	}
+}
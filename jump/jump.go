@@ -0,0 +1,93 @@
+// Package jump implements weighted jump consistent hashing, for mapping very
+// large volumes of keys to weighted buckets in O(log n) time, without the
+// per-key O(n) scan of the rendezvous package.
+//
+// It applies the jump consistent hash of Lamping and Veach over one virtual
+// bucket per unit of weight, with each bucket owning a contiguous run of
+// virtual buckets in the order given. Jump hashing only moves the minimal
+// fraction of keys when virtual buckets are appended to or removed from the
+// end, so changes should be made there:
+//
+//   - To add a bucket, append it.
+//   - To increase the weight of a bucket, append another Choice for the same
+//     bucket, rather than changing the weight of its existing Choice.
+//   - To decrease the weight of, or remove, the last buckets, reduce or remove
+//     the last Choices.
+//
+// Any other change, such as reweighting a Choice in the middle, reassigns many
+// keys between unchanged buckets. Use the rendezvous package if arbitrary
+// changes must move the minimal fraction of keys.
+package jump
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/mroth/weightedrand/v2"
+	"github.com/mroth/weightedrand/v2/internal/constraints"
+	"github.com/mroth/weightedrand/v2/internal/hash"
+)
+
+// maxTotal is the maximum sum of weights, the number of buckets supported by
+// the jump hash algorithm.
+const maxTotal = 1<<31 - 1
+
+// Possible errors returned by New.
+var (
+	ErrNoValidBuckets = errors.New("jump: zero buckets with weight >= 1")
+	ErrWeightOverflow = errors.New("jump: sum of weights exceeds 2^31-1")
+)
+
+// A Hash consistently maps keys to weighted buckets. It is immutable and safe
+// for concurrent usage; to change its buckets, construct a new Hash.
+type Hash[T any] struct {
+	buckets []T
+	totals  []int64 // cumulative weights, one per bucket
+}
+
+// New initializes a Hash over buckets, in order. The same bucket may appear in
+// more than one Choice, with keys assigned to it in proportion to its combined
+// weight. Choices with a weight < 1 are ignored.
+func New[T any, W constraints.Integer](buckets ...weightedrand.Choice[T, W]) (*Hash[T], error) {
+	h := &Hash[T]{}
+	var total int64
+	for _, b := range buckets {
+		if b.Weight < 1 {
+			continue
+		}
+		if uint64(b.Weight) > maxTotal || maxTotal-total < int64(b.Weight) {
+			return nil, ErrWeightOverflow
+		}
+		total += int64(b.Weight)
+		h.buckets = append(h.buckets, b.Item)
+		h.totals = append(h.totals, total)
+	}
+	if len(h.buckets) == 0 {
+		return nil, ErrNoValidBuckets
+	}
+	return h, nil
+}
+
+// Pick returns the bucket assigned to key.
+func (h *Hash[T]) Pick(key uint64) T {
+	v := jumpHash(key, h.totals[len(h.totals)-1])
+	i := sort.Search(len(h.totals), func(i int) bool { return h.totals[i] > v })
+	return h.buckets[i]
+}
+
+// PickString returns the bucket assigned to the string key.
+func (h *Hash[T]) PickString(key string) T {
+	return h.Pick(hash.String(key))
+}
+
+// jumpHash returns the bucket in [0,n) for key, per "A Fast, Minimal Memory,
+// Consistent Hash Algorithm" by Lamping and Veach.
+func jumpHash(key uint64, n int64) int64 {
+	b, j := int64(-1), int64(0)
+	for j < n {
+		b = j
+		key = key*2862933555777941757 + 1
+		j = int64(float64(b+1) * (float64(int64(1)<<31) / float64((key>>33)+1)))
+	}
+	return b
+}
@@ -0,0 +1,162 @@
+package jump
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/mroth/weightedrand/v2"
+	"github.com/mroth/weightedrand/v2/internal/hash"
+)
+
+func ExampleHash_PickString() {
+	shards, _ := New(
+		weightedrand.NewChoice("shard-0", 1),
+		weightedrand.NewChoice("shard-1", 3),
+	)
+	shard := shards.PickString("user:1234")
+	fmt.Println(shard == shards.PickString("user:1234"))
+	//Output: true
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		buckets []weightedrand.Choice[string, uint64]
+		wantErr error
+	}{
+		{name: "no buckets", wantErr: ErrNoValidBuckets},
+		{name: "zero weights", buckets: []weightedrand.Choice[string, uint64]{weightedrand.NewChoice("a", uint64(0))}, wantErr: ErrNoValidBuckets},
+		{name: "max total", buckets: []weightedrand.Choice[string, uint64]{weightedrand.NewChoice("a", uint64(maxTotal))}},
+		{name: "single overflow", buckets: []weightedrand.Choice[string, uint64]{weightedrand.NewChoice("a", uint64(1<<63))}, wantErr: ErrWeightOverflow},
+		{name: "sum overflow", buckets: []weightedrand.Choice[string, uint64]{weightedrand.NewChoice("a", uint64(maxTotal)), weightedrand.NewChoice("b", uint64(1))}, wantErr: ErrWeightOverflow},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(tt.buckets...); err != tt.wantErr {
+				t.Errorf("New() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// Reference values from other implementations of the algorithm.
+func TestJumpHash(t *testing.T) {
+	tests := []struct {
+		key  uint64
+		n    int64
+		want int64
+	}{
+		{key: 1, n: 1, want: 0},
+		{key: 42, n: 57, want: 43},
+		{key: 0xdead10cc, n: 1, want: 0},
+		{key: 0xdead10cc, n: 666, want: 361},
+		{key: 256, n: 1024, want: 520},
+	}
+	for _, tt := range tests {
+		if got := jumpHash(tt.key, tt.n); got != tt.want {
+			t.Errorf("jumpHash(%#x, %d) = %d, want %d", tt.key, tt.n, got, tt.want)
+		}
+	}
+}
+
+const numKeys = 100_000
+
+func assign(h *Hash[string]) []string {
+	buckets := make([]string, numKeys)
+	for i := range buckets {
+		buckets[i] = h.Pick(hash.Mix(uint64(i)))
+	}
+	return buckets
+}
+
+func TestHash_Pick_distribution(t *testing.T) {
+	h, _ := New(
+		weightedrand.NewChoice("a", 1),
+		weightedrand.NewChoice("b", 2),
+		weightedrand.NewChoice("zero", 0),
+		weightedrand.NewChoice("c", 3),
+		weightedrand.NewChoice("a", 2),
+	)
+	counts := make(map[string]int)
+	for _, b := range assign(h) {
+		counts[b]++
+	}
+	for bucket, weight := range map[string]float64{"a": 3, "b": 2, "c": 3} {
+		want := weight / 8
+		if got := float64(counts[bucket]) / numKeys; math.Abs(got-want) > 0.01 {
+			t.Errorf("fraction of keys on %s = %.3f, want %.3f", bucket, got, want)
+		}
+	}
+}
+
+func TestHash_Pick_churn(t *testing.T) {
+	base := []weightedrand.Choice[string, int]{
+		weightedrand.NewChoice("a", 2),
+		weightedrand.NewChoice("b", 2),
+		weightedrand.NewChoice("c", 4),
+	}
+	h, _ := New(base...)
+	before := assign(h)
+
+	tests := []struct {
+		name    string
+		buckets []weightedrand.Choice[string, int]
+		changed string  // the only bucket keys may move to or from, if minimal
+		moved   float64 // expected fraction of keys moved
+	}{
+		{name: "append bucket", buckets: append(base[:3:3], weightedrand.NewChoice("d", 8)), changed: "d", moved: 0.5},
+		// Keys move onto the new virtual buckets from every bucket, including
+		// those of a itself, which therefore do not change buckets.
+		{name: "increase weight by appending", buckets: append(base[:3:3], weightedrand.NewChoice("a", 2)), changed: "a", moved: 0.2 * (1 - 2.0/8)},
+		{name: "decrease weight of last", buckets: []weightedrand.Choice[string, int]{base[0], base[1], weightedrand.NewChoice("c", 2)}, changed: "c", moved: 0.25 * (1 - 2.0/6)},
+		{name: "remove last", buckets: base[:2], changed: "c", moved: 0.5},
+		// Reweighting in the middle shifts the virtual buckets of those after
+		// it, so keys move between unchanged buckets too.
+		{name: "reweight middle", buckets: []weightedrand.Choice[string, int]{base[0], weightedrand.NewChoice("b", 4), base[2]}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h2, _ := New(tt.buckets...)
+			after := assign(h2)
+			moved, unrelated := 0, 0
+			for i := range before {
+				if before[i] == after[i] {
+					continue
+				}
+				moved++
+				if before[i] != tt.changed && after[i] != tt.changed {
+					unrelated++
+				}
+			}
+			if tt.changed == "" {
+				if unrelated == 0 {
+					t.Error("expected keys to move between unchanged buckets")
+				}
+				return
+			}
+			if unrelated > 0 {
+				t.Errorf("%d keys moved between unchanged buckets", unrelated)
+			}
+			if got := float64(moved) / numKeys; math.Abs(got-tt.moved) > 0.01 {
+				t.Errorf("fraction of keys moved = %.3f, want %.3f", got, tt.moved)
+			}
+		})
+	}
+}
+
+func BenchmarkHash_Pick(b *testing.B) {
+	for _, n := range []int{10, 1000} {
+		b.Run(fmt.Sprintf("buckets=%d", n), func(b *testing.B) {
+			buckets := make([]weightedrand.Choice[int, int], n)
+			for i := range buckets {
+				buckets[i] = weightedrand.NewChoice(i, i+1)
+			}
+			h, _ := New(buckets...)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_ = h.Pick(uint64(i))
+			}
+		})
+	}
+}
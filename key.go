@@ -0,0 +1,27 @@
+package weightedrand
+
+import (
+	"math/bits"
+
+	"github.com/mroth/weightedrand/v2/internal/hash"
+)
+
+// PickByKey deterministically maps key to a Choice.Item, such that over many
+// distinct keys each item is selected with probability proportional to its
+// weight. The same key always yields the same item from Choosers constructed
+// from the same choices, even across processes, subject to the ordering of
+// equal weights described below, which makes it suitable for sticky assignment
+// such as of users to the variants of an experiment.
+//
+// The key is hashed into the Chooser's cumulative weight table, so changing
+// any weight generally reassigns some keys belonging to other choices as well;
+// see the rendezvous subpackage for assignments which change minimally.
+// Choices of equal weight are ordered by sorting, which may differ between Go
+// releases, and for Choosers built WithParallelBuild also with GOMAXPROCS; to
+// rule this out, construct the Chooser from choices already in ascending order
+// of weight, which are never reordered.
+func (c Chooser[T, W]) PickByKey(key string) T {
+	hi, _ := bits.Mul64(hash.String(key), uint64(c.max))
+	i := c.search(int(hi) + 1)
+	return c.selected(i)
+}
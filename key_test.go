@@ -0,0 +1,55 @@
+package weightedrand
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+)
+
+func ExampleChooser_PickByKey() {
+	chooser, _ := NewChooser(
+		NewChoice("control", 1),
+		NewChoice("variant", 1),
+	)
+	first := chooser.PickByKey("user-1234")
+	for i := 0; i < 10; i++ {
+		if chooser.PickByKey("user-1234") != first {
+			fmt.Println("reassigned!")
+		}
+	}
+	fmt.Println("consistent")
+	//Output: consistent
+}
+
+func TestChooser_PickByKey(t *testing.T) {
+	choices := mockFrequencyChoices(t, testChoices)
+	chooser, err := NewChooser(choices...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	counts := make(map[int]int)
+	for i := 0; i < testIterations; i++ {
+		counts[chooser.PickByKey("user-"+strconv.Itoa(i))]++
+	}
+	verifyFrequencyCounts(t, counts, choices)
+
+	// Assignments are a fixed function of the key, so they must not change
+	// between releases. Weights of 1 and 3 map 1/4 of keys to "a".
+	fixed, _ := NewChooser(NewChoice("a", 1), NewChoice("b", 3))
+	var got string
+	for _, key := range []string{"alice", "bob", "carol", "dave", "erin", "frank"} {
+		got += fixed.PickByKey(key)
+	}
+	if want := "bbabbb"; got != want {
+		t.Errorf("PickByKey assignments = %q, want %q", got, want)
+	}
+}
+
+func BenchmarkPickByKey(b *testing.B) {
+	chooser, _ := NewChooser(mockChoices(1000)...)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = chooser.PickByKey("user-1234")
+	}
+}
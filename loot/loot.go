@@ -0,0 +1,196 @@
+// Package loot implements game-style drop tables. Each table makes a number of
+// weighted rolls among its entries, which may drop items, nothing, or the
+// result of rolling another table, in addition to any guaranteed drops.
+//
+// Tables are validated as a set when constructed, so that references to
+// unknown tables and cycles of references are reported up front rather than
+// when a player happens to roll them.
+package loot
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+
+	"github.com/mroth/weightedrand/v2"
+)
+
+// A Range is an inclusive range of counts, chosen uniformly at random. The zero
+// Range is treated as exactly 1.
+type Range struct {
+	Min, Max int
+}
+
+func (r Range) normalize() Range {
+	if r == (Range{}) {
+		return Range{Min: 1, Max: 1}
+	}
+	return r
+}
+
+func (r Range) roll() int {
+	if r.Max == r.Min {
+		return r.Min
+	}
+	return r.Min + rand.Intn(r.Max-r.Min+1)
+}
+
+// An Entry is a possible outcome of a roll of a Table.
+type Entry[T any] struct {
+	Item    T      // item dropped, unless Table is set or Nothing is true
+	Table   string // if set, name of a table to roll instead of dropping Item
+	Nothing bool   // if true, the entry drops nothing
+	Weight  int    // ignored for guaranteed entries
+	Count   Range  // quantity of Item dropped, or number of rolls of Table
+}
+
+// A Table makes a number of weighted rolls among its Entries, plus one roll of
+// each of its Guaranteed entries. A table without Entries makes no weighted
+// rolls unless Rolls is set, which is an error.
+type Table[T any] struct {
+	Rolls      Range
+	Entries    []Entry[T]
+	Guaranteed []Entry[T]
+}
+
+// A Drop is an item and quantity resulting from a roll.
+type Drop[T any] struct {
+	Item  T
+	Count int
+}
+
+// ErrUnknownTable is returned when rolling, or referring to, a table which does
+// not exist.
+var ErrUnknownTable = errors.New("loot: unknown table")
+
+// Tables is a validated set of named drop tables. It is immutable and safe for
+// concurrent usage.
+type Tables[T any] struct {
+	tables map[string]*table[T]
+}
+
+type table[T any] struct {
+	rolls      Range
+	entries    []Entry[T]
+	chooser    *weightedrand.Chooser[int, int] // index into entries, nil if none
+	guaranteed []Entry[T]
+}
+
+// New validates and compiles the named tables.
+func New[T any](tables map[string]Table[T]) (*Tables[T], error) {
+	ts := &Tables[T]{tables: make(map[string]*table[T], len(tables))}
+	for name, t := range tables {
+		compiled, err := compile(t)
+		if err != nil {
+			return nil, fmt.Errorf("loot: table %q: %w", name, err)
+		}
+		ts.tables[name] = compiled
+	}
+	for name := range tables {
+		if err := ts.checkRefs(name, make(map[string]bool)); err != nil {
+			return nil, err
+		}
+	}
+	return ts, nil
+}
+
+func compile[T any](t Table[T]) (*table[T], error) {
+	c := &table[T]{rolls: t.Rolls, entries: t.Entries, guaranteed: t.Guaranteed}
+	if len(t.Entries) > 0 {
+		c.rolls = c.rolls.normalize()
+	}
+	if err := checkRange("rolls", c.rolls); err != nil {
+		return nil, err
+	}
+	choices := make([]weightedrand.Choice[int, int], len(t.Entries))
+	for i, e := range t.Entries {
+		if e.Weight < 0 {
+			return nil, fmt.Errorf("entry %d: negative weight %d", i, e.Weight)
+		}
+		if err := checkRange(fmt.Sprintf("entry %d count", i), e.Count.normalize()); err != nil {
+			return nil, err
+		}
+		choices[i] = weightedrand.NewChoice(i, e.Weight)
+	}
+	for i, e := range t.Guaranteed {
+		if err := checkRange(fmt.Sprintf("guaranteed entry %d count", i), e.Count.normalize()); err != nil {
+			return nil, err
+		}
+	}
+	if c.rolls.Max == 0 {
+		return c, nil
+	}
+	chooser, err := weightedrand.NewChooser(choices...)
+	if err != nil {
+		return nil, fmt.Errorf("rolls %d-%d times but has no entries with weight >= 1", c.rolls.Min, c.rolls.Max)
+	}
+	c.chooser = chooser
+	return c, nil
+}
+
+func checkRange(what string, r Range) error {
+	if r.Min < 0 || r.Max < r.Min {
+		return fmt.Errorf("invalid %s range %d-%d", what, r.Min, r.Max)
+	}
+	return nil
+}
+
+// checkRefs reports references from the named table to unknown tables, and
+// cycles of references, where path holds the tables referencing it.
+func (ts *Tables[T]) checkRefs(name string, path map[string]bool) error {
+	if path[name] {
+		return fmt.Errorf("loot: table %q refers to itself", name)
+	}
+	path[name] = true
+	defer delete(path, name)
+
+	t := ts.tables[name]
+	for _, entries := range [][]Entry[T]{t.entries, t.guaranteed} {
+		for _, e := range entries {
+			if e.Table == "" || e.Nothing {
+				continue
+			}
+			if _, ok := ts.tables[e.Table]; !ok {
+				return fmt.Errorf("%w %q referenced by table %q", ErrUnknownTable, e.Table, name)
+			}
+			if err := ts.checkRefs(e.Table, path); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Roll rolls the named table, returning the resulting drops in the order they
+// were rolled, guaranteed drops first.
+func (ts *Tables[T]) Roll(name string) ([]Drop[T], error) {
+	t, ok := ts.tables[name]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownTable, name)
+	}
+	return ts.roll(t, nil), nil
+}
+
+func (ts *Tables[T]) roll(t *table[T], drops []Drop[T]) []Drop[T] {
+	for _, e := range t.guaranteed {
+		drops = ts.resolve(e, drops)
+	}
+	for n := t.rolls.roll(); n > 0; n-- {
+		drops = ts.resolve(t.entries[t.chooser.Pick()], drops)
+	}
+	return drops
+}
+
+func (ts *Tables[T]) resolve(e Entry[T], drops []Drop[T]) []Drop[T] {
+	count := e.Count.normalize().roll()
+	switch {
+	case e.Nothing || count == 0:
+		return drops
+	case e.Table != "":
+		for i := 0; i < count; i++ {
+			drops = ts.roll(ts.tables[e.Table], drops)
+		}
+		return drops
+	}
+	return append(drops, Drop[T]{Item: e.Item, Count: count})
+}
@@ -0,0 +1,105 @@
+package loot
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+)
+
+func ExampleTables_Roll() {
+	tables, _ := New(map[string]Table[string]{
+		"goblin": {
+			Guaranteed: []Entry[string]{{Item: "gold", Count: Range{Min: 5, Max: 5}}},
+			Rolls:      Range{Min: 1, Max: 1},
+			Entries: []Entry[string]{
+				{Table: "gems", Weight: 1},
+				{Nothing: true, Weight: 0},
+			},
+		},
+		"gems": {
+			Entries: []Entry[string]{{Item: "ruby", Weight: 1}},
+		},
+	})
+	drops, _ := tables.Roll("goblin")
+	fmt.Println(drops)
+	//Output: [{gold 5} {ruby 1}]
+}
+
+func TestNew(t *testing.T) {
+	item := []Entry[string]{{Item: "x", Weight: 1}}
+	tests := []struct {
+		name    string
+		tables  map[string]Table[string]
+		wantErr string
+	}{
+		{name: "guaranteed only", tables: map[string]Table[string]{"a": {Guaranteed: item}}},
+		{name: "unknown reference", tables: map[string]Table[string]{"a": {Entries: []Entry[string]{{Table: "b", Weight: 1}}}}, wantErr: `unknown table "b" referenced by table "a"`},
+		{name: "cycle", tables: map[string]Table[string]{
+			"a": {Entries: []Entry[string]{{Table: "b", Weight: 1}}},
+			"b": {Guaranteed: []Entry[string]{{Table: "a"}}},
+		}, wantErr: "refers to itself"},
+		{name: "negative weight", tables: map[string]Table[string]{"a": {Entries: []Entry[string]{{Item: "x", Weight: -1}}}}, wantErr: `table "a": entry 0: negative weight`},
+		{name: "bad rolls", tables: map[string]Table[string]{"a": {Rolls: Range{Min: 2, Max: 1}, Entries: item}}, wantErr: "invalid rolls range 2-1"},
+		{name: "bad count", tables: map[string]Table[string]{"a": {Entries: []Entry[string]{{Item: "x", Weight: 1, Count: Range{Min: -1}}}}}, wantErr: "invalid entry 0 count range"},
+		{name: "rolls without entries", tables: map[string]Table[string]{"a": {Rolls: Range{Min: 1, Max: 2}}}, wantErr: "no entries with weight >= 1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(tt.tables)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("New() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("New() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTables_Roll(t *testing.T) {
+	tables, err := New(map[string]Table[string]{
+		"chest": {
+			Rolls: Range{Min: 2, Max: 4},
+			Entries: []Entry[string]{
+				{Item: "potion", Weight: 3, Count: Range{Min: 1, Max: 3}},
+				{Nothing: true, Weight: 1},
+				{Table: "rare", Weight: 0},
+			},
+		},
+		"rare": {Entries: []Entry[string]{{Item: "sword", Weight: 1}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tables.Roll("missing"); !errors.Is(err, ErrUnknownTable) {
+		t.Errorf("Roll() error = %v, want %v", err, ErrUnknownTable)
+	}
+
+	const n = 10000
+	potions, drops := 0, 0
+	for i := 0; i < n; i++ {
+		result, _ := tables.Roll("chest")
+		if len(result) > 4 {
+			t.Fatalf("Roll() made %d drops from at most 4 rolls", len(result))
+		}
+		for _, d := range result {
+			if d.Item != "potion" || d.Count < 1 || d.Count > 3 {
+				t.Fatalf("unexpected drop %v", d)
+			}
+			potions += d.Count
+			drops++
+		}
+	}
+	// 3 rolls on average, each dropping 2 potions with probability 3/4
+	if got := float64(potions) / n; math.Abs(got-4.5) > 0.1 {
+		t.Errorf("mean potions per roll = %.2f, want 4.5", got)
+	}
+	if got := float64(drops) / n; math.Abs(got-2.25) > 0.05 {
+		t.Errorf("mean drops per roll = %.2f, want 2.25", got)
+	}
+}
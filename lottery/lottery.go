@@ -0,0 +1,152 @@
+// Package lottery draws distinct weighted winners from a set of entrants, such
+// as for raffles where each entrant holds some number of tickets.
+//
+// Draws are derived deterministically from a secret 256-bit Seed using SHA-256,
+// so that they can be audited. Before entries close, the operator generates a
+// Seed with NewSeed and publishes its Commitment. After the draw, the operator
+// reveals the Seed, and anyone can check it against the published Commitment
+// and repeat the draw with DrawSeeded to confirm the winners, with no way for
+// the operator to have chosen a Seed favoring any entrant.
+package lottery
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+
+	"github.com/mroth/weightedrand/v2"
+	"github.com/mroth/weightedrand/v2/internal/constraints"
+)
+
+// Possible errors returned by Draw and DrawSeeded.
+var (
+	ErrWinners        = errors.New("lottery: more winners than entrants with weight >= 1")
+	ErrWeightOverflow = errors.New("lottery: sum of weights exceeds max uint64")
+)
+
+// A Seed is the secret randomness from which a draw is derived.
+type Seed [32]byte
+
+// NewSeed returns a Seed from the system CSPRNG.
+func NewSeed() (Seed, error) {
+	var s Seed
+	_, err := rand.Read(s[:])
+	return s, err
+}
+
+// Commitment returns the SHA-256 hash of s, which may be published before a
+// draw to bind the operator to s without revealing it.
+func (s Seed) Commitment() Commitment {
+	return sha256.Sum256(s[:])
+}
+
+// String returns s in hexadecimal.
+func (s Seed) String() string { return hex.EncodeToString(s[:]) }
+
+// A Commitment is the SHA-256 hash of a Seed.
+type Commitment [32]byte
+
+// String returns c in hexadecimal.
+func (c Commitment) String() string { return hex.EncodeToString(c[:]) }
+
+// A Result is the auditable record of a draw.
+type Result[T any] struct {
+	Winners    []T   // in the order drawn
+	Indices    []int // of each winner among the entrants
+	Seed       Seed
+	Commitment Commitment
+}
+
+// Draw selects the given number of distinct winners from entrants, each with a
+// chance of being drawn proportional to its weight among those not yet drawn,
+// using a fresh Seed from the system CSPRNG. Entrants with a weight < 1 can
+// never win.
+func Draw[T any, W constraints.Integer](winners int, entrants []weightedrand.Choice[T, W]) ([]T, error) {
+	seed, err := NewSeed()
+	if err != nil {
+		return nil, err
+	}
+	r, err := DrawSeeded(seed, winners, entrants)
+	if err != nil {
+		return nil, err
+	}
+	return r.Winners, nil
+}
+
+// DrawSeeded is like Draw, but derives the draw from seed, returning a Result
+// which records it. The same seed and entrants, in the same order, always
+// produce the same Result, on any platform.
+func DrawSeeded[T any, W constraints.Integer](seed Seed, winners int, entrants []weightedrand.Choice[T, W]) (*Result[T], error) {
+	weights := make([]uint64, len(entrants))
+	var total uint64
+	valid := 0
+	for i, e := range entrants {
+		if e.Weight < 1 {
+			continue
+		}
+		w := uint64(e.Weight)
+		if total+w < total {
+			return nil, ErrWeightOverflow
+		}
+		weights[i] = w
+		total += w
+		valid++
+	}
+	if winners < 0 || winners > valid {
+		return nil, ErrWinners
+	}
+
+	r := &Result[T]{Seed: seed, Commitment: seed.Commitment()}
+	src := &drbg{seed: seed}
+	for len(r.Winners) < winners {
+		x := src.uint64n(total)
+		i := 0
+		for x >= weights[i] {
+			x -= weights[i]
+			i++
+		}
+		total -= weights[i]
+		weights[i] = 0 // drawn without replacement
+		r.Winners = append(r.Winners, entrants[i].Item)
+		r.Indices = append(r.Indices, i)
+	}
+	return r, nil
+}
+
+// drbg is a deterministic random bit generator producing the SHA-256 hashes of
+// its seed followed by a block counter.
+type drbg struct {
+	seed    Seed
+	counter uint64
+	block   [sha256.Size]byte
+	used    int // bytes of block consumed
+}
+
+func (d *drbg) uint64() uint64 {
+	if d.counter == 0 || d.used == len(d.block) {
+		var msg [len(Seed{}) + 8]byte
+		copy(msg[:], d.seed[:])
+		binary.BigEndian.PutUint64(msg[len(d.seed):], d.counter)
+		d.block = sha256.Sum256(msg[:])
+		d.counter++
+		d.used = 0
+	}
+	v := binary.BigEndian.Uint64(d.block[d.used:])
+	d.used += 8
+	return v
+}
+
+// uint64n returns a uniform random number in [0,n) for n > 0, rejecting draws
+// from the final incomplete multiple of n so that the result is exactly
+// uniform.
+func (d *drbg) uint64n(n uint64) uint64 {
+	limit := -n % n // (2^64 - n) % n == 2^64 % n
+	for {
+		v := d.uint64()
+		if v >= limit {
+			return v % n
+		}
+	}
+}
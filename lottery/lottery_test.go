@@ -0,0 +1,110 @@
+package lottery
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/mroth/weightedrand/v2"
+)
+
+func ExampleDrawSeeded() {
+	// Before entries close, the operator generates a seed and publishes its
+	// commitment.
+	seed, _ := NewSeed()
+	published := seed.Commitment()
+
+	entrants := []weightedrand.Choice[string, int]{
+		weightedrand.NewChoice("alice", 5), // tickets held
+		weightedrand.NewChoice("bob", 1),
+		weightedrand.NewChoice("carol", 3),
+	}
+	result, _ := DrawSeeded(seed, 2, entrants)
+
+	// After the draw, the operator reveals the seed, and anyone can audit it.
+	audit, _ := DrawSeeded(result.Seed, 2, entrants)
+	fmt.Println(result.Seed.Commitment() == published)
+	fmt.Println(fmt.Sprint(audit.Winners) == fmt.Sprint(result.Winners))
+	//Output:
+	// true
+	// true
+}
+
+func TestSeed_Commitment(t *testing.T) {
+	var seed Seed
+	const want = "66687aadf862bd776c8fc18b8e9f8e20089714856ee233b3902a591d0d5f2925"
+	if got := seed.Commitment().String(); got != want {
+		t.Errorf("Commitment() = %s, want %s", got, want)
+	}
+}
+
+func TestDrawSeeded(t *testing.T) {
+	entrants := []weightedrand.Choice[string, uint64]{
+		weightedrand.NewChoice("a", uint64(1)),
+		weightedrand.NewChoice("never", uint64(0)),
+		weightedrand.NewChoice("b", uint64(2)),
+		weightedrand.NewChoice("c", uint64(3)),
+	}
+	var seed Seed
+	copy(seed[:], "weightedrand")
+
+	r, err := DrawSeeded(seed, 3, entrants)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Draws must never change, or past results could not be audited.
+	if got, want := fmt.Sprint(r.Winners, r.Indices), "[b a c] [2 0 3]"; got != want {
+		t.Errorf("DrawSeeded() = %s, want %s", got, want)
+	}
+	if r.Commitment != seed.Commitment() {
+		t.Error("Result.Commitment does not match Seed")
+	}
+
+	tests := []struct {
+		name     string
+		winners  int
+		entrants []weightedrand.Choice[string, uint64]
+		wantErr  error
+	}{
+		{name: "no winners", winners: 0, entrants: entrants},
+		{name: "too many winners", winners: 4, entrants: entrants, wantErr: ErrWinners},
+		{name: "negative winners", winners: -1, entrants: entrants, wantErr: ErrWinners},
+		{name: "overflow", winners: 1, entrants: []weightedrand.Choice[string, uint64]{
+			weightedrand.NewChoice("a", uint64(math.MaxUint64)),
+			weightedrand.NewChoice("b", uint64(1)),
+		}, wantErr: ErrWeightOverflow},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DrawSeeded(seed, tt.winners, tt.entrants); err != tt.wantErr {
+				t.Errorf("DrawSeeded() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDraw_distribution(t *testing.T) {
+	entrants := []weightedrand.Choice[int, int]{
+		weightedrand.NewChoice(0, 1),
+		weightedrand.NewChoice(1, 3),
+		weightedrand.NewChoice(2, 6),
+	}
+	const n = 20000
+	first := make([]int, len(entrants))
+	for i := 0; i < n; i++ {
+		winners, err := Draw(2, entrants)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if winners[0] == winners[1] {
+			t.Fatalf("drew %d twice", winners[0])
+		}
+		first[winners[0]]++
+	}
+	for i, e := range entrants {
+		want := float64(e.Weight) / 10
+		if got := float64(first[i]) / n; math.Abs(got-want) > 0.015 {
+			t.Errorf("entrant %d drawn first with frequency %.3f, want %.3f", i, got, want)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+// Package markov implements first-order Markov chains whose transitions are
+// picked by weighted random selection.
+package markov
+
+import (
+	"github.com/mroth/weightedrand/v2"
+	"github.com/mroth/weightedrand/v2/internal/constraints"
+)
+
+// A Chain is a table of weighted transitions from each state to the next. It
+// is immutable and safe for concurrent usage.
+type Chain[T comparable] struct {
+	next map[T]weightedrand.Picker[T]
+}
+
+// NewChain initializes a Chain from the weighted transitions out of each state.
+// States whose transitions have no weight >= 1 are terminal, as are states
+// which only appear as the target of a transition.
+func NewChain[T comparable, W constraints.Integer](transitions map[T][]weightedrand.Choice[T, W]) (*Chain[T], error) {
+	c := &Chain[T]{next: make(map[T]weightedrand.Picker[T], len(transitions))}
+	for state, choices := range transitions {
+		var pickable bool
+		for _, choice := range choices {
+			if choice.Weight >= 1 {
+				pickable = true
+				break
+			}
+		}
+		if !pickable {
+			continue
+		}
+		// Copy, since the Chooser takes ownership of the slice.
+		chooser, err := weightedrand.NewChooser(append([]weightedrand.Choice[T, W](nil), choices...)...)
+		if err != nil {
+			return nil, err
+		}
+		c.next[state] = chooser
+	}
+	return c, nil
+}
+
+// FromSequences builds a Chain from observed sequences of states, in which
+// each transition is weighted by the number of times it was observed.
+func FromSequences[T comparable](sequences ...[]T) *Chain[T] {
+	counts := make(map[T]map[T]int)
+	for _, seq := range sequences {
+		for i := 1; i < len(seq); i++ {
+			from, to := seq[i-1], seq[i]
+			if counts[from] == nil {
+				counts[from] = make(map[T]int)
+			}
+			counts[from][to]++
+		}
+	}
+
+	c := &Chain[T]{next: make(map[T]weightedrand.Picker[T], len(counts))}
+	for from, tos := range counts {
+		// Counts are positive and bounded by the length of the input, so
+		// construction cannot fail.
+		c.next[from], _ = weightedrand.NewChooserFromCounts(tos)
+	}
+	return c
+}
+
+// Next returns a weighted random successor of state, or false if state is
+// terminal or unknown.
+func (c *Chain[T]) Next(state T) (T, bool) {
+	p, ok := c.next[state]
+	if !ok {
+		var zero T
+		return zero, false
+	}
+	return p.Pick(), true
+}
+
+// Generate returns a sequence of up to n states following start, stopping
+// early if a terminal state is reached.
+func (c *Chain[T]) Generate(start T, n int) []T {
+	seq := make([]T, 0, n)
+	state := start
+	for len(seq) < n {
+		next, ok := c.Next(state)
+		if !ok {
+			break
+		}
+		seq = append(seq, next)
+		state = next
+	}
+	return seq
+}
@@ -0,0 +1,68 @@
+package markov
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/mroth/weightedrand/v2"
+)
+
+func ExampleFromSequences() {
+	corpus := "the cat sat on the mat"
+	chain := FromSequences(strings.Fields(corpus))
+	fmt.Println(chain.Generate("cat", 3))
+	//Output: [sat on the]
+}
+
+func TestNewChain(t *testing.T) {
+	chain, err := NewChain(map[string][]weightedrand.Choice[string, int]{
+		"sunny":  {weightedrand.NewChoice("sunny", 3), weightedrand.NewChoice("rainy", 1)},
+		"rainy":  {weightedrand.NewChoice("sunny", 1), weightedrand.NewChoice("rainy", 1)},
+		"stuck":  {weightedrand.NewChoice("sunny", 0)},
+		"broken": {},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, state := range []string{"stuck", "broken", "unknown"} {
+		if next, ok := chain.Next(state); ok {
+			t.Errorf("Next(%q) = %q, true, want terminal", state, next)
+		}
+	}
+
+	// the stationary distribution of this chain is 2/3 sunny
+	const n = 30000
+	sunny := 0
+	for _, s := range chain.Generate("sunny", n) {
+		if s == "sunny" {
+			sunny++
+		}
+	}
+	if got := float64(sunny) / n; math.Abs(got-2.0/3) > 0.02 {
+		t.Errorf("fraction of sunny states = %.3f, want 0.667", got)
+	}
+}
+
+func TestFromSequences(t *testing.T) {
+	chain := FromSequences([]int{1, 2, 1, 3}, []int{1, 2}, nil, []int{4})
+	counts := make(map[int]int)
+	for i := 0; i < 3000; i++ {
+		next, ok := chain.Next(1)
+		if !ok {
+			t.Fatal("Next(1) reported terminal state")
+		}
+		counts[next]++
+	}
+	// 1 was followed by 2 twice and 3 once
+	if counts[2] < 1800 || counts[3] < 800 || len(counts) != 2 {
+		t.Errorf("unexpected transitions from 1: %v", counts)
+	}
+	if got := chain.Generate(3, 5); len(got) != 0 {
+		t.Errorf("Generate() from terminal state = %v, want empty", got)
+	}
+	if got := chain.Generate(2, 2); fmt.Sprint(got[:1]) != "[1]" {
+		t.Errorf("Generate(2, 2) = %v, want to start with 1", got)
+	}
+}
@@ -0,0 +1,51 @@
+package weightedrand
+
+import (
+	"math/rand"
+
+	"github.com/mroth/weightedrand/v2/distributions"
+)
+
+// CountsN returns how many of n independent picks from the Chooser would
+// select each choice, indexed in the order of Choices, without making the
+// picks. The counts are a single multinomial draw, computed by a binomial draw
+// for each choice conditioned on the counts of those before it, so the time
+// taken depends on the number of choices rather than on n.
+//
+// Observers registered WithOnPick are not notified. Safe for concurrent usage.
+func (c Chooser[T, W]) CountsN(n uint64) []uint64 {
+	counts := make([]uint64, len(c.data))
+	// Draw the heaviest choices first, since they most quickly exhaust n. The
+	// probability of each choice, given that none of the heavier choices were
+	// selected, is its weight over the cumulative total of the lighter ones.
+	for i := len(c.totals) - 1; i >= 0 && n > 0; i-- {
+		rest := c.totals[i]
+		w := rest
+		if i > 0 {
+			w -= c.totals[i-1]
+		}
+		if w == 0 {
+			continue
+		}
+		var k uint64
+		if w == rest {
+			k = n
+		} else {
+			k = distributions.BinomialFunc(n, float64(w)/float64(rest), c.float64)
+		}
+		counts[i] = k
+		n -= k
+	}
+	return counts
+}
+
+// float64 returns a random number in the half-open interval [0,1) from the
+// Chooser's source of randomness, built from two draws narrow enough for ints
+// on 32-bit platforms.
+func (c Chooser[T, W]) float64() float64 {
+	if c.rng == nil {
+		return rand.Float64()
+	}
+	hi, lo := c.rng.Intn(1<<26), c.rng.Intn(1<<27)
+	return float64(hi<<27|lo) / (1 << 53)
+}
@@ -0,0 +1,65 @@
+package weightedrand
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestChooser_CountsN(t *testing.T) {
+	choices := mockFrequencyChoices(t, testChoices)
+	chooser, err := NewChooserWithOptions(choices, WithSource(rand.NewSource(1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, n := range []uint64{0, 1, 100, testIterations, 1 << 50} {
+		counts := chooser.CountsN(n)
+		if len(counts) != len(choices) {
+			t.Fatalf("len(CountsN()) = %d, want %d", len(counts), len(choices))
+		}
+		var sum uint64
+		for _, k := range counts {
+			sum += k
+		}
+		if sum != n {
+			t.Errorf("CountsN(%d) sums to %d", n, sum)
+		}
+	}
+
+	counts := make(map[int]int)
+	for i, k := range chooser.CountsN(testIterations) {
+		counts[chooser.data[i].Item] = int(k)
+	}
+	verifyFrequencyCounts(t, counts, chooser.Choices())
+
+	// Over many draws, the mean count of each choice is n·w/W.
+	const n, draws = 1 << 40, 1000
+	means := make([]float64, len(choices))
+	for d := 0; d < draws; d++ {
+		for i, k := range chooser.CountsN(n) {
+			means[i] += float64(k) / draws
+		}
+	}
+	for i, c := range chooser.Choices() {
+		want := float64(n) * float64(c.Weight) / float64(chooser.max)
+		if math.Abs(means[i]-want) > 1e-4*n {
+			t.Errorf("mean count of %d = %v, want %v", c.Item, means[i], want)
+		}
+	}
+}
+
+func BenchmarkChooser_CountsN(b *testing.B) {
+	for n := BMMinChoices; n <= BMMaxChoices/10; n *= 10 {
+		b.Run(fmt.Sprintf("size=%s", fmt1eN(n)), func(b *testing.B) {
+			chooser, err := NewChooser(mockChoices(n)...)
+			if err != nil {
+				b.Fatal(err)
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_ = chooser.CountsN(1e9)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package weightedrand
+
+import (
+	"errors"
+	"math/rand"
+)
+
+// A NarrowChooser is a Chooser whose cumulative weight table is stored as
+// uint32 rather than int values, halving its memory footprint on 64-bit
+// platforms. For tables of millions of choices this means both considerably
+// less memory and a larger fraction of the table fitting in CPU caches, at the
+// cost of limiting the sum of all weights to math.MaxUint32.
+type NarrowChooser[T any, W integer] struct {
+	data   []Choice[T, W]
+	totals []uint32
+	max    uint32
+}
+
+// NewNarrowChooser initializes a new NarrowChooser for picking from the
+// provided choices. It returns an error if the sum of the weights exceeds
+// math.MaxUint32. As with NewChooser, the NarrowChooser takes ownership of the
+// choices slice.
+func NewNarrowChooser[T any, W integer](choices ...Choice[T, W]) (*NarrowChooser[T, W], error) {
+	sortChoices(choices)
+	totals := make([]uint32, len(choices))
+	var runningTotal uint64
+	for i, c := range choices {
+		if c.Weight < 0 {
+			continue // ignore negative weights, can never be picked
+		}
+		if uint64(c.Weight) > maxUint32 {
+			return nil, errNarrowOverflow
+		}
+		runningTotal += uint64(c.Weight)
+		if runningTotal > maxUint32 {
+			return nil, errNarrowOverflow
+		}
+		totals[i] = uint32(runningTotal)
+	}
+	if runningTotal < 1 {
+		return nil, errNoValidChoices
+	}
+	return &NarrowChooser[T, W]{data: choices, totals: totals, max: uint32(runningTotal)}, nil
+}
+
+const maxUint32 = 1<<32 - 1
+
+// errNarrowOverflow is returned by NewNarrowChooser when the sum of weights
+// cannot be represented in its uint32 totals.
+var errNarrowOverflow = errors.New("sum of Choice Weights exceeds max uint32")
+
+// Pick returns a single weighted random Choice.Item from the NarrowChooser.
+//
+// Utilizes global rand as the source of randomness. Safe for concurrent usage.
+func (c NarrowChooser[T, W]) Pick() T {
+	r := uint32(rand.Int63n(int64(c.max))) + 1
+	return c.data[searchUint32s(c.totals, r)].Item
+}
+
+// Choices returns a copy of the Choices the NarrowChooser picks from, in
+// ascending order of weight, including any which can never be picked.
+func (c NarrowChooser[T, W]) Choices() []Choice[T, W] {
+	return append([]Choice[T, W](nil), c.data...)
+}
+
+// searchUint32s is search for uint32 totals, returning the index of the first
+// total >= x, using the same size threshold for the branchless variant.
+func searchUint32s(a []uint32, x uint32) int {
+	n := len(a)
+	if n > branchlessMaxLen {
+		i, j := 0, n
+		for i < j {
+			h := int(uint(i+j) >> 1)
+			if a[h] < x {
+				i = h + 1
+			} else {
+				j = h
+			}
+		}
+		return i
+	}
+	if n == 0 {
+		return 0
+	}
+	base := 0
+	for n > 1 {
+		half := n >> 1
+		base += half & lessMask64(a[base+half-1], x)
+		n -= half
+	}
+	return base + 1&lessMask64(a[base], x)
+}
+
+// lessMask64 returns all one bits if a < b, otherwise zero. The difference of
+// two uint32 values cannot overflow an int64.
+func lessMask64(a, b uint32) int {
+	return int((int64(a) - int64(b)) >> 63)
+}
@@ -0,0 +1,107 @@
+package weightedrand
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestNewNarrowChooser(t *testing.T) {
+	tests := []struct {
+		name    string
+		cs      []Choice[rune, uint64]
+		wantErr error
+	}{
+		{name: "zero choices", wantErr: errNoValidChoices},
+		{name: "zero weights", cs: []Choice[rune, uint64]{{'a', 0}}, wantErr: errNoValidChoices},
+		{name: "max total", cs: []Choice[rune, uint64]{{'a', math.MaxUint32 - 1}, {'b', 1}}},
+		{name: "total overflow", cs: []Choice[rune, uint64]{{'a', math.MaxUint32}, {'b', 1}}, wantErr: errNarrowOverflow},
+		{name: "single weight overflow", cs: []Choice[rune, uint64]{{'a', math.MaxUint64}, {'b', 2}}, wantErr: errNarrowOverflow},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewNarrowChooser(tt.cs...)
+			if err != tt.wantErr {
+				t.Errorf("NewNarrowChooser() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// A NarrowChooser must select the same choice as a Chooser for every possible
+// random value.
+func TestNarrowChooser_equivalence(t *testing.T) {
+	choices := []Choice[int, int]{{0, 3}, {1, -2}, {2, 0}, {3, 7}, {4, 1}, {5, 4}}
+	wide, err := NewChooser(append([]Choice[int, int](nil), choices...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	narrow, err := NewNarrowChooser(choices...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for r := 1; r <= wide.max; r++ {
+		if got, want := searchUint32s(narrow.totals, uint32(r)), search(wide.totals, r); got != want {
+			t.Errorf("search(%d) = %d, want %d", r, got, want)
+		}
+	}
+}
+
+func TestSearchUint32s(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 3, 17, 64, branchlessMaxLen + 1} {
+		a := make([]uint32, n)
+		for i := range a {
+			a[i] = rand.Uint32()
+		}
+		sort.Slice(a, func(i, j int) bool { return a[i] < a[j] })
+		for k := 0; k < 100; k++ {
+			x := rand.Uint32()
+			want := sort.Search(n, func(i int) bool { return a[i] >= x })
+			if got := searchUint32s(a, x); got != want {
+				t.Fatalf("n=%d: searchUint32s(%d) = %d, want %d", n, x, got, want)
+			}
+		}
+	}
+}
+
+func TestNarrowChooser_Pick(t *testing.T) {
+	const testChoices = 10
+	const testIterations = 100000
+	choices := make([]Choice[int, int], 0, testChoices)
+	for i := 0; i < testChoices; i++ {
+		choices = append(choices, NewChoice(i, i))
+	}
+	chooser, err := NewNarrowChooser(choices...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	counts := make(map[int]int)
+	for i := 0; i < testIterations; i++ {
+		counts[chooser.Pick()]++
+	}
+	if counts[0] != 0 {
+		t.Error("picked a zero weight choice")
+	}
+	for i := 2; i < testChoices; i++ {
+		if counts[i] < counts[i-1]/2 {
+			t.Errorf("choice %d picked %d times, far less than choice %d at %d", i, counts[i], i-1, counts[i-1])
+		}
+	}
+}
+
+func BenchmarkNarrowChooser_Pick(b *testing.B) {
+	for n := BMMinChoices; n <= BMMaxChoices; n *= 10 {
+		b.Run(fmt.Sprintf("size=%s", fmt1eN(n)), func(b *testing.B) {
+			chooser, err := NewNarrowChooser(mockChoices(n)...)
+			if err != nil {
+				b.Fatal(err)
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_ = chooser.Pick()
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package weightedrand
+
+import (
+	"errors"
+	"math/rand"
+	"runtime"
+)
+
+// An Option configures optional behavior of a Chooser created with
+// NewChooserWithOptions.
+type Option func(*config)
+
+type config struct {
+	privateRand bool
+	source      rand.Source
+	rng         RNG
+	strict      bool
+	onPick      interface{} // func(T, W), checked at construction
+	workers     int
+	eytzinger   bool
+}
+
+// WithPrivateRand gives the Chooser its own sources of randomness, seeded at
+// construction, rather than sharing the global math/rand source with the rest
+// of the program. Sources are sharded by goroutine across at least twice
+// GOMAXPROCS cache line padded slots, so Pick remains safe for concurrent usage
+// without lock contention.
+//
+// Since go1.20 the global source is already free of contention unless it has
+// been manually seeded, so this is chiefly useful for programs that call
+// rand.Seed or are built with older toolchains.
+func WithPrivateRand() Option {
+	return func(cfg *config) { cfg.privateRand = true }
+}
+
+// WithSource makes the Chooser draw all randomness from src, for example to
+// produce a reproducible sequence of picks from a seeded source in tests and
+// simulations. Access to src is serialized, so Pick remains safe for concurrent
+// usage, but concurrent callers contend for it. It takes precedence over
+// WithPrivateRand.
+func WithSource(src rand.Source) Option {
+	return func(cfg *config) { cfg.source = src }
+}
+
+// WithRand makes the Chooser draw all randomness from r, which may be any RNG
+// such as a *rand.Rand from math/rand/v2 with a PCG or ChaCha8 source. Access
+// to r is serialized, so r need not be safe for concurrent usage. It takes
+// precedence over WithSource and WithPrivateRand.
+func WithRand(r RNG) Option {
+	return func(cfg *config) { cfg.rng = r }
+}
+
+// WithStrictWeights makes NewChooserWithOptions return an error if any choice
+// has a negative weight, rather than ignoring it as NewChooser does.
+func WithStrictWeights() Option {
+	return func(cfg *config) { cfg.strict = true }
+}
+
+// WithOnPick registers fn to be called synchronously with the item and weight of
+// every selection made by the Chooser, e.g. for metrics or logging. It must be
+// safe for concurrent usage if the Chooser is used concurrently.
+//
+// The types of fn must match those of the Chooser, otherwise
+// NewChooserWithOptions returns an error.
+func WithOnPick[T any, W integer](fn func(item T, weight W)) Option {
+	return func(cfg *config) { cfg.onPick = fn }
+}
+
+// WithParallelBuild splits construction of large Choosers across up to
+// GOMAXPROCS goroutines, sorting blocks of choices concurrently before merging
+// them and computing the cumulative weight table as a blocked prefix sum. This
+// lets startup time for tables of many millions of choices scale with the
+// available cores, at the cost of a temporary copy of the choices while
+// sorting them. It has no effect on small tables, which are always built
+// sequentially.
+func WithParallelBuild() Option {
+	return func(cfg *config) { cfg.workers = runtime.GOMAXPROCS(0) }
+}
+
+// errOptionType is returned by NewChooserWithOptions when an Option which is
+// generic over the Choice types was instantiated with different types than the
+// Chooser being constructed.
+var errOptionType = errors.New("Option types do not match Chooser types")
+
+// errNegativeWeight is returned by NewChooserWithOptions WithStrictWeights if
+// any choice has a negative weight.
+var errNegativeWeight = errors.New("Choice with negative Weight")
+
+// NewChooserWithOptions initializes a new Chooser for picking from the
+// provided choices, configured by opts. It is otherwise identical to NewChooser,
+// whose variadic choices leave no room for options.
+func NewChooserWithOptions[T any, W integer](choices []Choice[T, W], opts ...Option) (*Chooser[T, W], error) {
+	var cfg config
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	var onPick func(T, W)
+	if cfg.onPick != nil {
+		fn, ok := cfg.onPick.(func(T, W))
+		if !ok {
+			return nil, errOptionType
+		}
+		onPick = fn
+	}
+
+	if cfg.strict {
+		for _, c := range choices {
+			if c.Weight < 0 {
+				return nil, errNegativeWeight
+			}
+		}
+	}
+
+	c, err := newChooser(choices, cfg.workers)
+	if err != nil {
+		return nil, err
+	}
+	c.onPick = onPick
+	if cfg.eytzinger {
+		c.eytz = &eytzinger{}
+		c.eytz.build(c.totals)
+	}
+	switch {
+	case cfg.rng != nil:
+		c.rng = &lockedRNG{r: cfg.rng}
+	case cfg.source != nil:
+		c.rng = &lockedRand{r: rand.New(cfg.source)}
+	case cfg.privateRand:
+		c.rng = newShardedRand(randomSeed())
+	}
+	return c, nil
+}
@@ -0,0 +1,151 @@
+package weightedrand
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"testing"
+)
+
+// TestWithPrivateRand uses the same methodology as TestChooser_PickSource,
+// sharing a single Chooser with a private source across goroutines.
+func TestWithPrivateRand(t *testing.T) {
+	choices := mockFrequencyChoices(t, testChoices)
+	chooser, err := NewChooserWithOptions(choices, WithPrivateRand())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if chooser.rng == nil {
+		t.Fatal("expected chooser to have a private source of randomness")
+	}
+
+	counts1 := make(map[int]int)
+	counts2 := make(map[int]int)
+	var wg sync.WaitGroup
+	wg.Add(2)
+	checker := func(counts map[int]int) {
+		defer wg.Done()
+		for i := 0; i < testIterations/2; i++ {
+			c := chooser.Pick()
+			counts[c]++
+		}
+	}
+	go checker(counts1)
+	go checker(counts2)
+	wg.Wait()
+
+	verifyFrequencyCounts(t, counts1, choices)
+	verifyFrequencyCounts(t, counts2, choices)
+}
+
+func TestNewChooserWithOptions(t *testing.T) {
+	_, err := NewChooserWithOptions([]Choice[rune, int]{}, WithPrivateRand())
+	if err != errNoValidChoices {
+		t.Errorf("NewChooserWithOptions() error = %v, wantErr %v", err, errNoValidChoices)
+	}
+}
+
+func TestWithSource(t *testing.T) {
+	choices := mockChoices(100)
+	picks := func() []rune {
+		c, err := NewChooserWithOptions(choices, WithSource(rand.NewSource(42)), WithPrivateRand())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := c.rng.(*lockedRand); !ok {
+			t.Fatalf("rng = %T, want *lockedRand", c.rng)
+		}
+		got := make([]rune, 100)
+		for i := range got {
+			got[i] = c.Pick()
+		}
+		return got
+	}
+	if a, b := picks(), picks(); string(a) != string(b) {
+		t.Error("same seeded source produced different picks")
+	}
+}
+
+// fixedRNG is an RNG always returning the same fraction of its range, for
+// deterministic picks.
+type fixedRNG float64
+
+func (f fixedRNG) Uint64N(n uint64) uint64 { return uint64(float64(f) * float64(n-1)) }
+
+func TestWithRand(t *testing.T) {
+	choices := []Choice[rune, int]{{'a', 1}, {'b', 0}, {'c', 5}, {'d', 2}}
+	for _, tt := range []struct {
+		rng  fixedRNG
+		want rune
+	}{
+		{rng: 0, want: 'a'},
+		{rng: 1, want: 'c'},
+	} {
+		c, err := NewChooserWithOptions(append([]Choice[rune, int](nil), choices...),
+			WithSource(rand.NewSource(1)), WithRand(tt.rng))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := c.rng.(*lockedRNG); !ok {
+			t.Fatalf("rng = %T, want *lockedRNG", c.rng)
+		}
+		if got := c.Pick(); got != tt.want {
+			t.Errorf("Pick() with RNG %v = %q, want %q", tt.rng, got, tt.want)
+		}
+	}
+}
+
+func TestWithStrictWeights(t *testing.T) {
+	choices := []Choice[rune, int]{NewChoice('a', 1), NewChoice('b', -1)}
+	if _, err := NewChooserWithOptions(choices, WithStrictWeights()); err != errNegativeWeight {
+		t.Errorf("NewChooserWithOptions() error = %v, wantErr %v", err, errNegativeWeight)
+	}
+	if _, err := NewChooserWithOptions(choices); err != nil {
+		t.Errorf("NewChooserWithOptions() without strict weights error = %v", err)
+	}
+}
+
+func ExampleWithOnPick() {
+	counts := make(map[string]int)
+	chooser, _ := NewChooserWithOptions(
+		[]Choice[string, int]{NewChoice("a", 0), NewChoice("b", 1)},
+		WithOnPick(func(item string, weight int) { counts[item]++ }),
+	)
+	for i := 0; i < 3; i++ {
+		chooser.Pick()
+	}
+	fmt.Println(counts)
+	//Output: map[b:3]
+}
+
+func TestWithOnPick(t *testing.T) {
+	var gotItem rune
+	var gotWeight int
+	chooser, err := NewChooserWithOptions(
+		[]Choice[rune, int]{NewChoice('a', 7)},
+		WithOnPick(func(item rune, weight int) { gotItem, gotWeight = item, weight }),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, pick := range map[string]func() rune{
+		"Pick":       chooser.Pick,
+		"TryPick":    func() rune { r, _ := chooser.TryPick(); return r },
+		"PickSource": func() rune { return chooser.PickSource(rand.New(rand.NewSource(1))) },
+	} {
+		gotItem, gotWeight = 0, 0
+		item := pick()
+		if gotItem != item || gotWeight != 7 {
+			t.Errorf("%s: hook called with (%q, %d), want (%q, 7)", name, gotItem, gotWeight, item)
+		}
+	}
+
+	_, err = NewChooserWithOptions(
+		[]Choice[rune, int]{NewChoice('a', 7)},
+		WithOnPick(func(item string, weight int) {}),
+	)
+	if err != errOptionType {
+		t.Errorf("NewChooserWithOptions() error = %v, wantErr %v", err, errOptionType)
+	}
+}
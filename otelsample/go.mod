@@ -0,0 +1,25 @@
+module github.com/mroth/weightedrand/v2/otelsample
+
+go 1.25.0
+
+require (
+	github.com/mroth/weightedrand/v2 v2.2.0
+	go.opentelemetry.io/otel v1.46.0
+	go.opentelemetry.io/otel/sdk v1.46.0
+	go.opentelemetry.io/otel/trace v1.46.0
+)
+
+require (
+	github.com/cespare/xxhash/v2 v2.3.0 // indirect
+	github.com/go-logr/logr v1.4.4 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
+	github.com/google/uuid v1.6.0 // indirect
+	go.opentelemetry.io/auto/sdk v1.2.1 // indirect
+	go.opentelemetry.io/otel/metric v1.46.0 // indirect
+	golang.org/x/sys v0.47.0 // indirect
+)
+
+// Builds within this repository use the parent module as checked out. The
+// replace directive is ignored for users of this module, who get the version
+// required above, the first to provide all the APIs used here.
+replace github.com/mroth/weightedrand/v2 => ../
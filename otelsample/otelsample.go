@@ -0,0 +1,91 @@
+// Package otelsample provides an OpenTelemetry trace sampler whose sampling
+// decisions are weighted by the value of a span attribute, such as a route or
+// tenant, so that some traffic can be sampled more heavily than the rest:
+//
+//	sampler, err := otelsample.New("http.route", otelsample.Rate{Sample: 1, Drop: 99},
+//		map[string]otelsample.Rate{"/checkout": {Sample: 10, Drop: 90}},
+//	)
+//	provider := sdktrace.NewTracerProvider(sdktrace.WithSampler(sdktrace.ParentBased(sampler)))
+//
+// This package is a separate module, so that the core weightedrand module
+// remains free of dependencies.
+package otelsample
+
+import (
+	"fmt"
+
+	"github.com/mroth/weightedrand/v2"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// A Rate is the relative weight of sampling versus dropping a span. For
+// example, a Rate of {Sample: 1, Drop: 99} samples 1% of spans.
+type Rate struct {
+	Sample uint32
+	Drop   uint32
+}
+
+// A Sampler is an sdktrace.Sampler picking whether to sample each span by the
+// Rate for the value of a span attribute. It considers only the attributes
+// provided when the span is started, and should usually be wrapped with
+// sdktrace.ParentBased so that sampling is consistent across a trace.
+type Sampler struct {
+	key      attribute.Key
+	fallback *weightedrand.Chooser[sdktrace.SamplingDecision, uint32]
+	rates    map[string]*weightedrand.Chooser[sdktrace.SamplingDecision, uint32]
+	desc     string
+}
+
+var _ sdktrace.Sampler = (*Sampler)(nil)
+
+// New initializes a Sampler applying the rate for the value of the attribute
+// key of each span, as formatted by attribute.Value.Emit, or the fallback rate
+// if the span has no such attribute or its value has no rate.
+func New(key attribute.Key, fallback Rate, rates map[string]Rate) (*Sampler, error) {
+	s := &Sampler{
+		key:   key,
+		rates: make(map[string]*weightedrand.Chooser[sdktrace.SamplingDecision, uint32], len(rates)),
+		desc:  fmt.Sprintf("WeightedSampler{key=%s,rates=%d}", key, len(rates)),
+	}
+	var err error
+	if s.fallback, err = newChooser(fallback); err != nil {
+		return nil, fmt.Errorf("otelsample: fallback rate: %w", err)
+	}
+	for v, r := range rates {
+		if s.rates[v], err = newChooser(r); err != nil {
+			return nil, fmt.Errorf("otelsample: rate for %q: %w", v, err)
+		}
+	}
+	return s, nil
+}
+
+func newChooser(r Rate) (*weightedrand.Chooser[sdktrace.SamplingDecision, uint32], error) {
+	return weightedrand.NewChooser(
+		weightedrand.NewChoice(sdktrace.RecordAndSample, r.Sample),
+		weightedrand.NewChoice(sdktrace.Drop, r.Drop),
+	)
+}
+
+// ShouldSample implements sdktrace.Sampler.
+func (s *Sampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	chooser := s.fallback
+	for _, kv := range p.Attributes {
+		if kv.Key == s.key {
+			if c, ok := s.rates[kv.Value.Emit()]; ok {
+				chooser = c
+			}
+			break
+		}
+	}
+	return sdktrace.SamplingResult{
+		Decision:   chooser.Pick(),
+		Tracestate: trace.SpanContextFromContext(p.ParentContext).TraceState(),
+	}
+}
+
+// Description implements sdktrace.Sampler.
+func (s *Sampler) Description() string {
+	return s.desc
+}
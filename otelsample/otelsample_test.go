@@ -0,0 +1,82 @@
+package otelsample
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestNew(t *testing.T) {
+	if _, err := New("route", Rate{}, nil); err == nil {
+		t.Error("New() with zero fallback rate error = nil")
+	}
+	if _, err := New("route", Rate{Sample: 1}, map[string]Rate{"/": {}}); err == nil {
+		t.Error("New() with zero rate error = nil")
+	}
+}
+
+func TestSampler(t *testing.T) {
+	s, err := New("route", Rate{Sample: 1, Drop: 9}, map[string]Rate{
+		"/checkout": {Sample: 1},
+		"/health":   {Drop: 1},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name  string
+		attrs []attribute.KeyValue
+		want  float64
+	}{
+		{name: "checkout", attrs: []attribute.KeyValue{attribute.String("route", "/checkout")}, want: 1},
+		{name: "health", attrs: []attribute.KeyValue{attribute.String("route", "/health")}, want: 0},
+		{name: "unknown route", attrs: []attribute.KeyValue{attribute.String("route", "/")}, want: 0.1},
+		{name: "no route", attrs: []attribute.KeyValue{attribute.String("other", "/checkout")}, want: 0.1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			const n = 10000
+			var sampled int
+			for i := 0; i < n; i++ {
+				res := s.ShouldSample(sdktrace.SamplingParameters{
+					ParentContext: context.Background(),
+					Name:          "span",
+					Attributes:    tt.attrs,
+				})
+				if res.Decision == sdktrace.RecordAndSample {
+					sampled++
+				}
+			}
+			if got := float64(sampled) / n; math.Abs(got-tt.want) > 0.02 {
+				t.Errorf("sampled fraction = %.3f, want %.2f", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSampler_provider(t *testing.T) {
+	s, err := New("tenant", Rate{Drop: 1}, map[string]Rate{"acme": {Sample: 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithSampler(sdktrace.ParentBased(s)),
+		sdktrace.WithSyncer(exporter),
+	)
+	tracer := provider.Tracer("test")
+	for _, tenant := range []string{"acme", "other"} {
+		_, span := tracer.Start(context.Background(), tenant,
+			trace.WithAttributes(attribute.String("tenant", tenant)))
+		span.End()
+	}
+	spans := exporter.GetSpans()
+	if len(spans) != 1 || spans[0].Name != "acme" {
+		t.Errorf("exported spans = %v, want only acme", spans)
+	}
+}
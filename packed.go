@@ -0,0 +1,117 @@
+package weightedrand
+
+import (
+	"math/bits"
+	"math/rand"
+)
+
+// packedBlockLen is the number of choices between the anchors of a
+// PackedChooser, trading the length of the scan within a block for the memory
+// used by the anchors.
+const packedBlockLen = 64
+
+// A PackedChooser is a Chooser whose cumulative weight table is compressed, for
+// holding tables of millions of small weights in a fraction of the memory.
+//
+// Since choices are sorted, the difference between consecutive cumulative
+// totals is just the weight of each choice, so rather than the totals it stores
+// the weights themselves bit-packed at the width of the largest weight, along
+// with the absolute running total at the start of every block of
+// packedBlockLen choices. A pick binary searches the anchors, then scans the
+// weights of a single block. For example, a table of 10 million weights below
+// 16 takes around 6MB rather than the 80MB of a Chooser's totals on 64-bit
+// platforms, at the cost of a somewhat slower Pick.
+type PackedChooser[T any, W integer] struct {
+	data    []Choice[T, W]
+	anchors []int    // running total before the start of each block
+	packed  []uint64 // weights of width bits each, negative weights as zero
+	width   uint
+	max     int
+}
+
+// NewPackedChooser initializes a new PackedChooser for picking from the
+// provided choices. As with NewChooser, the PackedChooser takes ownership of
+// the choices slice.
+func NewPackedChooser[T any, W integer](choices ...Choice[T, W]) (*PackedChooser[T, W], error) {
+	sortChoices(choices)
+
+	// Validate the total before allocating anything. Since the choices are
+	// sorted, the largest weight (and thus the width) is the last.
+	runningTotal, err := sumWeights(choices)
+	if err != nil {
+		return nil, err
+	}
+	if runningTotal < 1 {
+		return nil, errNoValidChoices
+	}
+
+	width := uint(bits.Len64(uint64(choices[len(choices)-1].Weight)))
+	c := &PackedChooser[T, W]{
+		data:    choices,
+		anchors: make([]int, (len(choices)+packedBlockLen-1)/packedBlockLen),
+		packed:  make([]uint64, (uint(len(choices))*width+63)/64),
+		width:   width,
+		max:     runningTotal,
+	}
+	runningTotal = 0
+	for i, choice := range choices {
+		if i%packedBlockLen == 0 {
+			c.anchors[i/packedBlockLen] = runningTotal
+		}
+		if choice.Weight > 0 {
+			c.set(i, uint64(choice.Weight))
+			runningTotal += int(choice.Weight)
+		}
+	}
+	return c, nil
+}
+
+// set stores the weight v of the choice at index i.
+func (c *PackedChooser[T, W]) set(i int, v uint64) {
+	off := uint(i) * c.width
+	w, s := off/64, off%64
+	c.packed[w] |= v << s
+	if s+c.width > 64 {
+		c.packed[w+1] |= v >> (64 - s)
+	}
+}
+
+// weight returns the weight of the choice at index i.
+func (c PackedChooser[T, W]) weight(i int) int {
+	off := uint(i) * c.width
+	w, s := off/64, off%64
+	v := c.packed[w] >> s
+	if s+c.width > 64 {
+		v |= c.packed[w+1] << (64 - s)
+	}
+	return int(v & (1<<c.width - 1))
+}
+
+// Pick returns a single weighted random Choice.Item from the PackedChooser.
+//
+// Utilizes global rand as the source of randomness. Safe for concurrent usage.
+func (c PackedChooser[T, W]) Pick() T {
+	return c.data[c.search(rand.Intn(c.max)+1)].Item
+}
+
+// search returns the index of the first choice whose cumulative total is >= x,
+// for x in [1, max].
+func (c PackedChooser[T, W]) search(x int) int {
+	// The last block whose running total before its start is < x, which must
+	// therefore contain the choice.
+	b := search(c.anchors, x) - 1
+	i, total := b*packedBlockLen, c.anchors[b]
+	for {
+		total += c.weight(i)
+		if total >= x {
+			return i
+		}
+		i++
+	}
+}
+
+// Choices returns a copy of the Choices the PackedChooser picks from, in
+// ascending order of weight, including any which can never be picked.
+func (c PackedChooser[T, W]) Choices() []Choice[T, W] {
+	return append([]Choice[T, W](nil), c.data...)
+}
@@ -0,0 +1,101 @@
+package weightedrand
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+func TestNewPackedChooser(t *testing.T) {
+	tests := []struct {
+		name    string
+		cs      []Choice[rune, int]
+		wantErr error
+	}{
+		{name: "zero choices", wantErr: errNoValidChoices},
+		{name: "zero and negative weights", cs: []Choice[rune, int]{{'a', 0}, {'b', -1}}, wantErr: errNoValidChoices},
+		{name: "weight overflow", cs: []Choice[rune, int]{{'a', maxInt / 2}, {'b', maxInt / 2}, {'c', 2}}, wantErr: errWeightOverflow},
+		{name: "max width", cs: []Choice[rune, int]{{'a', maxInt - 2}, {'b', 1}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewPackedChooser(tt.cs...)
+			if err != tt.wantErr {
+				t.Errorf("NewPackedChooser() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// A PackedChooser must select the same choice as a Chooser for every possible
+// random value, across weight widths that do and do not divide a word.
+func TestPackedChooser_equivalence(t *testing.T) {
+	for _, tt := range []struct{ n, maxW, negW int }{
+		{n: 1, maxW: 1},
+		{n: 3, maxW: 2},
+		{n: 200, maxW: 15, negW: 5},
+		{n: 1000, maxW: 100},
+		{n: 129, maxW: 1 << 20},
+	} {
+		t.Run(fmt.Sprintf("n=%d/maxW=%d", tt.n, tt.maxW), func(t *testing.T) {
+			rng := rand.New(rand.NewSource(int64(tt.n)))
+			choices := make([]Choice[int, int], tt.n)
+			for i := range choices {
+				choices[i] = NewChoice(i, rng.Intn(tt.maxW+tt.negW)+1-tt.negW)
+			}
+			choices[0].Weight = tt.maxW // ensure at least one valid choice
+			wide, err := NewChooser(append([]Choice[int, int](nil), choices...)...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			packed, err := NewPackedChooser(choices...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if packed.max != wide.max {
+				t.Fatalf("max = %d, want %d", packed.max, wide.max)
+			}
+			step := 1 + wide.max/10000
+			for r := 1; r <= wide.max; r += step {
+				if got, want := packed.search(r), search(wide.totals, r); got != want {
+					t.Fatalf("search(%d) = %d, want %d", r, got, want)
+				}
+			}
+			if got, want := packed.search(wide.max), search(wide.totals, wide.max); got != want {
+				t.Fatalf("search(max) = %d, want %d", got, want)
+			}
+		})
+	}
+}
+
+func TestPackedChooser_size(t *testing.T) {
+	choices := make([]Choice[int, int], 10*packedBlockLen)
+	for i := range choices {
+		choices[i] = NewChoice(i, i%16)
+	}
+	c, err := NewPackedChooser(choices...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.width != 4 {
+		t.Errorf("width = %d, want 4", c.width)
+	}
+	if len(c.packed) != len(choices)*4/64 || len(c.anchors) != 10 {
+		t.Errorf("len(packed), len(anchors) = %d, %d", len(c.packed), len(c.anchors))
+	}
+}
+
+func BenchmarkPackedChooser_Pick(b *testing.B) {
+	for n := BMMinChoices; n <= BMMaxChoices; n *= 10 {
+		b.Run(fmt.Sprintf("size=%s", fmt1eN(n)), func(b *testing.B) {
+			chooser, err := NewPackedChooser(mockChoices(n)...)
+			if err != nil {
+				b.Fatal(err)
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_ = chooser.Pick()
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package weightedrand
+
+import (
+	"sort"
+	"sync"
+)
+
+// parallelBuildMinLen is the smallest number of choices for which construction
+// is split across workers; below it the coordination costs more than it saves.
+const parallelBuildMinLen = 1 << 15
+
+// blockBounds splits [0,n) into at most workers contiguous blocks of near equal
+// size, returning the boundaries of each block in order.
+func blockBounds(n, workers int) []int {
+	if workers > n {
+		workers = n
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	bounds := make([]int, workers+1)
+	for b := range bounds {
+		bounds[b] = b * n / workers
+	}
+	return bounds
+}
+
+// parallelSortChoices sorts choices by ascending weight, like sortChoices, by
+// sorting one block per worker concurrently and then merging sorted blocks
+// pairwise, each round of merges also running concurrently. Merging requires
+// a scratch buffer the size of choices.
+func parallelSortChoices[T any, W integer](choices []Choice[T, W], workers int) {
+	if sort.SliceIsSorted(choices, func(i, j int) bool {
+		return choices[i].Weight < choices[j].Weight
+	}) {
+		return
+	}
+
+	bounds := blockBounds(len(choices), workers)
+	runs := len(bounds) - 1
+
+	var wg sync.WaitGroup
+	for b := 0; b < runs; b++ {
+		block := choices[bounds[b]:bounds[b+1]]
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sort.Slice(block, func(i, j int) bool {
+				return block[i].Weight < block[j].Weight
+			})
+		}()
+	}
+	wg.Wait()
+
+	src, dst := choices, make([]Choice[T, W], len(choices))
+	for width := 1; width < runs; width *= 2 {
+		for b := 0; b < runs; b += 2 * width {
+			lo := bounds[b]
+			mid := bounds[minInt(b+width, runs)]
+			hi := bounds[minInt(b+2*width, runs)]
+			out, left, right := dst[lo:hi], src[lo:mid], src[mid:hi]
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				mergeChoices(out, left, right)
+			}()
+		}
+		wg.Wait()
+		src, dst = dst, src
+	}
+	if &src[0] != &choices[0] {
+		copy(choices, src)
+	}
+}
+
+// mergeChoices merges the sorted slices a and b into out, which must have room
+// for both.
+func mergeChoices[T any, W integer](out, a, b []Choice[T, W]) {
+	i, j, k := 0, 0, 0
+	for i < len(a) && j < len(b) {
+		if b[j].Weight < a[i].Weight {
+			out[k] = b[j]
+			j++
+		} else {
+			out[k] = a[i]
+			i++
+		}
+		k++
+	}
+	k += copy(out[k:], a[i:])
+	copy(out[k:], b[j:])
+}
+
+// parallelFillTotals is equivalent to fillTotals, computed as a blocked prefix
+// sum: each worker first fills the running totals of its own block starting
+// from zero, then once the offset of every block is known from the sums of
+// those preceding it, adds that offset to each of its totals.
+func parallelFillTotals[T any, W integer](choices []Choice[T, W], totals []int, workers int) (int, error) {
+	bounds := blockBounds(len(choices), workers)
+	runs := len(bounds) - 1
+	sums := make([]int, runs)
+	errs := make([]error, runs)
+
+	var wg sync.WaitGroup
+	for b := 0; b < runs; b++ {
+		b := b
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			lo, hi := bounds[b], bounds[b+1]
+			sums[b], errs[b] = fillTotals(choices[lo:hi], totals[lo:hi])
+		}()
+	}
+	wg.Wait()
+
+	// Each block sum is already known to be below maxInt, so only the combined
+	// total remains to be checked, with the same bound as fillTotals.
+	offsets := make([]int, runs)
+	runningTotal := 0
+	for b, sum := range sums {
+		if errs[b] != nil {
+			return 0, errs[b]
+		}
+		if (maxInt - runningTotal) <= sum {
+			return 0, errWeightOverflow
+		}
+		offsets[b] = runningTotal
+		runningTotal += sum
+	}
+
+	for b := 1; b < runs; b++ {
+		if offsets[b] == 0 {
+			continue // only negative or zero weights precede this block
+		}
+		block, offset := totals[bounds[b]:bounds[b+1]], offsets[b]
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range block {
+				block[i] += offset
+			}
+		}()
+	}
+	wg.Wait()
+
+	return runningTotal, nil
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
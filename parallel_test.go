@@ -0,0 +1,128 @@
+package weightedrand
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestParallelBuild(t *testing.T) {
+	tests := []struct {
+		name   string
+		n      int
+		maxW   int
+		negW   int
+		sorted bool
+	}{
+		{name: "shuffled", n: 1000, maxW: 100},
+		{name: "sorted", n: 1000, maxW: 100, sorted: true},
+		{name: "with zero and negative", n: 1001, maxW: 5, negW: 3},
+		{name: "fewer choices than workers", n: 3, maxW: 10},
+	}
+	for _, tt := range tests {
+		for _, workers := range []int{2, 3, 4, 7} {
+			t.Run(fmt.Sprintf("%s/workers=%d", tt.name, workers), func(t *testing.T) {
+				rng := rand.New(rand.NewSource(int64(tt.n * workers)))
+				choices := make([]Choice[int, int], tt.n)
+				for i := range choices {
+					choices[i] = NewChoice(i, rng.Intn(tt.maxW+tt.negW+1)-tt.negW)
+				}
+				if tt.sorted {
+					sortChoices(choices)
+				}
+				want := append([]Choice[int, int](nil), choices...)
+				sortChoices(want)
+				wantTotals := make([]int, len(want))
+				wantMax, _ := fillTotals(want, wantTotals)
+
+				parallelSortChoices(choices, workers)
+				totals := make([]int, len(choices))
+				max, err := parallelFillTotals(choices, totals, workers)
+				if err != nil {
+					t.Fatal(err)
+				}
+				for i := range choices {
+					if choices[i].Weight != want[i].Weight {
+						t.Fatalf("weight[%d] = %d, want %d", i, choices[i].Weight, want[i].Weight)
+					}
+				}
+				if !reflect.DeepEqual(totals, wantTotals) {
+					t.Errorf("totals differ from sequential construction")
+				}
+				if max != wantMax {
+					t.Errorf("max = %d, want %d", max, wantMax)
+				}
+			})
+		}
+	}
+}
+
+func TestParallelSortChoices_presorted(t *testing.T) {
+	// Choices already in ascending order, ties included, must keep their order
+	// for any number of workers, so that PickByKey is reproducible.
+	choices := make([]Choice[int, int], 1000)
+	for i := range choices {
+		choices[i] = NewChoice(i, i/10)
+	}
+	for _, workers := range []int{1, 2, 3, 7} {
+		got := append([]Choice[int, int](nil), choices...)
+		parallelSortChoices(got, workers)
+		if !reflect.DeepEqual(got, choices) {
+			t.Errorf("workers=%d: presorted choices were reordered", workers)
+		}
+	}
+}
+
+func TestParallelFillTotals_overflow(t *testing.T) {
+	choices := []Choice[int, int]{
+		{Item: 0, Weight: 1},
+		{Item: 1, Weight: maxInt / 2},
+		{Item: 2, Weight: maxInt / 2},
+		{Item: 3, Weight: maxInt / 2},
+	}
+	for _, workers := range []int{2, 4} {
+		totals := make([]int, len(choices))
+		if _, err := parallelFillTotals(choices, totals, workers); err != errWeightOverflow {
+			t.Errorf("workers=%d: err = %v, want %v", workers, err, errWeightOverflow)
+		}
+	}
+}
+
+func TestWithParallelBuild(t *testing.T) {
+	choices := mockChoices(parallelBuildMinLen)
+	rand.Shuffle(len(choices), func(i, j int) {
+		choices[i], choices[j] = choices[j], choices[i]
+	})
+	want, err := NewChooser(append([]Choice[rune, int](nil), choices...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := NewChooserWithOptions(choices, WithParallelBuild())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.max != want.max || !reflect.DeepEqual(got.totals, want.totals) {
+		t.Error("parallel construction differs from NewChooser")
+	}
+}
+
+func BenchmarkNewChooser_parallel(b *testing.B) {
+	for n := parallelBuildMinLen; n <= BMMaxChoices; n *= 10 {
+		b.Run(fmt.Sprintf("size=%d", n), func(b *testing.B) {
+			choices := mockChoices(n)
+			rand.Shuffle(len(choices), func(i, j int) {
+				choices[i], choices[j] = choices[j], choices[i]
+			})
+			input := make([]Choice[rune, int], n)
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				copy(input, choices)
+				b.StartTimer()
+				_, _ = NewChooserWithOptions(input, WithParallelBuild())
+			}
+		})
+	}
+}
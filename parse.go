@@ -0,0 +1,126 @@
+package weightedrand
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mroth/weightedrand/v2/internal/percent"
+)
+
+// errChoiceSpec is wrapped by errors returned by ParseChoices.
+var errChoiceSpec = errors.New("invalid choice spec")
+
+// ParseChoices parses a comma separated list of "item:weight" pairs, such as
+// "a:5, b:3, c:1", so that quick tools and command line flags can define
+// weighted sets in a single string. Weights are non-negative base 10
+// integers. Whitespace surrounding items and weights is ignored.
+//
+// Alternatively, weights may all be written as percentages summing to exactly
+// 100%, such as "a:99.5%, b:0.5%", which are converted to the smallest integer
+// weights in exactly the same proportions, here 199 and 1.
+//
+// A backslash escapes the following character, so that items may contain
+// commas, colons, backslashes or surrounding whitespace: "a\:b:1" is the item
+// "a:b" with a weight of 1.
+func ParseChoices[T ~string](spec string) ([]Choice[T, int], error) {
+	entries, err := splitSpec(spec, ',')
+	if err != nil {
+		return nil, err
+	}
+	choices := make([]Choice[T, int], 0, len(entries))
+	var percents []percent.Percent
+	for i, entry := range entries {
+		fields, _ := splitSpec(entry, ':') // entry is already validated
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("%w: entry %d %q: want item:weight", errChoiceSpec, i+1, strings.TrimSpace(entry))
+		}
+		item := unescapeSpec(trimSpec(fields[0]))
+		if item == "" {
+			return nil, fmt.Errorf("%w: entry %d: empty item", errChoiceSpec, i+1)
+		}
+		weight := trimSpec(fields[1])
+		if i > 0 && percent.Is(weight) != (len(percents) > 0) {
+			return nil, fmt.Errorf("%w: entry %d: weights mix percentages and integers", errChoiceSpec, i+1)
+		}
+		if percent.Is(weight) {
+			p, err := percent.Parse(weight)
+			if err != nil {
+				return nil, fmt.Errorf("%w: entry %d: %v for item %q", errChoiceSpec, i+1, err, item)
+			}
+			percents = append(percents, p)
+			choices = append(choices, NewChoice(T(item), 0))
+			continue
+		}
+		w, err := strconv.Atoi(weight)
+		if err != nil || w < 0 {
+			return nil, fmt.Errorf("%w: entry %d: invalid weight %q for item %q", errChoiceSpec, i+1, weight, item)
+		}
+		choices = append(choices, NewChoice(T(item), w))
+	}
+
+	if len(percents) > 0 {
+		weights, err := percent.Weights(percents)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", errChoiceSpec, err)
+		}
+		for i, w := range weights {
+			choices[i].Weight = w
+		}
+	}
+	return choices, nil
+}
+
+// splitSpec splits s around each unescaped sep, leaving escapes in place. It
+// returns an error if s ends in an unpaired backslash.
+func splitSpec(s string, sep byte) ([]string, error) {
+	var fields []string
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if i++; i == len(s) {
+				return nil, fmt.Errorf("%w: trailing backslash", errChoiceSpec)
+			}
+		case sep:
+			fields = append(fields, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(fields, s[start:]), nil
+}
+
+// trimSpec trims unescaped whitespace from both ends of s.
+func trimSpec(s string) string {
+	s = strings.TrimLeft(s, " \t\n\r")
+	end := len(s)
+	for end > 0 && strings.IndexByte(" \t\n\r", s[end-1]) >= 0 {
+		// Count the backslashes preceding the space: if odd, it is escaped.
+		n := 0
+		for n < end-1 && s[end-2-n] == '\\' {
+			n++
+		}
+		if n%2 == 1 {
+			break
+		}
+		end--
+	}
+	return s[:end]
+}
+
+// unescapeSpec replaces each backslash escape in s with the escaped character.
+func unescapeSpec(s string) string {
+	if strings.IndexByte(s, '\\') < 0 {
+		return s
+	}
+	var b strings.Builder
+	b.Grow(len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' {
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
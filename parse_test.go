@@ -0,0 +1,67 @@
+package weightedrand
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestParseChoices(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		want    string
+		wantErr string
+	}{
+		{name: "basic", spec: "a:5, b:3, c:1", want: "[{a 5} {b 3} {c 1}]"},
+		{name: "single", spec: "only:0", want: "[{only 0}]"},
+		{name: "whitespace", spec: " \ta : 5 ,\nb:3 ", want: "[{a 5} {b 3}]"},
+		{name: "escaped separators", spec: `a\,b:1, c\:d:2`, want: "[{a,b 1} {c:d 2}]"},
+		{name: "escaped backslash", spec: `a\\:1`, want: `[{a\ 1}]`},
+		{name: "escaped whitespace", spec: `\ a\ :1`, want: "[{ a  1}]"},
+		{name: "escaped letter", spec: `\a:1`, want: "[{a 1}]"},
+		{name: "inner spaces", spec: "new york:2", want: "[{new york 2}]"},
+		{name: "duplicates", spec: "a:1,a:2", want: "[{a 1} {a 2}]"},
+		{name: "percentages", spec: "a:99.5%, b:0.5%, c:0%", want: "[{a 199} {b 1} {c 0}]"},
+		{name: "percentages not summing to 100", spec: "a:33.3%, b:33.3%, c:33.3%", wantErr: "sum is 99.9%"},
+		{name: "invalid percentage", spec: "a:-5%, b:105%", wantErr: `entry 1: invalid percentage "-5%" for item "a"`},
+		{name: "mixed percentage first", spec: "a:50%, b:1", wantErr: "entry 2: weights mix percentages and integers"},
+		{name: "mixed integer first", spec: "a:1, b:50%", wantErr: "entry 2: weights mix percentages and integers"},
+		{name: "empty", spec: "", wantErr: `entry 1 "": want item:weight`},
+		{name: "trailing comma", spec: "a:1,", wantErr: `entry 2 "": want item:weight`},
+		{name: "missing weight", spec: "a:1, b", wantErr: `entry 2 "b": want item:weight`},
+		{name: "extra colon", spec: "a:b:1", wantErr: `entry 1 "a:b:1": want item:weight`},
+		{name: "empty item", spec: " :1", wantErr: "entry 1: empty item"},
+		{name: "empty weight", spec: "a:", wantErr: `entry 1: invalid weight "" for item "a"`},
+		{name: "negative weight", spec: "a:-1", wantErr: `invalid weight "-1"`},
+		{name: "fractional weight", spec: "a:1.5", wantErr: `invalid weight "1.5"`},
+		{name: "weight overflow", spec: "a:99999999999999999999", wantErr: "invalid weight"},
+		{name: "escaped colon only", spec: `a\:1`, wantErr: "want item:weight"},
+		{name: "trailing backslash", spec: `a:1\`, wantErr: "trailing backslash"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseChoices[string](tt.spec)
+			if tt.wantErr != "" {
+				if !errors.Is(err, errChoiceSpec) || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseChoices(%q) error = %v, want containing %q", tt.spec, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseChoices(%q) error = %v", tt.spec, err)
+			}
+			if fmt.Sprint(got) != tt.want {
+				t.Errorf("ParseChoices(%q) = %v, want %v", tt.spec, got, tt.want)
+			}
+		})
+	}
+}
+
+func ExampleParseChoices() {
+	choices, _ := ParseChoices[string]("cherry:0, avocado:5")
+	chooser, _ := NewChooser(choices...)
+	fmt.Println(chooser.Pick())
+	// Output: avocado
+}
@@ -0,0 +1,55 @@
+package weightedrand
+
+import "sort"
+
+// Partition randomly assigns each of items to one of len(groupWeights) groups,
+// with probability proportional to the weight of the group, such as to split
+// a population into test cohorts. The groups are returned in the same order as
+// groupWeights, each holding its items in their original order. Groups with a
+// weight < 1 are always empty.
+//
+// Utilizes global rand as the source of randomness.
+func Partition[T any, W integer](items []T, groupWeights []W) ([][]T, error) {
+	c, err := newGroupChooser(groupWeights)
+	if err != nil {
+		return nil, err
+	}
+	groups := make([][]T, len(groupWeights))
+	for _, item := range items {
+		g := c.Pick()
+		groups[g] = append(groups[g], item)
+	}
+	return groups, nil
+}
+
+// PartitionByKey is like Partition, but assigns each item deterministically by
+// its key, as with PickByKey, so that the same item is always assigned to the
+// same group for the same group weights, even across processes. This keeps
+// cohorts and data splits stable as items are added to or removed from the
+// population.
+func PartitionByKey[T any, W integer](items []T, key func(T) string, groupWeights []W) ([][]T, error) {
+	c, err := newGroupChooser(groupWeights)
+	if err != nil {
+		return nil, err
+	}
+	groups := make([][]T, len(groupWeights))
+	for _, item := range items {
+		g := c.PickByKey(key(item))
+		groups[g] = append(groups[g], item)
+	}
+	return groups, nil
+}
+
+// newGroupChooser returns a Chooser of the indices of groupWeights. The indices
+// are given to it in a stable ascending order of weight, which it never
+// reorders, so that keyed assignments do not depend on the sort algorithm.
+func newGroupChooser[W integer](groupWeights []W) (*Chooser[int, W], error) {
+	choices := make([]Choice[int, W], len(groupWeights))
+	for i, w := range groupWeights {
+		choices[i] = NewChoice(i, w)
+	}
+	sort.SliceStable(choices, func(i, j int) bool {
+		return choices[i].Weight < choices[j].Weight
+	})
+	return NewChooser(choices...)
+}
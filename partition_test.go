@@ -0,0 +1,92 @@
+package weightedrand
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func ExamplePartitionByKey() {
+	users := []string{"ana", "ben", "cam", "dee", "eli", "fay", "gus", "hal"}
+	cohorts, _ := PartitionByKey(users, func(u string) string { return u }, []int{1, 1})
+	fmt.Println("control:", cohorts[0])
+	fmt.Println("treatment:", cohorts[1])
+	// Output:
+	// control: [ana ben eli fay gus]
+	// treatment: [cam dee hal]
+}
+
+func TestPartition(t *testing.T) {
+	const n = 100000
+	items := make([]int, n)
+	for i := range items {
+		items[i] = i
+	}
+	weights := []int{1, 0, 3, -1, 6}
+
+	partitions := map[string]func() ([][]int, error){
+		"Partition": func() ([][]int, error) { return Partition(items, weights) },
+		"PartitionByKey": func() ([][]int, error) {
+			return PartitionByKey(items, strconv.Itoa, weights)
+		},
+	}
+	for name, partition := range partitions {
+		t.Run(name, func(t *testing.T) {
+			groups, err := partition()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(groups) != len(weights) {
+				t.Fatalf("got %d groups, want %d", len(groups), len(weights))
+			}
+			seen := 0
+			for g, group := range groups {
+				for i := 1; i < len(group); i++ {
+					if group[i] <= group[i-1] {
+						t.Fatalf("group %d not in original order", g)
+					}
+				}
+				seen += len(group)
+				want := math.Max(0, float64(weights[g])) / 10
+				if got := float64(len(group)) / n; math.Abs(got-want) > 0.01 {
+					t.Errorf("group %d holds %.3f of items, want %.3f", g, got, want)
+				}
+			}
+			if seen != n {
+				t.Errorf("groups hold %d items, want %d", seen, n)
+			}
+		})
+	}
+}
+
+func TestPartitionByKey_stable(t *testing.T) {
+	key := func(s string) string { return s }
+	all, _ := PartitionByKey([]string{"a", "b", "c", "d", "e", "f"}, key, []int{1, 1, 1})
+	some, _ := PartitionByKey([]string{"b", "e"}, key, []int{1, 1, 1})
+	for g := range all {
+		var want []string
+		for _, item := range all[g] {
+			if item == "b" || item == "e" {
+				want = append(want, item)
+			}
+		}
+		if !reflect.DeepEqual(some[g], want) {
+			t.Errorf("group %d = %v, want %v", g, some[g], want)
+		}
+	}
+}
+
+func TestPartition_errors(t *testing.T) {
+	if _, err := Partition([]int{1}, []int{0, -1}); err != errNoValidChoices {
+		t.Errorf("Partition() error = %v, want %v", err, errNoValidChoices)
+	}
+	if _, err := PartitionByKey([]int{1}, strconv.Itoa, []int(nil)); err != errNoValidChoices {
+		t.Errorf("PartitionByKey() error = %v, want %v", err, errNoValidChoices)
+	}
+	groups, err := Partition([]int(nil), []int{1, 2})
+	if err != nil || len(groups) != 2 {
+		t.Errorf("Partition(nil) = %v, %v, want 2 empty groups", groups, err)
+	}
+}
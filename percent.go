@@ -0,0 +1,49 @@
+package weightedrand
+
+import (
+	"errors"
+	"math"
+)
+
+// percentEpsilon is the tolerance within which percentages must sum to 100,
+// allowing for floating point error but not for mis-summed configurations.
+const percentEpsilon = 1e-6
+
+// Possible errors returned by NewChooserFromPercents.
+var (
+	errPercentValue = errors.New("percentages must be finite and non-negative")
+	errPercentSum   = errors.New("percentages do not sum to 100")
+)
+
+// PercentChoice is an item with the percentage chance of it being picked.
+type PercentChoice[T any] struct {
+	Item    T
+	Percent float64
+}
+
+// NewChooserFromPercents initializes a new Chooser picking each item with the
+// given percentage chance. Unlike NewChooser, which accepts any relative
+// weights, it returns an error unless the percentages sum to 100, so that
+// mis-summed traffic splits and the like are caught at construction.
+//
+// Percentages are scaled to integer weights with a resolution of 2^-30, so
+// items with a percentage below roughly 1e-7 are never picked.
+func NewChooserFromPercents[T any](choices ...PercentChoice[T]) (*Chooser[T, int], error) {
+	var sum float64
+	for _, c := range choices {
+		if !(c.Percent >= 0) || math.IsInf(c.Percent, 1) {
+			return nil, errPercentValue
+		}
+		sum += c.Percent
+	}
+	if math.Abs(sum-100) > percentEpsilon {
+		return nil, errPercentSum
+	}
+
+	weighted := make([]Choice[T, int], len(choices))
+	for i, c := range choices {
+		w := int(math.Round(c.Percent / sum * probabilityResolution))
+		weighted[i] = NewChoice(c.Item, w)
+	}
+	return NewChooser(weighted...)
+}
@@ -0,0 +1,58 @@
+package weightedrand
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func ExampleNewChooserFromPercents() {
+	chooser, err := NewChooserFromPercents(
+		PercentChoice[string]{Item: "stable", Percent: 95},
+		PercentChoice[string]{Item: "canary", Percent: 4.5},
+	)
+	fmt.Println(chooser, err)
+	//Output: <nil> percentages do not sum to 100
+}
+
+func TestNewChooserFromPercents(t *testing.T) {
+	tests := []struct {
+		name     string
+		percents []float64
+		wantErr  error
+	}{
+		{name: "whole", percents: []float64{50, 30, 20}},
+		{name: "fractional", percents: []float64{33.33, 33.33, 33.34}},
+		{name: "floating point error", percents: []float64{0.1, 0.2, 99.7}},
+		{name: "single", percents: []float64{100}},
+		{name: "zero percent choice", percents: []float64{0, 100}},
+		{name: "under", percents: []float64{33.33, 33.33, 33.33}, wantErr: errPercentSum},
+		{name: "over", percents: []float64{60, 50}, wantErr: errPercentSum},
+		{name: "fractions of one", percents: []float64{0.25, 0.75}, wantErr: errPercentSum},
+		{name: "no choices", percents: nil, wantErr: errPercentSum},
+		{name: "negative", percents: []float64{110, -10}, wantErr: errPercentValue},
+		{name: "NaN", percents: []float64{100, math.NaN()}, wantErr: errPercentValue},
+		{name: "infinite", percents: []float64{100, math.Inf(1)}, wantErr: errPercentValue},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			choices := make([]PercentChoice[int], len(tt.percents))
+			for i, p := range tt.percents {
+				choices[i] = PercentChoice[int]{Item: i, Percent: p}
+			}
+			c, err := NewChooserFromPercents(choices...)
+			if err != tt.wantErr {
+				t.Fatalf("NewChooserFromPercents() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			for _, choice := range c.data {
+				want := tt.percents[choice.Item] / 100
+				if got := float64(choice.Weight) / float64(c.max); math.Abs(got-want) > 1e-8 {
+					t.Errorf("probability of %d = %v, want %v", choice.Item, got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,15 @@
+package weightedrand
+
+// Picker is implemented by anything which picks items, such as a Chooser. It
+// allows code depending on weighted random selection to accept a Picker, so
+// that a deterministic implementation can be injected in tests.
+type Picker[T any] interface {
+	Pick() T
+}
+
+var (
+	_ Picker[int] = (*Chooser[int, int])(nil)
+	_ Picker[int] = (*NarrowChooser[int, int])(nil)
+	_ Picker[int] = (*PackedChooser[int, int])(nil)
+	_ Picker[int] = (*AliasChooser[int, int])(nil)
+)
@@ -0,0 +1,23 @@
+package weightedrand
+
+import "fmt"
+
+// constant is a Picker always returning the same item.
+type constant string
+
+func (c constant) Pick() string { return string(c) }
+
+// greeting depends on a Picker rather than a concrete Chooser, so tests can
+// substitute a deterministic implementation.
+func greeting(p Picker[string]) string {
+	return p.Pick() + ", world"
+}
+
+func ExamplePicker() {
+	chooser, _ := NewChooser(NewChoice("hello", 1), NewChoice("goodbye", 0))
+	fmt.Println(greeting(chooser))
+	fmt.Println(greeting(constant("howdy")))
+	// Output:
+	// hello, world
+	// howdy, world
+}
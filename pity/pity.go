@@ -0,0 +1,105 @@
+// Package pity wraps weighted random selection with a "pity" guarantee, as in
+// gacha games: after a run of consecutive picks without any of a set of rare
+// items, the next pick is forced to be rare, after which the count resets.
+//
+// Misses are tracked independently per key, such as per user or per stream of
+// picks, and updated atomically with each pick, so concurrent picks for the
+// same key cannot both claim or both skip a guaranteed hit.
+package pity
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/mroth/weightedrand/v2"
+	"github.com/mroth/weightedrand/v2/internal/constraints"
+)
+
+// Possible errors returned by New.
+var (
+	ErrThreshold = errors.New("pity: threshold must be >= 1")
+	ErrNoRare    = errors.New("pity: zero rare items with weight >= 1")
+)
+
+// A Picker picks items with a guarantee of a rare item after a threshold of
+// consecutive misses per key. It is safe for concurrent usage.
+type Picker[K comparable, T comparable, W constraints.Integer] struct {
+	threshold int
+	all       *weightedrand.Chooser[T, W]
+	rare      *weightedrand.Chooser[T, W]
+	isRare    map[T]bool
+
+	mu     sync.Mutex
+	misses map[K]int
+}
+
+// New initializes a Picker picking from choices, in which the given rare items
+// are guaranteed to be picked for a key after threshold consecutive picks
+// without any of them. Forced picks choose among the rare items in proportion
+// to their weights.
+//
+// The key type must be given explicitly, such as New[string](90, choices,
+// "legendary").
+func New[K comparable, T comparable, W constraints.Integer](threshold int, choices []weightedrand.Choice[T, W], rare ...T) (*Picker[K, T, W], error) {
+	if threshold < 1 {
+		return nil, ErrThreshold
+	}
+	p := &Picker[K, T, W]{
+		threshold: threshold,
+		isRare:    make(map[T]bool, len(rare)),
+		misses:    make(map[K]int),
+	}
+	for _, item := range rare {
+		p.isRare[item] = true
+	}
+
+	// Copy, since each Chooser takes ownership of its slice.
+	var rareChoices []weightedrand.Choice[T, W]
+	for _, c := range choices {
+		if p.isRare[c.Item] {
+			rareChoices = append(rareChoices, c)
+		}
+	}
+	var err error
+	if p.rare, err = weightedrand.NewChooser(rareChoices...); err != nil {
+		return nil, ErrNoRare
+	}
+	if p.all, err = weightedrand.NewChooser(append([]weightedrand.Choice[T, W](nil), choices...)...); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Pick returns a weighted random item for key, or a rare item if key has had
+// threshold consecutive picks without one.
+func (p *Picker[K, T, W]) Pick(key K) T {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.misses[key] >= p.threshold {
+		delete(p.misses, key)
+		return p.rare.Pick()
+	}
+	item := p.all.Pick()
+	if p.isRare[item] {
+		delete(p.misses, key)
+	} else {
+		p.misses[key]++
+	}
+	return item
+}
+
+// Misses returns the number of consecutive picks for key without a rare item.
+func (p *Picker[K, T, W]) Misses(key K) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.misses[key]
+}
+
+// Reset clears the count of misses for key, releasing its memory. Keys which
+// are no longer picked for, such as departed users, should be reset.
+func (p *Picker[K, T, W]) Reset(key K) {
+	p.mu.Lock()
+	delete(p.misses, key)
+	p.mu.Unlock()
+}
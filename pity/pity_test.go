@@ -0,0 +1,56 @@
+package pity
+
+import (
+	"testing"
+
+	"github.com/mroth/weightedrand/v2"
+)
+
+var banner = []weightedrand.Choice[string, int]{
+	weightedrand.NewChoice("common", 1_000_000),
+	weightedrand.NewChoice("legendary", 1),
+	weightedrand.NewChoice("mythic", 1),
+}
+
+func TestNew(t *testing.T) {
+	if _, err := New[string](0, banner, "legendary"); err != ErrThreshold {
+		t.Errorf("New() error = %v, want %v", err, ErrThreshold)
+	}
+	if _, err := New[string](10, banner, "missing"); err != ErrNoRare {
+		t.Errorf("New() error = %v, want %v", err, ErrNoRare)
+	}
+	if _, err := New[string](10, banner, "legendary"); err != nil {
+		t.Errorf("New() error = %v", err)
+	}
+}
+
+func TestPicker_Pick(t *testing.T) {
+	p, _ := New[string](10, banner, "legendary", "mythic")
+
+	for round := 0; round < 3; round++ {
+		for i := 0; i < 10; i++ {
+			if got := p.Pick("alice"); got != "common" {
+				t.Skipf("rare item %q picked naturally; vanishingly unlikely", got)
+			}
+		}
+		if got := p.Misses("alice"); got != 10 {
+			t.Fatalf("Misses() = %d, want 10", got)
+		}
+		if got := p.Pick("alice"); got != "legendary" && got != "mythic" {
+			t.Fatalf("Pick() after 10 misses = %q, want a rare item", got)
+		}
+		if got := p.Misses("alice"); got != 0 {
+			t.Fatalf("Misses() after guaranteed hit = %d, want 0", got)
+		}
+	}
+
+	// keys are tracked independently
+	p.Pick("bob")
+	if got := p.Misses("bob"); got != 1 {
+		t.Errorf("Misses(bob) = %d, want 1", got)
+	}
+	p.Reset("bob")
+	if got := p.Misses("bob"); got != 0 {
+		t.Errorf("Misses(bob) after Reset = %d, want 0", got)
+	}
+}
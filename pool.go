@@ -0,0 +1,45 @@
+package weightedrand
+
+import "sync"
+
+// A Pool is a set of Choosers whose backing arrays may be reused, for
+// workloads which must construct a short-lived Chooser for every request, such
+// as per-request personalization. Constructing Choosers from a Pool rather
+// than with NewChooser reduces per-request allocations and GC pressure.
+//
+// The zero value is ready to use. A Pool is safe for concurrent usage, but the
+// Choosers obtained from it are owned by the caller until returned with Put.
+type Pool[T any, W integer] struct {
+	p sync.Pool
+}
+
+// Get returns a Chooser for picking from the provided choices, reusing a
+// previously Put Chooser if one is available. The result is equivalent to one
+// constructed by NewChooser, and takes ownership of the choices slice in the
+// same way.
+func (p *Pool[T, W]) Get(choices ...Choice[T, W]) (*Chooser[T, W], error) {
+	c, _ := p.p.Get().(*Chooser[T, W])
+	if c == nil {
+		c = &Chooser[T, W]{}
+	}
+	if err := c.Reset(choices...); err != nil {
+		p.p.Put(c)
+		return nil, err
+	}
+	return c, nil
+}
+
+// Put returns c to the Pool for reuse by a later call to Get. The Chooser must
+// not be used by the caller after it has been Put.
+func (p *Pool[T, W]) Put(c *Chooser[T, W]) {
+	if c == nil {
+		return
+	}
+	// Drop everything but the backing arrays, so the pool does not retain the
+	// previous choices, nor options they were constructed with.
+	*c = Chooser[T, W]{
+		totals:  c.totals[:0],
+		summary: Summary[W]{Histogram: c.summary.Histogram[:0]},
+	}
+	p.p.Put(c)
+}
@@ -0,0 +1,74 @@
+package weightedrand
+
+import (
+	"fmt"
+	"testing"
+)
+
+func ExamplePool() {
+	var pool Pool[string, int]
+
+	// e.g. within a request handler
+	chooser, _ := pool.Get(
+		NewChoice("recommended", 1),
+		NewChoice("unavailable", 0),
+	)
+	fmt.Println(chooser.Pick())
+	pool.Put(chooser)
+	// Output: recommended
+}
+
+func TestPool(t *testing.T) {
+	var pool Pool[rune, int]
+	a, err := pool.Get(NewChoice('a', 1), NewChoice('b', 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := a.Pick(); got != 'a' {
+		t.Errorf("Pick() = %q, want 'a'", got)
+	}
+	pool.Put(a)
+	pool.Put(nil) // no-op
+
+	if _, err := pool.Get(NewChoice('c', 0)); err != errNoValidChoices {
+		t.Errorf("Get() error = %v, want %v", err, errNoValidChoices)
+	}
+
+	b, err := pool.Get(NewChoice('c', 2), NewChoice('d', 3), NewChoice('e', 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.max != 5 || len(b.totals) != 3 || b.rng != nil || b.onPick != nil {
+		t.Errorf("Get() returned Chooser with unexpected state: %+v", b)
+	}
+	if s := b.Summary(); s.Count != 2 || s.Zero != 1 {
+		t.Errorf("Summary() = %v", s)
+	}
+}
+
+func BenchmarkPool(b *testing.B) {
+	for n := BMMinChoices; n <= 1000; n *= 10 {
+		choices := mockChoices(n)
+		sortChoices(choices) // measure construction, not sort.Slice
+		input := make([]Choice[rune, int], n)
+
+		b.Run(fmt.Sprintf("size=%s/NewChooser", fmt1eN(n)), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				copy(input, choices)
+				c, _ := NewChooser(input...)
+				_ = c.Pick()
+			}
+		})
+		b.Run(fmt.Sprintf("size=%s/Pool", fmt1eN(n)), func(b *testing.B) {
+			var pool Pool[rune, int]
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				copy(input, choices)
+				c, _ := pool.Get(input...)
+				_ = c.Pick()
+				pool.Put(c)
+			}
+		})
+	}
+}
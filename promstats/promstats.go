@@ -0,0 +1,123 @@
+// Package promstats exposes Prometheus metrics for a weightedrand Chooser,
+// including per-choice pick counts, configured weights, and the expected
+// versus observed probability of each choice, so that drift between the
+// configured and actual distribution can be monitored.
+//
+// A Collector is attached to a Chooser via its Observe method:
+//
+//	stats := promstats.NewCollector("experiment1", choices)
+//	prometheus.MustRegister(stats)
+//	chooser, err := weightedrand.NewChooserWithOptions(choices, weightedrand.WithOnPick(stats.Observe))
+//
+// This package is a separate module, so that the core weightedrand module
+// remains free of dependencies.
+package promstats
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"github.com/mroth/weightedrand/v2"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	picksDesc = prometheus.NewDesc(
+		"weightedrand_picks_total",
+		"Number of times each choice has been picked.",
+		[]string{"chooser", "choice"}, nil,
+	)
+	weightDesc = prometheus.NewDesc(
+		"weightedrand_choice_weight",
+		"Configured weight of each choice.",
+		[]string{"chooser", "choice"}, nil,
+	)
+	expectedDesc = prometheus.NewDesc(
+		"weightedrand_choice_expected_probability",
+		"Probability of each choice being picked according to its configured weight.",
+		[]string{"chooser", "choice"}, nil,
+	)
+	observedDesc = prometheus.NewDesc(
+		"weightedrand_choice_observed_probability",
+		"Fraction of all picks so far which selected each choice.",
+		[]string{"chooser", "choice"}, nil,
+	)
+)
+
+// integer is satisfied by any type which may be used as a Choice Weight.
+type integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 | ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// A Collector is a prometheus.Collector tracking picks for a single Chooser.
+type Collector[T comparable, W integer] struct {
+	name    string
+	choices []*choiceStats[W]
+	byItem  map[T]*choiceStats[W]
+	total   float64 // sum of pickable weights
+}
+
+type choiceStats[W integer] struct {
+	label  string
+	weight W
+	picks  uint64 // accessed atomically
+}
+
+// NewCollector initializes a Collector for a Chooser constructed from choices,
+// labeling its metrics with the given chooser name. Choices are labeled with
+// the default format of their item, as per fmt.Sprint, and duplicate items
+// have their weights combined.
+func NewCollector[T comparable, W integer](name string, choices []weightedrand.Choice[T, W]) *Collector[T, W] {
+	c := &Collector[T, W]{name: name, byItem: make(map[T]*choiceStats[W], len(choices))}
+	for _, choice := range choices {
+		if choice.Weight < 1 {
+			continue
+		}
+		c.total += float64(choice.Weight)
+		if s, ok := c.byItem[choice.Item]; ok {
+			s.weight += choice.Weight
+			continue
+		}
+		s := &choiceStats[W]{label: fmt.Sprint(choice.Item), weight: choice.Weight}
+		c.byItem[choice.Item] = s
+		c.choices = append(c.choices, s)
+	}
+	return c
+}
+
+// Observe records a pick of item. Its signature matches that expected by
+// weightedrand.WithOnPick. Picks of items unknown to the Collector are ignored.
+func (c *Collector[T, W]) Observe(item T, weight W) {
+	if s, ok := c.byItem[item]; ok {
+		atomic.AddUint64(&s.picks, 1)
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector[T, W]) Describe(ch chan<- *prometheus.Desc) {
+	ch <- picksDesc
+	ch <- weightDesc
+	ch <- expectedDesc
+	ch <- observedDesc
+}
+
+// Collect implements prometheus.Collector.
+func (c *Collector[T, W]) Collect(ch chan<- prometheus.Metric) {
+	picks := make([]uint64, len(c.choices))
+	var total uint64
+	for i, s := range c.choices {
+		picks[i] = atomic.LoadUint64(&s.picks)
+		total += picks[i]
+	}
+
+	for i, s := range c.choices {
+		ch <- prometheus.MustNewConstMetric(picksDesc, prometheus.CounterValue, float64(picks[i]), c.name, s.label)
+		ch <- prometheus.MustNewConstMetric(weightDesc, prometheus.GaugeValue, float64(s.weight), c.name, s.label)
+		ch <- prometheus.MustNewConstMetric(expectedDesc, prometheus.GaugeValue, float64(s.weight)/c.total, c.name, s.label)
+		observed := 0.0
+		if total > 0 {
+			observed = float64(picks[i]) / float64(total)
+		}
+		ch <- prometheus.MustNewConstMetric(observedDesc, prometheus.GaugeValue, observed, c.name, s.label)
+	}
+}
@@ -0,0 +1,67 @@
+package promstats
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mroth/weightedrand/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestCollector(t *testing.T) {
+	choices := []weightedrand.Choice[string, int]{
+		weightedrand.NewChoice("a", 1),
+		weightedrand.NewChoice("b", 3),
+		weightedrand.NewChoice("z", 0),
+	}
+	stats := NewCollector("test", choices)
+	reg := prometheus.NewPedanticRegistry()
+	reg.MustRegister(stats)
+
+	chooser, err := weightedrand.NewChooserWithOptions(choices, weightedrand.WithOnPick(stats.Observe))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 4; i++ {
+		chooser.Pick()
+	}
+	stats.Observe("unknown", 1) // ignored
+
+	const want = `
+# HELP weightedrand_choice_expected_probability Probability of each choice being picked according to its configured weight.
+# TYPE weightedrand_choice_expected_probability gauge
+weightedrand_choice_expected_probability{choice="a",chooser="test"} 0.25
+weightedrand_choice_expected_probability{choice="b",chooser="test"} 0.75
+# HELP weightedrand_choice_weight Configured weight of each choice.
+# TYPE weightedrand_choice_weight gauge
+weightedrand_choice_weight{choice="a",chooser="test"} 1
+weightedrand_choice_weight{choice="b",chooser="test"} 3
+`
+	err = testutil.GatherAndCompare(reg, strings.NewReader(want),
+		"weightedrand_choice_weight", "weightedrand_choice_expected_probability")
+	if err != nil {
+		t.Error(err)
+	}
+
+	if n := testutil.CollectAndCount(stats, "weightedrand_picks_total"); n != 2 {
+		t.Errorf("got %d pick counters, want 2", n)
+	}
+	var sum uint64
+	for _, s := range stats.choices {
+		sum += s.picks
+	}
+	if sum != 4 {
+		t.Errorf("recorded %d picks, want 4", sum)
+	}
+}
+
+func TestCollector_duplicates(t *testing.T) {
+	stats := NewCollector("test", []weightedrand.Choice[string, int]{
+		weightedrand.NewChoice("a", 1),
+		weightedrand.NewChoice("a", 2),
+	})
+	if len(stats.choices) != 1 || stats.choices[0].weight != 3 {
+		t.Errorf("expected duplicates to be combined, got %+v", stats.choices[0])
+	}
+}
@@ -0,0 +1,142 @@
+// Package quota generates streams of items whose final counts exactly match
+// the proportions of their weights, in a random order, for test fixtures and
+// task assignment where exact proportions are required rather than merely
+// expected.
+package quota
+
+import (
+	"errors"
+	"math/bits"
+	"math/rand"
+	"sort"
+	"sync"
+
+	"github.com/mroth/weightedrand/v2"
+	"github.com/mroth/weightedrand/v2/internal/constraints"
+)
+
+// Possible errors returned by Apportion and New.
+var (
+	ErrNegative       = errors.New("quota: negative count")
+	ErrNoValidChoices = errors.New("quota: zero choices with weight >= 1")
+	ErrWeightOverflow = errors.New("quota: sum of weights exceeds max uint64")
+)
+
+// Apportion divides n among choices in proportion to their weights by the
+// largest remainder method, returning the count for each choice in the order
+// given. The counts always sum to exactly n.
+//
+// Each choice first receives the integer part of its exact quota n·w/W, for
+// weight w and total weight W, then the remaining units go to the choices with
+// the largest fractional remainders, with ties going to the earlier choice.
+// Choices with a weight < 1 receive nothing. The result is deterministic.
+func Apportion[T any, W constraints.Integer](n int, choices ...weightedrand.Choice[T, W]) ([]int, error) {
+	if n < 0 {
+		return nil, ErrNegative
+	}
+	var total uint64
+	for _, c := range choices {
+		if c.Weight < 1 {
+			continue
+		}
+		var carry uint64
+		total, carry = bits.Add64(total, uint64(c.Weight), 0)
+		if carry != 0 {
+			return nil, ErrWeightOverflow
+		}
+	}
+	if total == 0 {
+		return nil, ErrNoValidChoices
+	}
+
+	counts := make([]int, len(choices))
+	remainders := make([]uint64, len(choices))
+	order := make([]int, 0, len(choices))
+	left := n
+	for i, c := range choices {
+		if c.Weight < 1 {
+			continue
+		}
+		// n·w/W computed exactly, the quotient being at most n since w <= W.
+		hi, lo := bits.Mul64(uint64(n), uint64(c.Weight))
+		q, r := bits.Div64(hi, lo, total)
+		counts[i], remainders[i] = int(q), r
+		left -= int(q)
+		order = append(order, i)
+	}
+	// All remainders are fractions of the same denominator W, so they can be
+	// compared directly. Fewer units are left over than there are choices.
+	sort.SliceStable(order, func(a, b int) bool {
+		return remainders[order[a]] > remainders[order[b]]
+	})
+	for _, i := range order[:left] {
+		counts[i]++
+	}
+	return counts, nil
+}
+
+// A Stream yields items in a random order, such that once exhausted each item
+// has been yielded exactly the number of times apportioned to it. Every order
+// of the apportioned items is equally likely. It is safe for concurrent usage.
+type Stream[T any] struct {
+	mu    sync.Mutex
+	items []T
+	tree  []int // Fenwick tree of the remaining count of each item
+	left  int
+}
+
+// New initializes a Stream of n items apportioned among choices in proportion
+// to their weights, as by Apportion.
+func New[T any, W constraints.Integer](n int, choices ...weightedrand.Choice[T, W]) (*Stream[T], error) {
+	counts, err := Apportion(n, choices...)
+	if err != nil {
+		return nil, err
+	}
+	s := &Stream[T]{
+		items: make([]T, len(choices)),
+		tree:  make([]int, len(choices)+1),
+		left:  n,
+	}
+	for i, c := range choices {
+		s.items[i] = c.Item
+		for j := i + 1; j < len(s.tree); j += j & -j {
+			s.tree[j] += counts[i]
+		}
+	}
+	return s, nil
+}
+
+// Next returns the next item of the Stream, or false once it is exhausted.
+//
+// Utilizes global rand as the source of randomness.
+func (s *Stream[T]) Next() (T, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.left == 0 {
+		var zero T
+		return zero, false
+	}
+
+	// Find the item whose range of remaining counts contains r, by descending
+	// the Fenwick tree, and remove one of its count.
+	r := rand.Intn(s.left)
+	pos := 0
+	for step := 1 << (bits.Len(uint(len(s.items))) - 1); step > 0; step >>= 1 {
+		if next := pos + step; next < len(s.tree) && s.tree[next] <= r {
+			pos = next
+			r -= s.tree[next]
+		}
+	}
+	for j := pos + 1; j < len(s.tree); j += j & -j {
+		s.tree[j]--
+	}
+	s.left--
+	return s.items[pos], true
+}
+
+// Remaining returns the number of items left in the Stream.
+func (s *Stream[T]) Remaining() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.left
+}
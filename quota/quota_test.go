@@ -0,0 +1,98 @@
+package quota
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/mroth/weightedrand/v2"
+)
+
+func TestApportion(t *testing.T) {
+	tests := []struct {
+		name    string
+		n       int
+		weights []uint64
+		want    []int
+		wantErr error
+	}{
+		{name: "exact", n: 10, weights: []uint64{1, 4, 5}, want: []int{1, 4, 5}},
+		{name: "remainders", n: 10, weights: []uint64{1, 1, 1}, want: []int{4, 3, 3}},
+		{name: "largest remainder", n: 7, weights: []uint64{3, 1, 2, 0}, want: []int{4, 1, 2, 0}},
+		{name: "zero", n: 0, weights: []uint64{1, 2}, want: []int{0, 0}},
+		{name: "huge weights", n: 3, weights: []uint64{math.MaxUint64 / 2, math.MaxUint64 / 2}, want: []int{2, 1}},
+		{name: "negative", n: -1, weights: []uint64{1}, wantErr: ErrNegative},
+		{name: "no positive weights", n: 1, weights: []uint64{0}, wantErr: ErrNoValidChoices},
+		{name: "overflow", n: 1, weights: []uint64{math.MaxUint64, 1}, wantErr: ErrWeightOverflow},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			choices := make([]weightedrand.Choice[int, uint64], len(tt.weights))
+			for i, w := range tt.weights {
+				choices[i] = weightedrand.NewChoice(i, w)
+			}
+			got, err := Apportion(tt.n, choices...)
+			if err != tt.wantErr {
+				t.Fatalf("Apportion() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Apportion() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStream(t *testing.T) {
+	const n = 1000
+	choices := []weightedrand.Choice[string, int]{
+		{Item: "a", Weight: 1}, {Item: "never", Weight: 0}, {Item: "b", Weight: 2},
+		{Item: "c", Weight: 3}, {Item: "d", Weight: 7},
+	}
+	want, err := Apportion(n, choices...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := New(n, choices...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	counts := make(map[string]int)
+	var prev string
+	var changes int
+	for {
+		item, ok := s.Next()
+		if !ok {
+			break
+		}
+		counts[item]++
+		if item != prev {
+			changes++
+		}
+		prev = item
+	}
+	if s.Remaining() != 0 {
+		t.Errorf("Remaining() = %d after exhaustion", s.Remaining())
+	}
+	for i, c := range choices {
+		if counts[c.Item] != want[i] {
+			t.Errorf("count of %q = %d, want %d", c.Item, counts[c.Item], want[i])
+		}
+	}
+	// The order must be shuffled rather than runs of each item, for which
+	// around 630 changes of item would be expected.
+	if changes < 400 {
+		t.Errorf("stream changed item only %d times, does not appear shuffled", changes)
+	}
+}
+
+func ExampleApportion() {
+	counts, _ := Apportion(10,
+		weightedrand.NewChoice("control", 1),
+		weightedrand.NewChoice("variant a", 1),
+		weightedrand.NewChoice("variant b", 1),
+	)
+	fmt.Println(counts)
+	// Output: [4 3 3]
+}
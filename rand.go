@@ -0,0 +1,202 @@
+package weightedrand
+
+import (
+	crand "crypto/rand"
+	"encoding/binary"
+	"math/bits"
+	"math/rand"
+	"runtime"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unsafe"
+
+	"github.com/mroth/weightedrand/v2/internal/hash"
+)
+
+// intner is a source of random numbers for a Chooser. Implementations must be
+// safe for concurrent usage.
+type intner interface {
+	Intn(n int) int
+}
+
+// RNG is a source of uniformly distributed random integers, as implemented by
+// the *Rand type of math/rand/v2 with any of its sources, such as PCG or
+// ChaCha8, as well as by crypto-backed or deterministic generators.
+//
+// Uint64N returns a random number in the half-open interval [0,n), for n > 0.
+type RNG interface {
+	Uint64N(n uint64) uint64
+}
+
+// lockedRNG serializes access to an RNG, which need not itself be safe for
+// concurrent usage.
+type lockedRNG struct {
+	mu sync.Mutex
+	r  RNG
+}
+
+func (l *lockedRNG) Intn(n int) int {
+	l.mu.Lock()
+	v := l.r.Uint64N(uint64(n))
+	l.mu.Unlock()
+	return int(v)
+}
+
+// lockedRand serializes access to a *rand.Rand, which is not itself safe for
+// concurrent usage.
+type lockedRand struct {
+	mu sync.Mutex
+	r  *rand.Rand
+}
+
+func (l *lockedRand) Intn(n int) int {
+	l.mu.Lock()
+	v := l.r.Intn(n)
+	l.mu.Unlock()
+	return v
+}
+
+// shardedRand provides private sources of randomness sharded across a power of
+// two number of slots, at least twice GOMAXPROCS, so that concurrent callers
+// generally do not contend with one another. Each slot is a splitmix64 state,
+// padded to its own cache line and advanced atomically, so callers which do
+// share a slot still draw distinct values without locking.
+//
+// Go offers no cheap way to identify the running processor or goroutine, so
+// callers are assigned slots by the address of their stack, which differs
+// between goroutines and is stable for the life of each one in all but the
+// rare event of stack growth.
+type shardedRand struct {
+	slots []randSlot
+	mask  uint64
+}
+
+// cacheLineSize is large enough to cover the 128 byte cache lines of arm64 and
+// the adjacent line prefetching of amd64.
+const cacheLineSize = 128
+
+type randSlot struct {
+	state uint64 // accessed atomically
+	_     [cacheLineSize - 8]byte
+}
+
+// minStackSize is the minimum size of a goroutine stack, such that the stacks
+// of distinct goroutines differ above this many low bits of their addresses.
+const minStackSize = 2048
+
+func newShardedRand(seed uint64) *shardedRand {
+	n := 1
+	for n < 2*runtime.GOMAXPROCS(0) {
+		n <<= 1
+	}
+	r := &shardedRand{slots: make([]randSlot, n), mask: uint64(n - 1)}
+	sm := splitMix64{state: seed}
+	for i := range r.slots {
+		r.slots[i].state = sm.Uint64()
+	}
+	return r
+}
+
+// Intn returns a non-negative pseudo-random number in the half-open interval
+// [0,n). It panics if n <= 0.
+func (r *shardedRand) Intn(n int) int {
+	if n <= 0 {
+		panic("invalid argument to Intn")
+	}
+	var anchor byte
+	sp := uint64(uintptr(unsafe.Pointer(&anchor))) / minStackSize
+	slot := &r.slots[hash.Mix(sp)&r.mask]
+	x := hash.Mix(atomic.AddUint64(&slot.state, splitMix64Gamma))
+	// Multiply-shift reduction is biased by at most n/2^64, which is
+	// negligible for any int n.
+	hi, _ := bits.Mul64(x, uint64(n))
+	return int(hi)
+}
+
+// randomSeed returns a seed from the system CSPRNG, falling back to the clock
+// in the exceedingly unlikely event that it is unavailable.
+func randomSeed() uint64 {
+	var b [8]byte
+	if _, err := crand.Read(b[:]); err != nil {
+		return uint64(time.Now().UnixNano())
+	}
+	return binary.LittleEndian.Uint64(b[:])
+}
+
+const splitMix64Gamma = 0x9e3779b97f4a7c15
+
+// splitMix64 is a minimal rand.Source64. Unlike the default math/rand source
+// it has only eight bytes of state, so it is cheap to create and seed, which
+// matters since sync.Pool may discard instances at any garbage collection.
+type splitMix64 struct {
+	state uint64
+}
+
+func (s *splitMix64) Uint64() uint64 {
+	s.state += splitMix64Gamma
+	z := s.state
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+func (s *splitMix64) Int63() int64    { return int64(s.Uint64() >> 1) }
+func (s *splitMix64) Seed(seed int64) { s.state = uint64(seed) }
+
+// RNGKind selects a random number generation algorithm from math/rand/v2. When
+// built with toolchains older than Go 1.22, which lack that package, pure Go
+// implementations of the same algorithms are used instead, which produce the
+// same picks for the same seed.
+type RNGKind int
+
+const (
+	// PCG is the PCG-DXSM generator, which is fast and statistically strong,
+	// but predictable from its output.
+	PCG RNGKind = iota + 1
+	// ChaCha8 is the ChaCha8 based generator, which is slower than PCG but
+	// cryptographically strong, so its output cannot be predicted.
+	ChaCha8
+)
+
+// String returns the name of the generator.
+func (k RNGKind) String() string {
+	switch k {
+	case PCG:
+		return "PCG"
+	case ChaCha8:
+		return "ChaCha8"
+	}
+	return "RNGKind(" + strconv.Itoa(int(k)) + ")"
+}
+
+// WithRNG gives the Chooser a generator of its own of the given kind, seeded
+// from the system CSPRNG, so that each deployment can choose between speed and
+// robustness rather than sharing the global source. As with WithRand, access
+// to the generator is serialized. It panics if kind is unknown.
+func WithRNG(kind RNGKind) Option {
+	return WithSeededRNG(kind, randomSeed())
+}
+
+// WithSeededRNG is like WithRNG, but seeds the generator with seed, for a
+// reproducible sequence of picks.
+func WithSeededRNG(kind RNGKind, seed uint64) Option {
+	// Expand the seed to the state of either generator via splitmix64, so
+	// that similar seeds produce unrelated states.
+	sm := splitMix64{state: seed}
+	var r RNG
+	switch kind {
+	case PCG:
+		r = newPCG(sm.Uint64(), sm.Uint64())
+	case ChaCha8:
+		var key [32]byte
+		for i := 0; i < len(key); i += 8 {
+			binary.LittleEndian.PutUint64(key[i:], sm.Uint64())
+		}
+		r = newChaCha8(key)
+	default:
+		panic("weightedrand: unknown " + kind.String())
+	}
+	return WithRand(r)
+}
@@ -0,0 +1,13 @@
+//go:build go1.22
+
+package weightedrand
+
+import randv2 "math/rand/v2"
+
+func newPCG(seed1, seed2 uint64) RNG {
+	return randv2.New(randv2.NewPCG(seed1, seed2))
+}
+
+func newChaCha8(seed [32]byte) RNG {
+	return randv2.New(randv2.NewChaCha8(seed))
+}
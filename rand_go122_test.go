@@ -0,0 +1,20 @@
+//go:build go1.22
+
+package weightedrand
+
+import (
+	"fmt"
+	randv2 "math/rand/v2"
+)
+
+var _ RNG = (*randv2.Rand)(nil)
+
+func ExampleWithRand() {
+	pcg := randv2.New(randv2.NewPCG(1, 2))
+	chooser, _ := NewChooserWithOptions(
+		[]Choice[string, int]{{Item: "seeded", Weight: 1}, {Item: "never", Weight: 0}},
+		WithRand(pcg),
+	)
+	fmt.Println(chooser.Pick())
+	// Output: seeded
+}
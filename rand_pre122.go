@@ -0,0 +1,16 @@
+//go:build !go1.22
+
+package weightedrand
+
+import "github.com/mroth/weightedrand/v2/internal/randv2"
+
+// Toolchains before Go 1.22 lack math/rand/v2, so its generators are provided
+// by an equivalent pure Go implementation.
+
+func newPCG(seed1, seed2 uint64) RNG {
+	return randv2.New(randv2.NewPCG(seed1, seed2))
+}
+
+func newChaCha8(seed [32]byte) RNG {
+	return randv2.New(randv2.NewChaCha8(seed))
+}
@@ -0,0 +1,145 @@
+package weightedrand
+
+import (
+	"reflect"
+	"runtime"
+	"sync"
+	"testing"
+	"unsafe"
+)
+
+// Reference values from the splitmix64.c implementation by Sebastiano Vigna.
+func TestSplitMix64(t *testing.T) {
+	s := splitMix64{state: 0}
+	want := []uint64{0xe220a8397b1dcdaf, 0x6e789e6aa1b965f4, 0x06c45d188009454f}
+	for i, w := range want {
+		if got := s.Uint64(); got != w {
+			t.Errorf("Uint64() #%d = %#x, want %#x", i, got, w)
+		}
+	}
+}
+
+func TestShardedRand_Intn(t *testing.T) {
+	r := newShardedRand(42)
+	for i := 0; i < 1000; i++ {
+		if v := r.Intn(7); v < 0 || v >= 7 {
+			t.Fatalf("Intn(7) = %d, out of range", v)
+		}
+	}
+}
+
+func TestShardedRand_slots(t *testing.T) {
+	r := newShardedRand(42)
+	if n := len(r.slots); n < 2*runtime.GOMAXPROCS(0) || n&(n-1) != 0 {
+		t.Errorf("len(slots) = %d, want a power of two >= 2*GOMAXPROCS", n)
+	}
+	if size := unsafe.Sizeof(randSlot{}); size != cacheLineSize {
+		t.Errorf("slot size = %d, want %d", size, cacheLineSize)
+	}
+
+	// Draws from many goroutines should spread across the slots, and every
+	// value drawn should be distinct, since no two draws share a state.
+	const goroutines, draws = 64, 100
+	var mu sync.Mutex
+	seen := make(map[uint64]bool)
+	var wg sync.WaitGroup
+	wg.Add(goroutines)
+	for g := 0; g < goroutines; g++ {
+		go func() {
+			defer wg.Done()
+			for i := 0; i < draws; i++ {
+				v := uint64(r.Intn(maxInt))
+				mu.Lock()
+				seen[v] = true
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if len(seen) != goroutines*draws {
+		t.Errorf("drew %d distinct values, want %d", len(seen), goroutines*draws)
+	}
+	var used int
+	for i := range r.slots {
+		if r.slots[i].state != newShardedRand(42).slots[i].state {
+			used++
+		}
+	}
+	if len(r.slots) > 1 && used < 2 {
+		t.Errorf("goroutines drew from %d of %d slots", used, len(r.slots))
+	}
+}
+
+func TestWithSeededRNG(t *testing.T) {
+	for _, kind := range []RNGKind{PCG, ChaCha8} {
+		t.Run(kind.String(), func(t *testing.T) {
+			picks := func(seed uint64) []int {
+				choices := make([]Choice[int, int], 100)
+				for i := range choices {
+					choices[i] = NewChoice(i, 1)
+				}
+				c, err := NewChooserWithOptions(choices, WithSeededRNG(kind, seed))
+				if err != nil {
+					t.Fatal(err)
+				}
+				got := make([]int, 20)
+				for i := range got {
+					got[i] = c.Pick()
+				}
+				return got
+			}
+			if a, b := picks(42), picks(42); !reflect.DeepEqual(a, b) {
+				t.Errorf("same seed produced different picks: %v, %v", a, b)
+			}
+			if a, b := picks(42), picks(43); reflect.DeepEqual(a, b) {
+				t.Errorf("different seeds produced the same picks: %v", a)
+			}
+		})
+	}
+}
+
+func TestWithRNG(t *testing.T) {
+	for _, kind := range []RNGKind{PCG, ChaCha8} {
+		t.Run(kind.String(), func(t *testing.T) {
+			choices := mockFrequencyChoices(t, testChoices)
+			c, err := NewChooserWithOptions(choices, WithRNG(kind))
+			if err != nil {
+				t.Fatal(err)
+			}
+			counts := make(map[int]int)
+			for i := 0; i < testIterations; i++ {
+				counts[c.Pick()]++
+			}
+			verifyFrequencyCounts(t, counts, choices)
+		})
+	}
+}
+
+func TestWithRNG_unknown(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("WithRNG(0) did not panic")
+		}
+	}()
+	WithRNG(0)
+}
+
+func TestRNGKind_String(t *testing.T) {
+	for kind, want := range map[RNGKind]string{PCG: "PCG", ChaCha8: "ChaCha8", 7: "RNGKind(7)"} {
+		if got := kind.String(); got != want {
+			t.Errorf("String() = %q, want %q", got, want)
+		}
+	}
+}
+
+func BenchmarkWithRNG(b *testing.B) {
+	for _, kind := range []RNGKind{PCG, ChaCha8} {
+		b.Run(kind.String(), func(b *testing.B) {
+			c, _ := NewChooserWithOptions(mockChoices(BMMinChoices), WithSeededRNG(kind, 1))
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_ = c.Pick()
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package randtext
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/mroth/weightedrand/v2"
+)
+
+func ExampleNewRunes() {
+	dna, _ := NewRunes(
+		weightedrand.NewChoice('A', 3),
+		weightedrand.NewChoice('C', 2),
+		weightedrand.NewChoice('G', 2),
+		weightedrand.NewChoice('T', 3),
+		weightedrand.NewChoice('N', 0),
+	)
+	s := dna.String(12)
+	fmt.Println(len(s), strings.ContainsRune(s, 'N'))
+	//Output: 12 false
+}
+
+func TestRunes_String(t *testing.T) {
+	r, err := NewRunes(
+		weightedrand.NewChoice('a', 1),
+		weightedrand.NewChoice('é', 1),
+		weightedrand.NewChoice('🍉', 2),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := r.String(0); s != "" {
+		t.Errorf("String(0) = %q, want empty", s)
+	}
+	const n = 40000
+	s := r.String(n)
+	if got := utf8.RuneCountInString(s); got != n {
+		t.Fatalf("String(%d) has %d runes", n, got)
+	}
+	if got := float64(strings.Count(s, "🍉")) / n; math.Abs(got-0.5) > 0.02 {
+		t.Errorf("frequency of 🍉 = %.3f, want 0.5", got)
+	}
+
+	if _, err := NewRunes[int](); err == nil {
+		t.Error("NewRunes() with no alphabet should return an error")
+	}
+}
+
+func TestEnglish(t *testing.T) {
+	const n = 100000
+	s := English().String(n)
+	counts := make(map[rune]int)
+	for _, c := range s {
+		if c < 'a' || c > 'z' {
+			t.Fatalf("generated %q outside a-z", c)
+		}
+		counts[c]++
+	}
+	if counts['e'] < counts['t'] || counts['t'] < counts['z'] {
+		t.Errorf("letter counts do not follow English frequencies: e=%d t=%d z=%d", counts['e'], counts['t'], counts['z'])
+	}
+}
+
+func TestBytes(t *testing.T) {
+	b, err := NewBytes(weightedrand.NewChoice(byte(0xff), 1), weightedrand.NewChoice(byte(0), 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := b.Bytes(3); string(got) != "\xff\xff\xff" {
+		t.Errorf("Bytes(3) = %q, want %q", got, "\xff\xff\xff")
+	}
+	got, err := io.ReadAll(io.LimitReader(b, 5))
+	if err != nil || string(got) != "\xff\xff\xff\xff\xff" {
+		t.Errorf("ReadAll() = %q, %v", got, err)
+	}
+
+	buf := make([]byte, 64)
+	allocs := testing.AllocsPerRun(10, func() { b.Read(buf) })
+	if allocs != 0 {
+		t.Errorf("Read allocated %v times per run, want 0", allocs)
+	}
+}
@@ -0,0 +1,22 @@
+package weightedrand
+
+import "io"
+
+// NewReader returns an io.Reader producing an endless stream of bytes drawn
+// independently from the Chooser, such as to generate synthetic data with
+// realistic byte frequencies. Each Read fills its buffer, as if by PickNInto,
+// and never returns an error, so it is typically wrapped in an io.LimitReader.
+//
+// The Reader is safe for concurrent usage, provided buffers are not shared.
+func NewReader[W integer](c *Chooser[byte, W]) io.Reader {
+	return reader[W]{c}
+}
+
+type reader[W integer] struct {
+	c *Chooser[byte, W]
+}
+
+func (r reader[W]) Read(p []byte) (int, error) {
+	r.c.PickNInto(p)
+	return len(p), nil
+}
@@ -0,0 +1,63 @@
+package weightedrand
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"testing"
+)
+
+func TestNewReader(t *testing.T) {
+	chooser, err := NewChooser(
+		NewChoice(byte('a'), 1),
+		NewChoice(byte('b'), 3),
+		NewChoice(byte('z'), 0),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := NewReader(chooser)
+
+	if n, err := r.Read(nil); n != 0 || err != nil {
+		t.Errorf("Read(nil) = %d, %v, want 0, nil", n, err)
+	}
+	data, err := io.ReadAll(io.LimitReader(r, testIterations))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != testIterations {
+		t.Fatalf("read %d bytes, want %d", len(data), testIterations)
+	}
+	if n := bytes.Count(data, []byte("z")); n != 0 {
+		t.Errorf("read zero weight byte %d times", n)
+	}
+	if got := float64(bytes.Count(data, []byte("b"))) / testIterations; got < 0.74 || got > 0.76 {
+		t.Errorf("frequency of b = %.3f, want 0.75", got)
+	}
+}
+
+func BenchmarkReader(b *testing.B) {
+	choices := make([]Choice[byte, int], 256)
+	for i := range choices {
+		choices[i] = NewChoice(byte(i), i+1)
+	}
+	chooser, _ := NewChooser(choices...)
+	r := NewReader(chooser)
+	buf := make([]byte, 4096)
+	b.SetBytes(int64(len(buf)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		r.Read(buf)
+	}
+}
+
+func ExampleNewReader() {
+	chooser, _ := NewChooser(
+		NewChoice(byte('A'), 1),
+		NewChoice(byte('C'), 0),
+	)
+	data, _ := io.ReadAll(io.LimitReader(NewReader(chooser), 8))
+	fmt.Println(string(data))
+	// Output: AAAAAAAA
+}
@@ -0,0 +1,54 @@
+package weightedrand
+
+import (
+	"sort"
+	"sync"
+)
+
+// A Registry is a set of Choosers identified by name, such as one per
+// experiment or tenant, for applications which manage many weighted tables.
+//
+// The zero value is an empty Registry ready to use. A Registry is safe for
+// concurrent usage, and Choosers may be replaced while others are being picked
+// from.
+type Registry[T any, W integer] struct {
+	mu       sync.RWMutex
+	choosers map[string]*Chooser[T, W]
+}
+
+// Get returns the Chooser registered under name, and whether there was one.
+func (r *Registry[T, W]) Get(name string) (*Chooser[T, W], bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	c, ok := r.choosers[name]
+	return c, ok
+}
+
+// Set registers c under name, replacing any existing Chooser of that name.
+func (r *Registry[T, W]) Set(name string, c *Chooser[T, W]) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.choosers == nil {
+		r.choosers = make(map[string]*Chooser[T, W])
+	}
+	r.choosers[name] = c
+}
+
+// Delete removes the Chooser registered under name, if any.
+func (r *Registry[T, W]) Delete(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.choosers, name)
+}
+
+// List returns the names of all registered Choosers, in sorted order.
+func (r *Registry[T, W]) List() []string {
+	r.mu.RLock()
+	names := make([]string, 0, len(r.choosers))
+	for name := range r.choosers {
+		names = append(names, name)
+	}
+	r.mu.RUnlock()
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,79 @@
+package weightedrand
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func ExampleRegistry() {
+	var experiments Registry[string, int]
+	checkout, _ := NewChooser(NewChoice("one-page", 1), NewChoice("multi-page", 0))
+	experiments.Set("checkout", checkout)
+
+	if c, ok := experiments.Get("checkout"); ok {
+		fmt.Println(c.Pick())
+	}
+	fmt.Println(experiments.List())
+	// Output:
+	// one-page
+	// [checkout]
+}
+
+func TestRegistry(t *testing.T) {
+	var r Registry[string, int]
+	if _, ok := r.Get("a"); ok {
+		t.Error("Get() on empty registry reported a chooser")
+	}
+	if got := r.List(); len(got) != 0 {
+		t.Errorf("List() on empty registry = %v", got)
+	}
+	r.Delete("a") // no-op
+
+	a, _ := NewChooser(NewChoice("a", 1))
+	b, _ := NewChooser(NewChoice("b", 1))
+	r.Set("b", b)
+	r.Set("a", a)
+	if got, ok := r.Get("a"); !ok || got != a {
+		t.Errorf("Get(%q) = %p, %v, want %p, true", "a", got, ok, a)
+	}
+	if got, want := r.List(), []string{"a", "b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("List() = %v, want %v", got, want)
+	}
+
+	r.Set("a", b)
+	if got, _ := r.Get("a"); got != b {
+		t.Error("Set() did not replace existing chooser")
+	}
+	r.Delete("a")
+	if _, ok := r.Get("a"); ok {
+		t.Error("Get() reported a deleted chooser")
+	}
+	if got, want := r.List(), []string{"b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("List() = %v, want %v", got, want)
+	}
+}
+
+func TestRegistry_concurrent(t *testing.T) {
+	var r Registry[int, int]
+	c, _ := NewChooser(NewChoice(1, 1))
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				name := strconv.Itoa(g*10 + i%10)
+				r.Set(name, c)
+				if got, ok := r.Get(name); ok {
+					got.Pick()
+				}
+				r.List()
+				r.Delete(name)
+			}
+		}(g)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,219 @@
+// Package reload provides a Chooser whose choices are loaded from an external
+// provider, and which may be refreshed while in use.
+//
+// When a refresh fails or exceeds its deadline, the previously loaded choices
+// continue to be served and the Chooser is marked as stale, so that a flaky
+// configuration backend never takes down the selection path.
+//
+// Refreshes may be triggered by the caller, or made periodically by Run.
+package reload
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mroth/weightedrand/v2"
+	"github.com/mroth/weightedrand/v2/internal/constraints"
+)
+
+// A Provider fetches the current set of choices, e.g. from a file, database or
+// remote configuration service.
+type Provider[T any, W constraints.Integer] func(ctx context.Context) ([]weightedrand.Choice[T, W], error)
+
+// An Option configures a Chooser.
+type Option func(*config)
+
+type config struct {
+	timeout time.Duration
+	maxAge  time.Duration
+	onError func(error)
+	jitter  float64
+}
+
+// WithTimeout bounds the duration of each refresh. If the provider has not
+// returned by the deadline, the refresh fails with context.DeadlineExceeded
+// and the previous choices continue to be served, even if the provider does
+// not itself respect context cancellation.
+func WithTimeout(d time.Duration) Option {
+	return func(cfg *config) { cfg.timeout = d }
+}
+
+// WithMaxAge causes the Chooser to report itself as Stale once d has elapsed
+// since the last successful refresh, regardless of whether any refresh has
+// since failed.
+func WithMaxAge(d time.Duration) Option {
+	return func(cfg *config) { cfg.maxAge = d }
+}
+
+// WithErrorHandler registers fn to be called whenever a refresh fails and the
+// previous choices are retained, e.g. for logging or alerting.
+func WithErrorHandler(fn func(error)) Option {
+	return func(cfg *config) { cfg.onError = fn }
+}
+
+// WithJitter varies each interval between the refreshes made by Run randomly by
+// up to factor times the interval in either direction, so that instances of a
+// service started together do not all refresh at once. Factor is clamped to
+// [0,1].
+func WithJitter(factor float64) Option {
+	return func(cfg *config) { cfg.jitter = math.Max(0, math.Min(factor, 1)) }
+}
+
+// Chooser is a weighted random chooser backed by a Provider. It is safe for
+// concurrent usage, including calling Pick concurrently with Refresh.
+type Chooser[T any, W constraints.Integer] struct {
+	provider Provider[T, W]
+	cfg      config
+	current  atomic.Value // *weightedrand.Chooser[T, W]
+
+	refreshing sync.Mutex // serializes refreshes
+
+	mu          sync.Mutex // guards fields below, never held while loading
+	lastRefresh time.Time
+	lastErr     error
+}
+
+// New initializes a Chooser with the choices returned by provider. Since there
+// are no previous choices to fall back to, any failure of this initial load is
+// returned as an error.
+func New[T any, W constraints.Integer](ctx context.Context, provider Provider[T, W], opts ...Option) (*Chooser[T, W], error) {
+	c := &Chooser[T, W]{provider: provider}
+	for _, opt := range opts {
+		opt(&c.cfg)
+	}
+
+	chs, err := c.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.current.Store(chs)
+	c.lastRefresh = time.Now()
+	return c, nil
+}
+
+// Pick returns a single weighted random item from the most recently loaded
+// choices.
+func (c *Chooser[T, W]) Pick() T {
+	return c.current.Load().(*weightedrand.Chooser[T, W]).Pick()
+}
+
+// Refresh reloads the choices from the provider. On failure the previous
+// choices are retained, the Chooser becomes Stale, and the error is passed to
+// any registered error handler before being returned.
+func (c *Chooser[T, W]) Refresh(ctx context.Context) error {
+	c.refreshing.Lock()
+	defer c.refreshing.Unlock()
+
+	chs, err := c.load(ctx)
+
+	c.mu.Lock()
+	c.lastErr = err
+	if err == nil {
+		c.current.Store(chs)
+		c.lastRefresh = time.Now()
+	}
+	c.mu.Unlock()
+
+	if err != nil && c.cfg.onError != nil {
+		c.cfg.onError(err)
+	}
+	return err
+}
+
+// Run refreshes the Chooser every interval, as varied by any configured jitter,
+// until ctx is done, and then returns the context's error. Failed refreshes are
+// reported by Err, Stale and any registered error handler, and do not stop Run,
+// so it is usually started in its own goroutine for the life of the service.
+// It panics if interval is not positive.
+func (c *Chooser[T, W]) Run(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		panic("reload: non-positive interval for Run")
+	}
+	t := time.NewTimer(c.cfg.jittered(interval))
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+			_ = c.Refresh(ctx) // reported by Err, Stale and any error handler
+			t.Reset(c.cfg.jittered(interval))
+		}
+	}
+}
+
+// jittered returns d varied uniformly at random by up to the jitter factor.
+func (cfg *config) jittered(d time.Duration) time.Duration {
+	if cfg.jitter == 0 {
+		return d
+	}
+	spread := cfg.jitter * float64(d)
+	return d + time.Duration(spread*(2*rand.Float64()-1))
+}
+
+// LastRefresh returns the time of the last successful load of choices.
+func (c *Chooser[T, W]) LastRefresh() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lastRefresh
+}
+
+// Err returns the error from the most recent refresh, or nil if it succeeded.
+func (c *Chooser[T, W]) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lastErr
+}
+
+// Stale reports whether the Chooser is serving outdated choices, either because
+// the most recent refresh failed, or because the configured maximum age has
+// elapsed since the last successful refresh.
+func (c *Chooser[T, W]) Stale() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.lastErr != nil {
+		return true
+	}
+	return c.cfg.maxAge > 0 && time.Since(c.lastRefresh) > c.cfg.maxAge
+}
+
+type loadResult[T any, W constraints.Integer] struct {
+	chooser *weightedrand.Chooser[T, W]
+	err     error
+}
+
+// load fetches and builds a new chooser, abandoning the provider if it does not
+// return before any configured deadline.
+func (c *Chooser[T, W]) load(ctx context.Context) (*weightedrand.Chooser[T, W], error) {
+	if c.cfg.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.cfg.timeout)
+		defer cancel()
+	}
+
+	done := make(chan loadResult[T, W], 1) // buffered so an abandoned load can exit
+	go func() {
+		choices, err := c.provider(ctx)
+		if err != nil {
+			done <- loadResult[T, W]{err: err}
+			return
+		}
+		chs, err := weightedrand.NewChooser(choices...)
+		if err != nil {
+			err = fmt.Errorf("reload: invalid choices: %w", err)
+		}
+		done <- loadResult[T, W]{chooser: chs, err: err}
+	}()
+
+	select {
+	case res := <-done:
+		return res.chooser, res.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
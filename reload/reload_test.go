@@ -0,0 +1,248 @@
+package reload
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mroth/weightedrand/v2"
+)
+
+// staticProvider returns a Provider which always returns a single choice of
+// item, or err if non-nil.
+func staticProvider(item string, err error) Provider[string, int] {
+	return func(ctx context.Context) ([]weightedrand.Choice[string, int], error) {
+		if err != nil {
+			return nil, err
+		}
+		return []weightedrand.Choice[string, int]{weightedrand.NewChoice(item, 1)}, nil
+	}
+}
+
+func TestNew(t *testing.T) {
+	ctx := context.Background()
+	if _, err := New(ctx, staticProvider("", errors.New("boom"))); err == nil {
+		t.Error("expected error from failing initial load")
+	}
+	empty := func(ctx context.Context) ([]weightedrand.Choice[string, int], error) { return nil, nil }
+	if _, err := New(ctx, empty); err == nil {
+		t.Error("expected error from initial load with no valid choices")
+	}
+
+	c, err := New(ctx, staticProvider("a", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := c.Pick(); got != "a" {
+		t.Errorf("Pick() = %q, want %q", got, "a")
+	}
+	if c.Stale() {
+		t.Error("freshly loaded chooser reported as stale")
+	}
+	if c.LastRefresh().IsZero() {
+		t.Error("LastRefresh() not set by initial load")
+	}
+}
+
+func TestChooser_Refresh(t *testing.T) {
+	ctx := context.Background()
+	var item string
+	var fail error
+	provider := func(ctx context.Context) ([]weightedrand.Choice[string, int], error) {
+		return staticProvider(item, fail)(ctx)
+	}
+	var handled []error
+	c, err := New(ctx, provider, WithErrorHandler(func(err error) { handled = append(handled, err) }))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	item = "b"
+	if err := c.Refresh(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.Pick(); got != "b" {
+		t.Errorf("Pick() = %q after refresh, want %q", got, "b")
+	}
+	last := c.LastRefresh()
+
+	item, fail = "c", errors.New("boom")
+	if err := c.Refresh(ctx); err != fail {
+		t.Errorf("Refresh() error = %v, want %v", err, fail)
+	}
+	if got := c.Pick(); got != "b" {
+		t.Errorf("Pick() = %q after failed refresh, want previous %q", got, "b")
+	}
+	if !c.Stale() || c.Err() != fail {
+		t.Errorf("Stale() = %v, Err() = %v after failed refresh", c.Stale(), c.Err())
+	}
+	if !c.LastRefresh().Equal(last) {
+		t.Error("LastRefresh() changed by failed refresh")
+	}
+	if len(handled) != 1 || handled[0] != fail {
+		t.Errorf("error handler called with %v, want [%v]", handled, fail)
+	}
+
+	fail = nil
+	if err := c.Refresh(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if c.Stale() {
+		t.Error("chooser still stale after successful refresh")
+	}
+}
+
+func TestChooser_statusDuringRefresh(t *testing.T) {
+	ctx := context.Background()
+	block := make(chan struct{})
+	started := make(chan struct{})
+	first := true
+	provider := func(ctx context.Context) ([]weightedrand.Choice[string, int], error) {
+		if !first {
+			close(started)
+			<-block // a provider hanging without any timeout
+		}
+		first = false
+		return staticProvider("a", nil)(ctx)
+	}
+	c, err := New(ctx, provider)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan error)
+	go func() { done <- c.Refresh(ctx) }()
+	<-started
+
+	status := make(chan struct{})
+	go func() {
+		c.Stale()
+		c.Err()
+		c.LastRefresh()
+		close(status)
+	}()
+	select {
+	case <-status:
+	case <-time.After(5 * time.Second):
+		t.Fatal("status methods blocked behind a slow refresh")
+	}
+	close(block)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWithTimeout(t *testing.T) {
+	ctx := context.Background()
+	block := make(chan struct{})
+	defer close(block)
+	first := true
+	provider := func(ctx context.Context) ([]weightedrand.Choice[string, int], error) {
+		if !first {
+			<-block // deliberately ignores ctx
+		}
+		first = false
+		return staticProvider("a", nil)(ctx)
+	}
+
+	c, err := New(ctx, provider, WithTimeout(10*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Refresh(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Refresh() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if got := c.Pick(); got != "a" {
+		t.Errorf("Pick() = %q after timed out refresh, want previous %q", got, "a")
+	}
+}
+
+func TestWithMaxAge(t *testing.T) {
+	c, err := New(context.Background(), staticProvider("a", nil), WithMaxAge(time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(5 * time.Millisecond)
+	if !c.Stale() {
+		t.Error("expected chooser to be stale after exceeding max age")
+	}
+	if c.Err() != nil {
+		t.Errorf("Err() = %v, want nil", c.Err())
+	}
+}
+
+func TestChooser_Run(t *testing.T) {
+	var mu sync.Mutex
+	loads := 0
+	provider := func(ctx context.Context) ([]weightedrand.Choice[string, int], error) {
+		mu.Lock()
+		defer mu.Unlock()
+		loads++
+		if loads > 2 {
+			return nil, errors.New("boom")
+		}
+		return staticProvider(fmt.Sprint(loads), nil)(ctx)
+	}
+	errs := make(chan error, 1)
+	onError := func(err error) {
+		select {
+		case errs <- err:
+		default:
+		}
+	}
+	c, err := New(context.Background(), provider, WithJitter(0.5), WithErrorHandler(onError))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- c.Run(ctx, time.Millisecond) }()
+
+	// The second load succeeds and the third fails, without stopping Run.
+	select {
+	case <-errs:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for periodic refreshes")
+	}
+	if got := c.Pick(); got != "2" {
+		t.Errorf("Pick() = %q after failed refresh, want previous %q", got, "2")
+	}
+	if !c.Stale() {
+		t.Error("expected chooser to be stale after failed refresh")
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("Run() = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestWithJitter(t *testing.T) {
+	const d = time.Second
+	for _, factor := range []float64{-1, 0, 0.1, 1, 2} {
+		var cfg config
+		WithJitter(factor)(&cfg)
+		spread := time.Duration(math.Max(0, math.Min(factor, 1)) * float64(d))
+		lo, hi := d, d
+		for i := 0; i < 1000; i++ {
+			got := cfg.jittered(d)
+			if got < d-spread || got > d+spread {
+				t.Fatalf("WithJitter(%v): jittered(%v) = %v, out of range", factor, d, got)
+			}
+			if got < lo {
+				lo = got
+			}
+			if got > hi {
+				hi = got
+			}
+		}
+		if spread > 0 && hi-lo < spread {
+			t.Errorf("WithJitter(%v): intervals spanned only %v", factor, hi-lo)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+// Package rendezvous implements weighted rendezvous hashing, also known as
+// highest random weight (HRW) hashing, for consistently mapping keys to
+// weighted nodes such as cache shards.
+//
+// Each key is assigned to the node with the highest score, where the score of
+// a node for a key is derived from a hash of both and scaled by the node's
+// weight, such that each node receives a fraction of keys proportional to its
+// weight. Since the score of a node for a key does not depend on any other
+// node, adding, removing or reweighting a node only remaps the minimal
+// fraction of keys: those moving to or from that node.
+//
+// Picking is O(n) in the number of nodes, so it is best suited to tens or
+// hundreds of nodes rather than millions.
+package rendezvous
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/mroth/weightedrand/v2"
+	"github.com/mroth/weightedrand/v2/internal/constraints"
+	"github.com/mroth/weightedrand/v2/internal/hash"
+)
+
+// ErrNoValidNodes is returned by New when no nodes have a weight >= 1.
+var ErrNoValidNodes = errors.New("rendezvous: zero nodes with weight >= 1")
+
+// A Hash consistently maps keys to weighted nodes. It is immutable and safe for
+// concurrent usage; to change its nodes, construct a new Hash.
+type Hash[T any] struct {
+	nodes   []T
+	hashes  []uint64
+	weights []float64
+}
+
+// New initializes a Hash over the provided nodes. Nodes are identified by their
+// formatting with fmt.Sprint, which must be distinct for each and stable for
+// keys to be consistently assigned across processes. Nodes with a weight < 1
+// are ignored.
+func New[T any, W constraints.Integer](nodes ...weightedrand.Choice[T, W]) (*Hash[T], error) {
+	h := &Hash[T]{}
+	for _, n := range nodes {
+		if n.Weight < 1 {
+			continue
+		}
+		h.nodes = append(h.nodes, n.Item)
+		h.hashes = append(h.hashes, hash.String(fmt.Sprint(n.Item)))
+		h.weights = append(h.weights, float64(n.Weight))
+	}
+	if len(h.nodes) == 0 {
+		return nil, ErrNoValidNodes
+	}
+	return h, nil
+}
+
+// Pick returns the node assigned to key.
+func (h *Hash[T]) Pick(key string) T {
+	k := hash.String(key)
+	best, bestScore := 0, math.Inf(-1)
+	for i, w := range h.weights {
+		if s := score(k, h.hashes[i], w); s > bestScore {
+			best, bestScore = i, s
+		}
+	}
+	return h.nodes[best]
+}
+
+// score returns the weighted score of the node with hash node for the key with
+// hash key: -w / ln(u), for u uniform in (0,1) derived from both hashes. This is
+// the logarithmic method of Schindelhauer and Schomaker, under which the node
+// with the highest score is chosen with probability proportional to w.
+func score(key, node uint64, w float64) float64 {
+	u := (float64(hash.Mix(key^node)>>11) + 0.5) / (1 << 53)
+	return -w / math.Log(u)
+}
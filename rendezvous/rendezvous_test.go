@@ -0,0 +1,115 @@
+package rendezvous
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"testing"
+
+	"github.com/mroth/weightedrand/v2"
+)
+
+func ExampleHash_Pick() {
+	shards, _ := New(
+		weightedrand.NewChoice("cache-a", 1),
+		weightedrand.NewChoice("cache-b", 2),
+	)
+	shard := shards.Pick("user:1234")
+	fmt.Println(shard == shards.Pick("user:1234"))
+	//Output: true
+}
+
+func TestNew(t *testing.T) {
+	if _, err := New[string, int](); err != ErrNoValidNodes {
+		t.Errorf("New() error = %v, want %v", err, ErrNoValidNodes)
+	}
+	if _, err := New(weightedrand.NewChoice("a", 0), weightedrand.NewChoice("b", -1)); err != ErrNoValidNodes {
+		t.Errorf("New() error = %v, want %v", err, ErrNoValidNodes)
+	}
+}
+
+const numKeys = 100_000
+
+func assign(h *Hash[string]) []string {
+	nodes := make([]string, numKeys)
+	for i := range nodes {
+		nodes[i] = h.Pick("key-" + strconv.Itoa(i))
+	}
+	return nodes
+}
+
+func TestHash_Pick_distribution(t *testing.T) {
+	h, _ := New(
+		weightedrand.NewChoice("a", 1),
+		weightedrand.NewChoice("b", 2),
+		weightedrand.NewChoice("c", 5),
+		weightedrand.NewChoice("zero", 0),
+	)
+	counts := make(map[string]int)
+	for _, n := range assign(h) {
+		counts[n]++
+	}
+	for node, weight := range map[string]float64{"a": 1, "b": 2, "c": 5} {
+		want := weight / 8
+		if got := float64(counts[node]) / numKeys; math.Abs(got-want) > 0.01 {
+			t.Errorf("fraction of keys on %s = %.3f, want %.3f", node, got, want)
+		}
+	}
+}
+
+// Changing one node should only move keys to or from that node.
+func TestHash_Pick_minimalRemapping(t *testing.T) {
+	base := []weightedrand.Choice[string, int]{
+		weightedrand.NewChoice("a", 1),
+		weightedrand.NewChoice("b", 1),
+		weightedrand.NewChoice("c", 2),
+	}
+	h, _ := New(base...)
+	before := assign(h)
+
+	tests := []struct {
+		name    string
+		nodes   []weightedrand.Choice[string, int]
+		changed string
+		moved   float64 // expected fraction of keys moved
+	}{
+		{name: "add", nodes: append(base[:3:3], weightedrand.NewChoice("d", 4)), changed: "d", moved: 0.5},
+		{name: "remove", nodes: base[:2], changed: "c", moved: 0.5},
+		{name: "reweight", nodes: []weightedrand.Choice[string, int]{base[0], base[1], weightedrand.NewChoice("c", 6)}, changed: "c", moved: 6.0/8 - 2.0/4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h2, _ := New(tt.nodes...)
+			after := assign(h2)
+			moved := 0
+			for i := range before {
+				if before[i] == after[i] {
+					continue
+				}
+				moved++
+				if before[i] != tt.changed && after[i] != tt.changed {
+					t.Fatalf("key %d moved from %s to %s, neither of which changed", i, before[i], after[i])
+				}
+			}
+			if got := float64(moved) / numKeys; math.Abs(got-tt.moved) > 0.01 {
+				t.Errorf("fraction of keys moved = %.3f, want %.3f", got, tt.moved)
+			}
+		})
+	}
+}
+
+func BenchmarkHash_Pick(b *testing.B) {
+	for _, n := range []int{10, 100} {
+		b.Run(fmt.Sprintf("nodes=%d", n), func(b *testing.B) {
+			nodes := make([]weightedrand.Choice[int, int], n)
+			for i := range nodes {
+				nodes[i] = weightedrand.NewChoice(i, i+1)
+			}
+			h, _ := New(nodes...)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_ = h.Pick("user:1234")
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package weightedrand
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// A Reservoir maintains a weighted random sample of up to k items, without
+// replacement, from a stream of items of unknown length, without holding the
+// stream in memory.
+//
+// Like WeightedTopK, it assigns each item a random key of u^(1/weight) and
+// keeps the k largest, such that at any point its items are a weighted random
+// sample of all those offered so far. Its storage grows with the sample up to
+// k items, and is retained by Reset, so once full, or when reused, offering
+// items never allocates.
+//
+// Utilizes global rand as the source of randomness. A Reservoir is not safe
+// for concurrent usage.
+type Reservoir[T any] struct {
+	k    int
+	heap []reservoirEntry[T] // min-heap of keys
+}
+
+type reservoirEntry[T any] struct {
+	key  float64
+	item T
+}
+
+// NewReservoir returns an empty Reservoir holding up to k items. If k <= 0,
+// it never holds any.
+func NewReservoir[T any](k int) *Reservoir[T] {
+	if k < 0 {
+		k = 0
+	}
+	c := k
+	if c > maxInitialReservoir {
+		c = maxInitialReservoir
+	}
+	return &Reservoir[T]{k: k, heap: make([]reservoirEntry[T], 0, c)}
+}
+
+// maxInitialReservoir caps the storage allocated by NewReservoir, so that a
+// large k, such as from user input, costs memory only as items are offered.
+const maxInitialReservoir = 1024
+
+// Offer adds item to the sample with probability according to its weight,
+// displacing another if the Reservoir is full. Items with a weight which is
+// not positive are never included.
+func (r *Reservoir[T]) Offer(item T, weight float64) {
+	if !(weight > 0) || r.k == 0 {
+		return
+	}
+	// Compare keys in log space, log(u^(1/w)) = log(u)/w, for precision with
+	// large weights. u is in (0, 1] so that the log is finite.
+	key := math.Log(1-rand.Float64()) / weight
+	if len(r.heap) < r.k {
+		r.heap = append(r.heap, reservoirEntry[T]{key: key, item: item})
+		r.up(len(r.heap) - 1)
+	} else if key > r.heap[0].key {
+		r.heap[0] = reservoirEntry[T]{key: key, item: item}
+		r.down(0)
+	}
+}
+
+// Len returns the number of items in the sample, which is k once at least k
+// items with a positive weight have been offered.
+func (r *Reservoir[T]) Len() int {
+	return len(r.heap)
+}
+
+// Items returns the items of the sample in the order they would have been
+// selected one at a time, as by WeightedTopK.
+func (r *Reservoir[T]) Items() []T {
+	entries := make([]reservoirEntry[T], len(r.heap))
+	copy(entries, r.heap)
+	sort.Slice(entries, func(i, j int) bool { return entries[i].key > entries[j].key })
+	items := make([]T, len(entries))
+	for i, e := range entries {
+		items[i] = e.item
+	}
+	return items
+}
+
+// Reset empties the Reservoir for reuse, retaining its storage.
+func (r *Reservoir[T]) Reset() {
+	var zero reservoirEntry[T]
+	for i := range r.heap {
+		r.heap[i] = zero // do not retain items
+	}
+	r.heap = r.heap[:0]
+}
+
+// up and down restore the heap invariant after the entry at i was added or
+// replaced. They are written out, rather than using container/heap, whose Push
+// would allocate to box each entry.
+func (r *Reservoir[T]) up(i int) {
+	h := r.heap
+	for i > 0 {
+		parent := (i - 1) / 2
+		if h[parent].key <= h[i].key {
+			break
+		}
+		h[parent], h[i] = h[i], h[parent]
+		i = parent
+	}
+}
+
+func (r *Reservoir[T]) down(i int) {
+	h := r.heap
+	for {
+		least := i
+		if c := 2*i + 1; c < len(h) && h[c].key < h[least].key {
+			least = c
+		}
+		if c := 2*i + 2; c < len(h) && h[c].key < h[least].key {
+			least = c
+		}
+		if least == i {
+			return
+		}
+		h[i], h[least] = h[least], h[i]
+		i = least
+	}
+}
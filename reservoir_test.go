@@ -0,0 +1,111 @@
+package weightedrand
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func ExampleReservoir() {
+	r := NewReservoir[string](2)
+	for _, c := range []Choice[string, int]{
+		{Item: "never", Weight: 0},
+		{Item: "always", Weight: 1_000_000_000},
+		{Item: "sometimes", Weight: 1},
+	} {
+		r.Offer(c.Item, float64(c.Weight))
+	}
+	fmt.Println(r.Items())
+	//Output: [always sometimes]
+}
+
+func TestReservoir(t *testing.T) {
+	empty := NewReservoir[int](-1)
+	if empty.Offer(1, 1); empty.Len() != 0 {
+		t.Errorf("NewReservoir(-1) holds %d items, want 0", empty.Len())
+	}
+
+	r := NewReservoir[int](10)
+	for i, w := range []float64{1, 2, 0, -1, math.NaN(), 3} {
+		r.Offer(i, w)
+	}
+	if got := r.Len(); got != 3 {
+		t.Errorf("Len() = %d, want 3 items with a positive weight", got)
+	}
+	r.Reset()
+	if got := r.Items(); len(got) != 0 {
+		t.Errorf("Items() after Reset = %v, want empty", got)
+	}
+
+	// As with WeightedTopK, the first item is a single weighted pick, and the
+	// sample includes each item with the probability of its inclusion in a
+	// weighted sample without replacement.
+	weights := []float64{1, 2, 3, 4}
+	const n = 100000
+	first := make([]int, len(weights))
+	included := make([]int, len(weights))
+	r = NewReservoir[int](2)
+	for i := 0; i < n; i++ {
+		r.Reset()
+		for item, w := range weights {
+			r.Offer(item, w)
+		}
+		got := r.Items()
+		if len(got) != 2 || got[0] == got[1] {
+			t.Fatalf("Items() = %v", got)
+		}
+		first[got[0]]++
+		included[got[0]]++
+		included[got[1]]++
+	}
+	for i, w := range weights {
+		if got, want := float64(first[i])/n, w/10; math.Abs(got-want) > 0.01 {
+			t.Errorf("first item %d frequency = %v, want %v", i, got, want)
+		}
+	}
+	// P(i included) = P(i first) + Σ_j P(j first)·P(i second | j first).
+	for i, wi := range weights {
+		want := wi / 10
+		for j, wj := range weights {
+			if j != i {
+				want += wj / 10 * wi / (10 - wj)
+			}
+		}
+		if got := float64(included[i]) / n; math.Abs(got-want) > 0.01 {
+			t.Errorf("item %d inclusion frequency = %v, want %v", i, got, want)
+		}
+	}
+}
+
+func TestReservoir_allocs(t *testing.T) {
+	r := NewReservoir[string](16)
+	allocs := testing.AllocsPerRun(100, func() {
+		for i := 0; i < 100; i++ {
+			r.Offer("item", float64(i+1))
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("Offer allocated %v times, want 0", allocs)
+	}
+}
+
+func TestNewReservoir_large(t *testing.T) {
+	r := NewReservoir[int](1 << 40)
+	if c := cap(r.heap); c > maxInitialReservoir {
+		t.Errorf("NewReservoir(1<<40) preallocated %d entries", c)
+	}
+	for i := 0; i < 3; i++ {
+		r.Offer(i, 1)
+	}
+	if got := r.Len(); got != 3 {
+		t.Errorf("Len() = %d, want 3", got)
+	}
+}
+
+func BenchmarkReservoir_Offer(b *testing.B) {
+	r := NewReservoir[int](5)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		r.Offer(i, float64(i%100+1))
+	}
+}
@@ -0,0 +1,177 @@
+// Package rollout provides feature flags whose variants are assigned to users
+// by weight, with the weights following a schedule so that a new variant can
+// be ramped up gradually over time.
+//
+// Assignment is deterministic for each user key, using rendezvous hashing, so
+// a user keeps their variant across requests and processes. As a ramp shifts
+// weight from one variant to another, users only ever move in the direction of
+// the ramp: once a user is assigned the new variant, they keep it.
+package rollout
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"github.com/mroth/weightedrand/v2"
+	"github.com/mroth/weightedrand/v2/rendezvous"
+)
+
+// Possible errors returned by New.
+var (
+	ErrNoSteps        = errors.New("rollout: flag has no steps")
+	ErrNegativeWeight = errors.New("rollout: negative weight")
+)
+
+// RampResolution is the interval at which the weights of a ramping Step are
+// recomputed.
+const RampResolution = time.Second
+
+// A Step sets the weights of a flag's variants from a point in time.
+type Step struct {
+	// At is the time from which the step's weights apply.
+	At time.Time
+	// Weights maps the names of variants to their weights. Variants which
+	// are absent have a weight of zero.
+	Weights map[string]int
+	// Ramp, if true, makes the weights change linearly over time from those
+	// of the previous step, reaching the step's weights at At, rather than
+	// changing all at once.
+	Ramp bool
+}
+
+// A Flag assigns users to variants by the weights of its current Step. It is
+// safe for concurrent usage.
+type Flag struct {
+	name  string
+	steps []Step
+	cache atomic.Value // *snapshot
+}
+
+// snapshot is a Flag's assignment in effect over an interval of time.
+type snapshot struct {
+	from, until time.Time
+	weights     map[string]int
+	hash        *rendezvous.Hash[string] // nil if no variant has a weight >= 1
+}
+
+// New initializes a Flag with the given steps, in any order. The flag's name
+// is hashed along with user keys, so that different flags assign users
+// independently.
+func New(name string, steps ...Step) (*Flag, error) {
+	if len(steps) == 0 {
+		return nil, ErrNoSteps
+	}
+	f := &Flag{name: name, steps: make([]Step, len(steps))}
+	for i, s := range steps {
+		weights := make(map[string]int, len(s.Weights))
+		for v, w := range s.Weights {
+			if w < 0 {
+				return nil, ErrNegativeWeight
+			}
+			weights[v] = w
+		}
+		s.Weights = weights
+		f.steps[i] = s
+	}
+	sort.SliceStable(f.steps, func(i, j int) bool {
+		return f.steps[i].At.Before(f.steps[j].At)
+	})
+	return f, nil
+}
+
+// Name returns the name of the flag.
+func (f *Flag) Name() string { return f.name }
+
+// Evaluate returns the variant currently assigned to the user with key, or
+// false if the flag has no variants with a weight >= 1, such as before its
+// first step.
+func (f *Flag) Evaluate(key string) (string, bool) {
+	return f.EvaluateAt(key, time.Now())
+}
+
+// EvaluateAt is like Evaluate, but returns the variant assigned at time t, such
+// as to preview the progress of a ramp.
+func (f *Flag) EvaluateAt(key string, t time.Time) (string, bool) {
+	s := f.snapshotAt(t)
+	if s.hash == nil {
+		return "", false
+	}
+	return s.hash.Pick(f.name + "\x00" + key), true
+}
+
+// WeightsAt returns the weights of the flag's variants at time t, including
+// those with a weight of zero.
+func (f *Flag) WeightsAt(t time.Time) map[string]int {
+	s := f.snapshotAt(t)
+	weights := make(map[string]int, len(s.weights))
+	for v, w := range s.weights {
+		weights[v] = w
+	}
+	return weights
+}
+
+// snapshotAt returns the snapshot in effect at t, reusing the cached one if it
+// covers t.
+func (f *Flag) snapshotAt(t time.Time) *snapshot {
+	if s, ok := f.cache.Load().(*snapshot); ok && !t.Before(s.from) && t.Before(s.until) {
+		return s
+	}
+	s := f.build(t)
+	f.cache.Store(s)
+	return s
+}
+
+// farFuture bounds snapshots which never expire.
+var farFuture = time.Unix(1<<62, 0)
+
+// build computes the snapshot in effect at t.
+func (f *Flag) build(t time.Time) *snapshot {
+	i := sort.Search(len(f.steps), func(i int) bool { return f.steps[i].At.After(t) }) - 1
+	s := &snapshot{from: time.Unix(-1<<62, 0), until: farFuture, weights: map[string]int{}}
+	if i >= 0 {
+		s.from = f.steps[i].At
+	}
+	if i+1 < len(f.steps) {
+		s.until = f.steps[i+1].At
+	}
+
+	switch {
+	case i < 0:
+		return s // before the first step
+	case i+1 < len(f.steps) && f.steps[i+1].Ramp:
+		prev, next := f.steps[i], f.steps[i+1]
+		// Hold the weights for the resolution bucket containing t.
+		elapsed := t.Sub(prev.At) / RampResolution * RampResolution
+		s.from = prev.At.Add(elapsed)
+		if until := s.from.Add(RampResolution); until.Before(s.until) {
+			s.until = until
+		}
+		frac := float64(elapsed) / float64(next.At.Sub(prev.At))
+		for v, w := range prev.Weights {
+			s.weights[v] = w
+		}
+		for v := range next.Weights {
+			if _, ok := s.weights[v]; !ok {
+				s.weights[v] = 0
+			}
+		}
+		for v, w := range s.weights {
+			target := float64(next.Weights[v])
+			s.weights[v] = int(math.Round(float64(w) + (target-float64(w))*frac))
+		}
+	default:
+		for v, w := range f.steps[i].Weights {
+			s.weights[v] = w
+		}
+	}
+
+	choices := make([]weightedrand.Choice[string, int], 0, len(s.weights))
+	for v, w := range s.weights {
+		choices = append(choices, weightedrand.NewChoice(v, w))
+	}
+	s.hash, _ = rendezvous.New(choices...) // nil if no weights >= 1
+	return s
+}
@@ -0,0 +1,131 @@
+package rollout
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+)
+
+var epoch = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestNew(t *testing.T) {
+	if _, err := New("f"); !errors.Is(err, ErrNoSteps) {
+		t.Errorf("New() error = %v, want %v", err, ErrNoSteps)
+	}
+	_, err := New("f", Step{Weights: map[string]int{"a": -1}})
+	if !errors.Is(err, ErrNegativeWeight) {
+		t.Errorf("New() error = %v, want %v", err, ErrNegativeWeight)
+	}
+}
+
+func TestFlag_WeightsAt(t *testing.T) {
+	f, err := New("f",
+		// out of order, to check steps are sorted
+		Step{At: epoch.Add(2 * time.Hour), Weights: map[string]int{"on": 100}, Ramp: true},
+		Step{At: epoch, Weights: map[string]int{"off": 100}},
+		Step{At: epoch.Add(3 * time.Hour), Weights: map[string]int{"off": 1}},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		at   time.Duration
+		want string
+	}{
+		{at: -time.Second, want: "map[]"},
+		{at: 0, want: "map[off:100 on:0]"},
+		{at: 30 * time.Minute, want: "map[off:75 on:25]"},
+		{at: 90*time.Minute + 500*time.Millisecond, want: "map[off:25 on:75]"},
+		{at: 2 * time.Hour, want: "map[on:100]"},
+		{at: 3 * time.Hour, want: "map[off:1]"},
+		{at: 1000 * time.Hour, want: "map[off:1]"},
+	}
+	for _, tt := range tests {
+		if got := fmt.Sprint(f.WeightsAt(epoch.Add(tt.at))); got != tt.want {
+			t.Errorf("WeightsAt(%v) = %s, want %s", tt.at, got, tt.want)
+		}
+	}
+}
+
+func TestFlag_EvaluateAt(t *testing.T) {
+	f, err := New("f",
+		Step{At: epoch, Weights: map[string]int{"control": 100}},
+		Step{At: epoch.Add(100 * time.Second), Weights: map[string]int{"control": 0, "treatment": 100}, Ramp: true},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := f.EvaluateAt("user", epoch.Add(-time.Second)); ok {
+		t.Errorf("EvaluateAt() before first step = %q, want none", v)
+	}
+
+	// Over the ramp, the share of users with the treatment grows with its
+	// weight, and no user ever moves back to the control.
+	const users = 10000
+	treated := make([]bool, users)
+	for s := 0; s <= 100; s += 10 {
+		at := epoch.Add(time.Duration(s) * time.Second)
+		n := 0
+		for u := range treated {
+			v, ok := f.EvaluateAt(fmt.Sprint("user", u), at)
+			if !ok {
+				t.Fatalf("EvaluateAt() at %ds = none", s)
+			}
+			if treated[u] && v != "treatment" {
+				t.Fatalf("user%d moved back to %s at %ds", u, v, s)
+			}
+			treated[u] = v == "treatment"
+			if treated[u] {
+				n++
+			}
+		}
+		if got, want := float64(n)/users, float64(s)/100; math.Abs(got-want) > 0.02 {
+			t.Errorf("treatment share at %ds = %.3f, want %.2f", s, got, want)
+		}
+	}
+}
+
+func TestFlag_independent(t *testing.T) {
+	weights := map[string]int{"a": 1, "b": 1}
+	f, _ := New("f", Step{Weights: weights})
+	g, _ := New("g", Step{Weights: weights})
+	same := 0
+	for u := 0; u < 1000; u++ {
+		key := fmt.Sprint("user", u)
+		fv, _ := f.Evaluate(key)
+		gv, _ := g.Evaluate(key)
+		if fv == gv {
+			same++
+		}
+	}
+	if same < 400 || same > 600 {
+		t.Errorf("flags agreed for %d of 1000 users, want about 500", same)
+	}
+}
+
+func BenchmarkFlag_Evaluate(b *testing.B) {
+	f, _ := New("f",
+		Step{At: time.Now(), Weights: map[string]int{"control": 100}},
+		Step{At: time.Now().Add(time.Hour), Weights: map[string]int{"treatment": 100}, Ramp: true},
+	)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		f.Evaluate("user")
+	}
+}
+
+func ExampleFlag_EvaluateAt() {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	flag, _ := New("new-checkout",
+		Step{At: start, Weights: map[string]int{"old": 100}},
+		// ramp the new checkout up to every user over a day
+		Step{At: start.Add(24 * time.Hour), Weights: map[string]int{"new": 100}, Ramp: true},
+	)
+	fmt.Println(flag.WeightsAt(start.Add(6 * time.Hour)))
+	fmt.Println(flag.EvaluateAt("alice", start.Add(48*time.Hour)))
+	// Output:
+	// map[new:25 old:75]
+	// new true
+}
@@ -0,0 +1,168 @@
+package sampling
+
+import (
+	"math/rand"
+
+	"github.com/mroth/weightedrand/v2/internal/constraints"
+)
+
+// Chao maintains a sample of up to k distinct items from a stream of weighted
+// items, using the algorithm of Chao (1982), without holding the population in
+// memory. After any number of items have been added, each item's probability
+// of inclusion in the sample is exactly as given by InclusionProbabilities for
+// the whole stream so far: proportional to its weight, except for items whose
+// weight is large enough for them to be included with certainty.
+//
+// Items with a weight < 1 are never included. A Chao is not safe for
+// concurrent usage.
+type Chao[T any, W constraints.Integer] struct {
+	k     int
+	total float64 // sum of the weights of all items added
+	scale float64 // inclusion probability per unit weight of uncertain items
+
+	// Items of the sample, split between those included with certainty, in
+	// order of decreasing weight, and those whose inclusion probability is
+	// scale times their weight.
+	certain   []chaoItem[T]
+	uncertain []chaoItem[T]
+}
+
+type chaoItem[T any] struct {
+	item   T
+	weight float64
+}
+
+// NewChao returns a Chao maintaining a sample of up to k items.
+func NewChao[T any, W constraints.Integer](k int) (*Chao[T, W], error) {
+	if k < 0 {
+		return nil, ErrNegative
+	}
+	return &Chao[T, W]{
+		k:         k,
+		certain:   make([]chaoItem[T], 0, k),
+		uncertain: make([]chaoItem[T], 0, k),
+	}, nil
+}
+
+// Add offers item to the sample, which it may enter by displacing another.
+func (c *Chao[T, W]) Add(item T, weight W) {
+	if weight < 1 || c.k == 0 {
+		return
+	}
+	w := float64(weight)
+	c.total += w
+	if len(c.certain)+len(c.uncertain) < c.k {
+		// Until more than k items have been added, every one is included.
+		c.insertCertain(item, w)
+		return
+	}
+
+	// Only the new item or those which were certain before may be certain
+	// now, since the inclusion probabilities of all others can only fall as
+	// the total weight grows. Find those which remain so in decreasing order
+	// of weight, as InclusionProbabilities does. The first kept of the
+	// previously certain items remain certain, and the rest are demoted.
+	kept, newCertain := 0, false
+	slots, rest := float64(c.k), c.total
+	for {
+		next, isNew := w, true
+		if newCertain || (kept < len(c.certain) && c.certain[kept].weight >= w) {
+			if kept == len(c.certain) {
+				break
+			}
+			next, isNew = c.certain[kept].weight, false
+		}
+		if slots*next < rest {
+			break
+		}
+		slots--
+		rest -= next
+		if isNew {
+			newCertain = true
+		} else {
+			kept++
+		}
+	}
+	scale := slots / rest
+
+	// Include the new item with its inclusion probability, p.
+	p := 1.0
+	if !newCertain {
+		p = scale * w
+	}
+	if rand.Float64() >= p {
+		c.demote(kept, -1, scale)
+		return
+	}
+
+	// Choose an item to displace, such that every item of the sample remains
+	// included with its new probability q: given that the new item was
+	// included, each demoted item is displaced with probability (1-q)/p, and
+	// each uncertain item with probability (1-scale/c.scale)/p. These sum to
+	// one, so r falls through to a uniformly chosen uncertain item if it does
+	// not land on a demoted one.
+	r := rand.Float64() * p
+	evict := -1
+	for i := kept; i < len(c.certain); i++ {
+		if r -= 1 - scale*c.certain[i].weight; r < 0 {
+			evict = i
+			break
+		}
+	}
+	switch {
+	case evict >= 0:
+		c.demote(kept, evict, scale)
+	case len(c.uncertain) > 0:
+		i := rand.Intn(len(c.uncertain))
+		last := len(c.uncertain) - 1
+		c.uncertain[i] = c.uncertain[last]
+		c.uncertain = c.uncertain[:last]
+		c.demote(kept, -1, scale)
+	default:
+		// Only reachable by floating point error in r.
+		c.demote(kept, len(c.certain)-1, scale)
+	}
+
+	if newCertain {
+		c.insertCertain(item, w)
+	} else {
+		c.uncertain = append(c.uncertain, chaoItem[T]{item, w})
+	}
+}
+
+// insertCertain adds an item to the certain items, which are kept in order of
+// decreasing weight.
+func (c *Chao[T, W]) insertCertain(item T, w float64) {
+	i := len(c.certain)
+	c.certain = append(c.certain, chaoItem[T]{})
+	for ; i > 0 && c.certain[i-1].weight < w; i-- {
+		c.certain[i] = c.certain[i-1]
+	}
+	c.certain[i] = chaoItem[T]{item, w}
+}
+
+// demote moves the certain items from index kept onwards to the uncertain
+// items, except for the item at index evict, which is dropped from the sample,
+// and records the new scale of the inclusion probabilities of uncertain items.
+func (c *Chao[T, W]) demote(kept, evict int, scale float64) {
+	for i := kept; i < len(c.certain); i++ {
+		if i != evict {
+			c.uncertain = append(c.uncertain, c.certain[i])
+		}
+	}
+	c.certain = c.certain[:kept]
+	c.scale = scale
+}
+
+// Sample returns the items currently in the sample, in no particular order.
+// It holds min(k, n) items, for n items with a weight >= 1 added so far.
+func (c *Chao[T, W]) Sample() []T {
+	sample := make([]T, 0, len(c.certain)+len(c.uncertain))
+	for _, ci := range c.certain {
+		sample = append(sample, ci.item)
+	}
+	for _, ci := range c.uncertain {
+		sample = append(sample, ci.item)
+	}
+	return sample
+}
@@ -0,0 +1,116 @@
+package sampling
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/mroth/weightedrand/v2"
+)
+
+func TestChao(t *testing.T) {
+	tests := []struct {
+		name    string
+		k       int
+		weights []int
+	}{
+		{name: "proportional", k: 3, weights: []int{1, 2, 3, 4, 5, 6, 7, 8}},
+		{name: "heavy first", k: 3, weights: []int{50, 40, 1, 2, 3, 4, 0, 5}},
+		{name: "heavy last", k: 3, weights: []int{1, 2, 3, 4, 5, 0, 40, 50}},
+		{name: "heavy demoted", k: 2, weights: []int{9, 1, 1, 3, 2, 8, 4, 6, 5}},
+		{name: "fewer than k", k: 5, weights: []int{1, 2, 0, 3}},
+	}
+	const runs = 100000
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			choices := make([]weightedrand.Choice[int, int], len(tt.weights))
+			for i, w := range tt.weights {
+				choices[i] = weightedrand.NewChoice(i, w)
+			}
+			n := tt.k
+			if pickable := len(tt.weights) - countZero(tt.weights); n > pickable {
+				n = pickable
+			}
+			probs, err := InclusionProbabilities(n, choices)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			counts := make([]int, len(choices))
+			for r := 0; r < runs; r++ {
+				c, err := NewChao[int, int](tt.k)
+				if err != nil {
+					t.Fatal(err)
+				}
+				for _, choice := range choices {
+					c.Add(choice.Item, choice.Weight)
+				}
+				sample := c.Sample()
+				if len(sample) != n {
+					t.Fatalf("len(sample) = %d, want %d", len(sample), n)
+				}
+				seen := make(map[int]bool)
+				for _, item := range sample {
+					if seen[item] {
+						t.Fatalf("sample %v contains duplicate", sample)
+					}
+					seen[item] = true
+					counts[item]++
+				}
+			}
+			for i, p := range probs {
+				if got := float64(counts[i]) / runs; math.Abs(got-p) > 0.01 {
+					t.Errorf("inclusion frequency of %d = %v, want %v", i, got, p)
+				}
+			}
+		})
+	}
+}
+
+func countZero(weights []int) int {
+	var n int
+	for _, w := range weights {
+		if w < 1 {
+			n++
+		}
+	}
+	return n
+}
+
+func TestNewChao(t *testing.T) {
+	if _, err := NewChao[int, int](-1); err != ErrNegative {
+		t.Errorf("NewChao(-1) error = %v, want %v", err, ErrNegative)
+	}
+	c, err := NewChao[int, int](0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Add(1, 1)
+	if got := c.Sample(); len(got) != 0 {
+		t.Errorf("Sample() = %v, want empty", got)
+	}
+}
+
+func BenchmarkChao_Add(b *testing.B) {
+	for _, k := range []int{10, 1000} {
+		b.Run(fmt.Sprintf("k=%d", k), func(b *testing.B) {
+			c, _ := NewChao[int, int](k)
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				c.Add(i, i%100+1)
+			}
+		})
+	}
+}
+
+func ExampleChao() {
+	c, _ := NewChao[string, int](2)
+	for _, line := range []struct {
+		host  string
+		bytes int
+	}{{"a", 512}, {"b", 0}, {"c", 2048}} {
+		c.Add(line.host, line.bytes)
+	}
+	fmt.Println(len(c.Sample()))
+	// Output: 2
+}
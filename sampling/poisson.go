@@ -0,0 +1,46 @@
+package sampling
+
+import (
+	"math/rand"
+
+	"github.com/mroth/weightedrand/v2"
+	"github.com/mroth/weightedrand/v2/internal/constraints"
+)
+
+// Poisson draws a Poisson sample, in which each choice is included
+// independently with its probability as given by InclusionProbabilities for an
+// expected sample size of n. Unlike PPS, the size of the sample varies from
+// draw to draw, but the independence of inclusions keeps the variance of
+// Horvitz–Thompson estimators simple to compute.
+//
+// The items are returned in the order of choices.
+func Poisson[T any, W constraints.Integer](n int, choices []weightedrand.Choice[T, W]) ([]T, error) {
+	indices, err := PoissonIndices(n, choices)
+	if err != nil {
+		return nil, err
+	}
+	sample := make([]T, len(indices))
+	for i, j := range indices {
+		sample[i] = choices[j].Item
+	}
+	return sample, nil
+}
+
+// PoissonIndices is like Poisson, but returns the indices of the included
+// choices, in increasing order. These index the probabilities returned by
+// InclusionProbabilities for the same choices, for weighting each sampled
+// item by the inverse of its probability of inclusion, as in the
+// Horvitz–Thompson estimator.
+func PoissonIndices[T any, W constraints.Integer](n int, choices []weightedrand.Choice[T, W]) ([]int, error) {
+	probs, err := InclusionProbabilities(n, choices)
+	if err != nil {
+		return nil, err
+	}
+	indices := make([]int, 0, n)
+	for i, p := range probs {
+		if p > 0 && rand.Float64() < p {
+			indices = append(indices, i)
+		}
+	}
+	return indices, nil
+}
@@ -0,0 +1,94 @@
+package sampling
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/mroth/weightedrand/v2"
+)
+
+func TestPoisson(t *testing.T) {
+	choices := []weightedrand.Choice[int, int]{
+		{Item: 0, Weight: 50}, {Item: 1, Weight: 1}, {Item: 2, Weight: 2},
+		{Item: 3, Weight: 3}, {Item: 4, Weight: 4}, {Item: 5, Weight: 0},
+	}
+	const n, runs = 3, 100000
+	probs, err := InclusionProbabilities(n, choices)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	counts := make([]int, len(choices))
+	var size int
+	for r := 0; r < runs; r++ {
+		sample, err := Poisson(n, choices)
+		if err != nil {
+			t.Fatal(err)
+		}
+		size += len(sample)
+		for i, item := range sample {
+			if i > 0 && item <= sample[i-1] {
+				t.Fatalf("sample %v not in order of choices", sample)
+			}
+			counts[item]++
+		}
+	}
+	for i, p := range probs {
+		if got := float64(counts[i]) / runs; math.Abs(got-p) > 0.01 {
+			t.Errorf("inclusion frequency of %d = %v, want %v", i, got, p)
+		}
+	}
+	if got := float64(size) / runs; math.Abs(got-n) > 0.02 {
+		t.Errorf("mean sample size = %v, want %v", got, n)
+	}
+
+	if _, err := Poisson(7, choices); err != ErrSampleSize {
+		t.Errorf("Poisson(7) error = %v, want %v", err, ErrSampleSize)
+	}
+}
+
+// The Horvitz–Thompson estimate of a population total, the sum over the
+// sample of each item's value divided by its probability of inclusion, is
+// unbiased.
+func TestPoissonIndices_horvitzThompson(t *testing.T) {
+	choices := []weightedrand.Choice[float64, int]{
+		{Item: 120, Weight: 100}, {Item: 15, Weight: 10}, {Item: 4, Weight: 5},
+		{Item: 30, Weight: 20}, {Item: 2, Weight: 1}, {Item: 55, Weight: 40},
+	}
+	var want float64
+	for _, c := range choices {
+		want += c.Item
+	}
+	probs, err := InclusionProbabilities(2, choices)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const runs = 100000
+	var mean float64
+	for r := 0; r < runs; r++ {
+		indices, err := PoissonIndices(2, choices)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, i := range indices {
+			mean += choices[i].Item / probs[i] / runs
+		}
+	}
+	if math.Abs(mean-want)/want > 0.01 {
+		t.Errorf("mean estimate = %v, want %v", mean, want)
+	}
+}
+
+func ExamplePoissonIndices() {
+	choices := []weightedrand.Choice[string, int]{
+		{Item: "large", Weight: 90},
+		{Item: "small", Weight: 10},
+	}
+	probs, _ := InclusionProbabilities(1, choices)
+	indices, _ := PoissonIndices(1, choices)
+	for _, i := range indices {
+		fmt.Printf("%s weighted by %.1f\n", choices[i].Item, 1/probs[i])
+	}
+}
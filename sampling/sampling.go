@@ -0,0 +1,111 @@
+// Package sampling provides weighted sampling designs for drawing multiple
+// distinct items, as used in survey sampling, where the probability of each
+// item's inclusion in the sample must be known exactly.
+package sampling
+
+import (
+	"errors"
+	"math/rand"
+
+	"github.com/mroth/weightedrand/v2"
+	"github.com/mroth/weightedrand/v2/internal/constraints"
+)
+
+// Possible errors returned when sampling.
+var (
+	ErrSampleSize = errors.New("sampling: sample size exceeds number of choices with weight >= 1")
+	ErrNegative   = errors.New("sampling: negative sample size")
+)
+
+// InclusionProbabilities returns the probability of each choice being included
+// in a probability proportional to size sample of n items, as drawn by PPS.
+//
+// Each choice's probability is n·w/W, for weight w and total weight W, except
+// that any choice for which this would exceed 1 is instead included with
+// certainty, with the remaining probabilities recomputed among the other
+// choices for the remaining sample size. Choices with a weight < 1 have a
+// probability of zero.
+func InclusionProbabilities[T any, W constraints.Integer](n int, choices []weightedrand.Choice[T, W]) ([]float64, error) {
+	if n < 0 {
+		return nil, ErrNegative
+	}
+	probs := make([]float64, len(choices))
+	pickable := 0
+	for _, c := range choices {
+		if c.Weight >= 1 {
+			pickable++
+		}
+	}
+	if n > pickable {
+		return nil, ErrSampleSize
+	}
+
+	// Repeatedly assign certainty to choices whose probability would exceed
+	// one, until the remainder are all proportional.
+	remaining := n
+	for remaining > 0 {
+		var total float64
+		for i, c := range choices {
+			if c.Weight >= 1 && probs[i] < 1 {
+				total += float64(c.Weight)
+			}
+		}
+		certain := 0
+		for i, c := range choices {
+			if c.Weight >= 1 && probs[i] < 1 {
+				probs[i] = float64(remaining) * float64(c.Weight) / total
+				if probs[i] >= 1 {
+					probs[i] = 1
+					certain++
+				}
+			}
+		}
+		if certain == 0 {
+			break
+		}
+		remaining -= certain
+		for i := range probs {
+			if probs[i] < 1 {
+				probs[i] = 0 // recomputed on next iteration
+			}
+		}
+	}
+	return probs, nil
+}
+
+// PPS draws a sample of n distinct items without replacement, with probability
+// proportional to size, such that each item's probability of inclusion is
+// exactly as given by InclusionProbabilities.
+//
+// Naively picking weighted items and removing them does not preserve these
+// inclusion probabilities. Instead, this uses randomized systematic sampling:
+// choices are placed in random order, their inclusion probabilities laid end
+// to end along a line of length n, and the items under n evenly spaced points
+// from a uniformly random start are selected.
+//
+// The items are returned in random order.
+func PPS[T any, W constraints.Integer](n int, choices []weightedrand.Choice[T, W]) ([]T, error) {
+	probs, err := InclusionProbabilities(n, choices)
+	if err != nil {
+		return nil, err
+	}
+	order := rand.Perm(len(choices))
+	sample := make([]T, 0, n)
+
+	point := rand.Float64()
+	var cum float64
+	for k, i := range order {
+		if len(sample) == n {
+			break
+		}
+		cum += probs[i]
+		if k == len(order)-1 {
+			cum = float64(n) // guard against accumulated floating point error
+		}
+		if probs[i] > 0 && point < cum {
+			sample = append(sample, choices[i].Item)
+			point++
+		}
+	}
+	return sample, nil
+}
@@ -0,0 +1,90 @@
+package sampling
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/mroth/weightedrand/v2"
+)
+
+func TestInclusionProbabilities(t *testing.T) {
+	choices := []weightedrand.Choice[string, int]{
+		weightedrand.NewChoice("big", 100),
+		weightedrand.NewChoice("a", 1),
+		weightedrand.NewChoice("b", 1),
+		weightedrand.NewChoice("c", 2),
+		weightedrand.NewChoice("zero", 0),
+	}
+	tests := []struct {
+		n    int
+		want []float64
+	}{
+		{n: 0, want: []float64{0, 0, 0, 0, 0}},
+		{n: 1, want: []float64{100.0 / 104, 1.0 / 104, 1.0 / 104, 2.0 / 104, 0}},
+		{n: 2, want: []float64{1, 0.25, 0.25, 0.5, 0}},
+		{n: 4, want: []float64{1, 1, 1, 1, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("n=%d", tt.n), func(t *testing.T) {
+			got, err := InclusionProbabilities(tt.n, choices)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var sum float64
+			for i := range got {
+				sum += got[i]
+				if math.Abs(got[i]-tt.want[i]) > 1e-12 {
+					t.Errorf("probability of %s = %v, want %v", choices[i].Item, got[i], tt.want[i])
+				}
+			}
+			if math.Abs(sum-float64(tt.n)) > 1e-9 {
+				t.Errorf("probabilities sum to %v, want %d", sum, tt.n)
+			}
+		})
+	}
+
+	if _, err := InclusionProbabilities(5, choices); err != ErrSampleSize {
+		t.Errorf("error = %v, want %v", err, ErrSampleSize)
+	}
+	if _, err := InclusionProbabilities(-1, choices); err != ErrNegative {
+		t.Errorf("error = %v, want %v", err, ErrNegative)
+	}
+}
+
+// Empirical inclusion frequencies should match the exact probabilities.
+func TestPPS(t *testing.T) {
+	choices := []weightedrand.Choice[int, int]{
+		{Item: 0, Weight: 50}, {Item: 1, Weight: 1}, {Item: 2, Weight: 2},
+		{Item: 3, Weight: 3}, {Item: 4, Weight: 4}, {Item: 5, Weight: 0},
+	}
+	const n, runs = 3, 100000
+	probs, err := InclusionProbabilities(n, choices)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	counts := make([]int, len(choices))
+	for r := 0; r < runs; r++ {
+		sample, err := PPS(n, choices)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(sample) != n {
+			t.Fatalf("len(sample) = %d, want %d", len(sample), n)
+		}
+		seen := make(map[int]bool)
+		for _, item := range sample {
+			if seen[item] {
+				t.Fatalf("sample %v contains duplicate", sample)
+			}
+			seen[item] = true
+			counts[item]++
+		}
+	}
+	for i, p := range probs {
+		if got := float64(counts[i]) / runs; math.Abs(got-p) > 0.01 {
+			t.Errorf("inclusion frequency of %d = %v, want %v", i, got, p)
+		}
+	}
+}
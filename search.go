@@ -0,0 +1,43 @@
+package weightedrand
+
+// branchlessMaxLen is the largest totals length for which search uses the
+// branchless variant. Once the table exceeds CPU caches, the speculative loads
+// of a mispredicted branchy search act as an effective prefetch, outweighing
+// the cost of the mispredictions. Tuned on amd64 with BenchmarkSearch and
+// BenchmarkPick.
+const branchlessMaxLen = 1 << 16
+
+// search returns the index of the first total >= x, selecting the fastest
+// search variant for the size of the table.
+func search(totals []int, x int) int {
+	if len(totals) <= branchlessMaxLen {
+		return searchIntsBranchless(totals, x)
+	}
+	return searchInts(totals, x)
+}
+
+// searchIntsBranchless is equivalent to searchInts for the non-negative values
+// of a Chooser's totals, but performs a fixed number of iterations for a given
+// length, and advances through the slice using arithmetic on the sign of each
+// comparison rather than a conditional branch. For large tables, where the
+// branches of a binary search are effectively random and thus frequently
+// mispredicted, this avoids pipeline stalls.
+func searchIntsBranchless(a []int, x int) int {
+	n := len(a)
+	if n == 0 {
+		return 0
+	}
+	base := 0
+	for n > 1 {
+		half := n >> 1
+		base += half & lessMask(a[base+half-1], x)
+		n -= half
+	}
+	return base + 1&lessMask(a[base], x)
+}
+
+// lessMask returns all one bits if a < b, otherwise zero, for non-negative a
+// and b, whose difference therefore cannot overflow.
+func lessMask(a, b int) int {
+	return (a - b) >> (intSize - 1)
+}
@@ -0,0 +1,62 @@
+package weightedrand
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestSearchIntsBranchless(t *testing.T) {
+	for n := 0; n <= 64; n++ {
+		a := make([]int, n)
+		for i := range a {
+			a[i] = rand.Intn(20)
+		}
+		sort.Ints(a)
+		for x := 0; x <= 21; x++ {
+			if got, want := searchIntsBranchless(a, x), sort.SearchInts(a, x); got != want {
+				t.Fatalf("searchIntsBranchless(%v, %d) = %d, want %d", a, x, got, want)
+			}
+		}
+	}
+}
+
+func BenchmarkSearch(b *testing.B) {
+	searches := []struct {
+		name string
+		fn   func([]int, int) int
+	}{
+		{"binary", searchInts},
+		{"branchless", searchIntsBranchless},
+	}
+	for n := BMMinChoices; n <= BMMaxChoices; n *= 10 {
+		chooser, err := NewChooser(mockChoices(n)...)
+		if err != nil {
+			b.Fatal(err)
+		}
+		for _, s := range searches {
+			b.Run(fmt.Sprintf("size=%s/search=%s", fmt1eN(n), s.name), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					_ = s.fn(chooser.totals, rand.Intn(chooser.max)+1)
+				}
+			})
+		}
+		var e eytzinger
+		e.build(chooser.totals)
+		b.Run(fmt.Sprintf("size=%s/search=eytzinger", fmt1eN(n)), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_ = e.search(rand.Intn(chooser.max) + 1)
+			}
+		})
+		var g guide
+		if !g.build(chooser.totals, chooser.max) {
+			continue
+		}
+		b.Run(fmt.Sprintf("size=%s/search=guide", fmt1eN(n)), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_ = g.search(chooser.totals, rand.Intn(chooser.max)+1)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+//go:build go1.23
+
+package weightedrand
+
+import "iter"
+
+// MergeSeq returns an iterator which lazily merges several iterators, choosing
+// the source of each successive value by weighted random selection amongst the
+// sources which have not yet been exhausted. The order of values from each
+// source is preserved.
+//
+// Sources with a weight < 1 are never pulled from. Once a source is exhausted
+// the remaining sources are chosen amongst in proportion to their weights, so
+// the merged iterator yields every value of every source with a weight >= 1.
+// Sources are only pulled as the merged iterator is consumed, and are stopped
+// when it finishes or its consumer stops early.
+//
+// MergeSeq panics if the weights of the sources sum to more than the maximum
+// of an int, as NewChooser would return an error.
+func MergeSeq[T any, W integer](seqs ...Choice[iter.Seq[T], W]) iter.Seq[T] {
+	choices := make([]Choice[int, W], 0, len(seqs))
+	for i, s := range seqs {
+		if s.Weight >= 1 {
+			choices = append(choices, NewChoice(i, s.Weight))
+		}
+	}
+	if _, err := sumWeights(choices); err != nil {
+		panic(err)
+	}
+
+	return func(yield func(T) bool) {
+		next := make([]func() (T, bool), len(seqs))
+		for _, c := range choices {
+			var stop func()
+			next[c.Item], stop = iter.Pull(seqs[c.Item].Item)
+			defer stop()
+		}
+
+		remaining := append([]Choice[int, W](nil), choices...)
+		for len(remaining) > 0 {
+			// Construction cannot fail, having validated the total weight.
+			chooser, _ := NewChooser(append([]Choice[int, W](nil), remaining...)...)
+			for {
+				i := chooser.Pick()
+				v, ok := next[i]()
+				if !ok {
+					remaining = removeItem(remaining, i)
+					break
+				}
+				if !yield(v) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// removeItem removes the first choice of item i from choices.
+func removeItem[W integer](choices []Choice[int, W], i int) []Choice[int, W] {
+	for j, c := range choices {
+		if c.Item == i {
+			return append(choices[:j], choices[j+1:]...)
+		}
+	}
+	return choices
+}
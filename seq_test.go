@@ -0,0 +1,72 @@
+//go:build go1.23
+
+package weightedrand
+
+import (
+	"fmt"
+	"iter"
+	"slices"
+	"testing"
+)
+
+func ExampleMergeSeq() {
+	merged := MergeSeq(
+		NewChoice(slices.Values([]string{"a", "b", "c"}), 1),
+		NewChoice(slices.Values([]string{"x", "y"}), 0),
+	)
+	for v := range merged {
+		fmt.Print(v)
+	}
+	fmt.Println()
+	// Output: abc
+}
+
+func TestMergeSeq(t *testing.T) {
+	var pulled int
+	counting := func(prefix string, n int) iter.Seq[string] {
+		return func(yield func(string) bool) {
+			for i := 0; i < n; i++ {
+				pulled++
+				if !yield(fmt.Sprintf("%s%d", prefix, i)) {
+					return
+				}
+			}
+		}
+	}
+
+	merged := MergeSeq(
+		NewChoice(counting("a", 6), 3),
+		NewChoice(counting("b", 2), 1),
+		NewChoice(counting("z", 2), 0),
+	)
+	got := slices.Collect(merged)
+	if len(got) != 8 {
+		t.Fatalf("MergeSeq() yielded %v, want 8 values", got)
+	}
+	next := make(map[byte]int)
+	for _, v := range got {
+		if want := fmt.Sprintf("%c%d", v[0], next[v[0]]); v != want {
+			t.Fatalf("MergeSeq() = %v, out of source order at %s", got, v)
+		}
+		next[v[0]]++
+	}
+
+	// Stopping early must not pull further values from any source.
+	pulled = 0
+	for range merged {
+		break
+	}
+	if pulled != 1 {
+		t.Errorf("pulled %d values for a single yield, want 1", pulled)
+	}
+}
+
+func TestMergeSeq_overflow(t *testing.T) {
+	defer func() {
+		if r := recover(); r != errWeightOverflow {
+			t.Errorf("MergeSeq() panic = %v, want %v", r, errWeightOverflow)
+		}
+	}()
+	empty := slices.Values([]int(nil))
+	MergeSeq(NewChoice(empty, maxInt), NewChoice(empty, maxInt))
+}
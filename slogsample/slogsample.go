@@ -0,0 +1,147 @@
+//go:build go1.21
+
+// Package slogsample provides a log/slog Handler which samples log records
+// with weighted probabilities per level or per attribute key, such as keeping
+// every error but only 1% of debug records, before passing them on to another
+// Handler.
+package slogsample
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+
+	"github.com/mroth/weightedrand/v2"
+)
+
+// A Rate is the relative weight of keeping versus dropping a record. For
+// example, a Rate of {Keep: 1, Drop: 99} keeps 1% of records.
+type Rate struct {
+	Keep uint32
+	Drop uint32
+}
+
+// Options configures the rates at which a Handler samples records.
+type Options struct {
+	// Levels gives the rate for records by level. Each record is sampled at
+	// the rate of the highest configured level at or below its own, so rates
+	// apply to all levels up to the next configured level.
+	Levels map[slog.Level]Rate
+
+	// Attrs gives the rate for records with an attribute of a given key,
+	// including attributes added with WithAttrs, taking precedence over
+	// Levels. If a record has several such attributes, the first applies.
+	Attrs map[string]Rate
+
+	// Default is the rate for records matching neither Levels nor Attrs. The
+	// zero value keeps every record.
+	Default Rate
+}
+
+type sampler = *weightedrand.Chooser[bool, uint32]
+
+// A Handler samples records, passing those it keeps to another Handler.
+type Handler struct {
+	next     slog.Handler
+	levels   []slog.Level // configured levels, ascending
+	byLevel  []sampler
+	attrs    map[string]sampler
+	fallback sampler // nil to keep every record
+	fixed    sampler // rate from an attribute added with WithAttrs, if any
+}
+
+var _ slog.Handler = (*Handler)(nil)
+
+// New returns a Handler sampling records according to opts before passing
+// them on to next. It returns an error if any rate in opts other than Default
+// is the zero Rate.
+func New(next slog.Handler, opts Options) (*Handler, error) {
+	h := &Handler{next: next, attrs: make(map[string]sampler, len(opts.Attrs))}
+	var err error
+	if opts.Default != (Rate{}) {
+		if h.fallback, err = newSampler(opts.Default); err != nil {
+			return nil, err
+		}
+	}
+	for level := range opts.Levels {
+		h.levels = append(h.levels, level)
+	}
+	sort.Slice(h.levels, func(i, j int) bool { return h.levels[i] < h.levels[j] })
+	h.byLevel = make([]sampler, len(h.levels))
+	for i, level := range h.levels {
+		if h.byLevel[i], err = newSampler(opts.Levels[level]); err != nil {
+			return nil, fmt.Errorf("slogsample: rate for level %v: %w", level, err)
+		}
+	}
+	for key, r := range opts.Attrs {
+		if h.attrs[key], err = newSampler(r); err != nil {
+			return nil, fmt.Errorf("slogsample: rate for attribute %q: %w", key, err)
+		}
+	}
+	return h, nil
+}
+
+func newSampler(r Rate) (sampler, error) {
+	return weightedrand.NewChooser(
+		weightedrand.NewChoice(true, r.Keep),
+		weightedrand.NewChoice(false, r.Drop),
+	)
+}
+
+// Enabled implements slog.Handler, reporting whether the next Handler is
+// enabled for the level.
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler, passing r on to the next Handler if it is
+// picked to be kept.
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	if s := h.sampler(r); s != nil && !s.Pick() {
+		return nil
+	}
+	return h.next.Handle(ctx, r)
+}
+
+// sampler returns the sampler applying to r, or nil if it is to be kept.
+func (h *Handler) sampler(r slog.Record) sampler {
+	if h.fixed != nil {
+		return h.fixed
+	}
+	var s sampler
+	if len(h.attrs) > 0 {
+		r.Attrs(func(a slog.Attr) bool {
+			s = h.attrs[a.Key]
+			return s == nil
+		})
+		if s != nil {
+			return s
+		}
+	}
+	// The last configured level <= the record's level.
+	i := sort.Search(len(h.levels), func(i int) bool { return h.levels[i] > r.Level })
+	if i > 0 {
+		return h.byLevel[i-1]
+	}
+	return h.fallback
+}
+
+// WithAttrs implements slog.Handler.
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	h2 := *h
+	h2.next = h.next.WithAttrs(attrs)
+	for _, a := range attrs {
+		if s, ok := h.attrs[a.Key]; ok && h2.fixed == nil {
+			h2.fixed = s
+		}
+	}
+	return &h2
+}
+
+// WithGroup implements slog.Handler.
+func (h *Handler) WithGroup(name string) slog.Handler {
+	h2 := *h
+	h2.next = h.next.WithGroup(name)
+	return &h2
+}
@@ -0,0 +1,90 @@
+//go:build go1.21
+
+package slogsample
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"math"
+	"strings"
+	"testing"
+)
+
+// countHandler counts the records it handles.
+type countHandler struct {
+	slog.Handler
+	n *int
+}
+
+func (h countHandler) Handle(context.Context, slog.Record) error {
+	*h.n++
+	return nil
+}
+
+func (h countHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+
+func TestHandler(t *testing.T) {
+	opts := Options{
+		Levels: map[slog.Level]Rate{
+			slog.LevelDebug: {Keep: 1, Drop: 99},
+			slog.LevelInfo:  {Keep: 1, Drop: 1},
+			slog.LevelError: {Keep: 1},
+		},
+		Attrs: map[string]Rate{"audit": {Keep: 1}},
+	}
+	tests := []struct {
+		name  string
+		level slog.Level
+		attrs []any
+		with  []any
+		want  float64
+	}{
+		{name: "below all levels", level: slog.LevelDebug - 4, want: 1},
+		{name: "debug", level: slog.LevelDebug, want: 0.01},
+		{name: "info", level: slog.LevelInfo, want: 0.5},
+		{name: "warn uses info", level: slog.LevelWarn, want: 0.5},
+		{name: "error", level: slog.LevelError, want: 1},
+		{name: "attr", level: slog.LevelDebug, attrs: []any{"user", 1, "audit", true}, want: 1},
+		{name: "with attrs", level: slog.LevelDebug, with: []any{"audit", true}, want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var n int
+			enabled := slog.NewTextHandler(nil, &slog.HandlerOptions{Level: slog.LevelDebug - 4})
+			h, err := New(countHandler{enabled, &n}, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			logger := slog.New(h).With(tt.with...)
+			const iterations = 10000
+			for i := 0; i < iterations; i++ {
+				logger.Log(context.Background(), tt.level, "msg", tt.attrs...)
+			}
+			if got := float64(n) / iterations; math.Abs(got-tt.want) > 0.02 {
+				t.Errorf("kept fraction = %.3f, want %.2f", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	if _, err := New(slog.Default().Handler(), Options{Levels: map[slog.Level]Rate{slog.LevelInfo: {}}}); err == nil {
+		t.Error("New() with zero level rate error = nil")
+	}
+	if _, err := New(slog.Default().Handler(), Options{Attrs: map[string]Rate{"a": {}}}); err == nil {
+		t.Error("New() with zero attribute rate error = nil")
+	}
+}
+
+func TestHandler_passthrough(t *testing.T) {
+	var buf bytes.Buffer
+	h, err := New(slog.NewTextHandler(&buf, nil), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	slog.New(h).WithGroup("g").With("k", "v").Info("hello")
+	if got := buf.String(); !strings.Contains(got, "msg=hello g.k=v") {
+		t.Errorf("output = %q, want record passed through", got)
+	}
+}
@@ -0,0 +1,60 @@
+package weightedrand
+
+import (
+	"errors"
+	"math"
+)
+
+// probabilityResolution is the total integer weight that probabilities are
+// scaled to. It is chosen to fit within the max int of 32-bit platforms.
+const probabilityResolution = 1 << 30
+
+// Possible errors returned by NewSoftmaxChooser.
+var (
+	errSoftmaxLength      = errors.New("items and scores differ in length")
+	errSoftmaxTemperature = errors.New("temperature must be positive and finite")
+	errSoftmaxScore       = errors.New("scores must be finite")
+)
+
+// NewSoftmaxChooser initializes a new Chooser picking from items, where the
+// probability of each item is given by the softmax of its corresponding score:
+//
+//	exp(scores[i]/temperature) / Σ exp(scores[j]/temperature)
+//
+// Lower temperatures concentrate probability on the highest scores, while
+// higher temperatures flatten the distribution towards uniform. Scores may be
+// any finite real values.
+//
+// Probabilities are scaled to integer weights with a resolution of 2^-30, so
+// items with a probability below that are never picked.
+func NewSoftmaxChooser[T any](items []T, scores []float64, temperature float64) (*Chooser[T, int], error) {
+	if len(items) != len(scores) {
+		return nil, errSoftmaxLength
+	}
+	if !(temperature > 0) || math.IsInf(temperature, 1) {
+		return nil, errSoftmaxTemperature
+	}
+
+	// Subtract the max score before exponentiating for numerical stability, so
+	// that all exponents are <= 0 and the largest term is exactly 1.
+	maxScore := math.Inf(-1)
+	for _, s := range scores {
+		if math.IsNaN(s) || math.IsInf(s, 0) {
+			return nil, errSoftmaxScore
+		}
+		maxScore = math.Max(maxScore, s)
+	}
+	exps := make([]float64, len(scores))
+	var sum float64
+	for i, s := range scores {
+		exps[i] = math.Exp((s - maxScore) / temperature)
+		sum += exps[i]
+	}
+
+	choices := make([]Choice[T, int], len(items))
+	for i, item := range items {
+		w := int(math.Round(exps[i] / sum * probabilityResolution))
+		choices[i] = NewChoice(item, w)
+	}
+	return NewChooser(choices...)
+}
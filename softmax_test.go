@@ -0,0 +1,69 @@
+package weightedrand
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func ExampleNewSoftmaxChooser() {
+	items := []string{"low", "mid", "high"}
+	scores := []float64{-100, 0.5, 42}
+	chooser, _ := NewSoftmaxChooser(items, scores, 0.1)
+	fmt.Println(chooser.Pick())
+	//Output: high
+}
+
+func TestNewSoftmaxChooser(t *testing.T) {
+	items := []rune{'a', 'b', 'c'}
+	tests := []struct {
+		name        string
+		items       []rune
+		scores      []float64
+		temperature float64
+		wantErr     error
+	}{
+		{name: "length mismatch", items: items, scores: []float64{1, 2}, temperature: 1, wantErr: errSoftmaxLength},
+		{name: "zero temperature", items: items, scores: []float64{1, 2, 3}, temperature: 0, wantErr: errSoftmaxTemperature},
+		{name: "NaN temperature", items: items, scores: []float64{1, 2, 3}, temperature: math.NaN(), wantErr: errSoftmaxTemperature},
+		{name: "infinite temperature", items: items, scores: []float64{1, 2, 3}, temperature: math.Inf(1), wantErr: errSoftmaxTemperature},
+		{name: "NaN score", items: items, scores: []float64{1, math.NaN(), 3}, temperature: 1, wantErr: errSoftmaxScore},
+		{name: "infinite score", items: items, scores: []float64{1, math.Inf(1), 3}, temperature: 1, wantErr: errSoftmaxScore},
+		{name: "no items", items: nil, scores: nil, temperature: 1, wantErr: errNoValidChoices},
+		{name: "huge scores", items: items, scores: []float64{1e300, -1e300, 1e299}, temperature: 1e-3, wantErr: nil},
+		{name: "nominal case", items: items, scores: []float64{1, 2, 3}, temperature: 1, wantErr: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewSoftmaxChooser(tt.items, tt.scores, tt.temperature)
+			if err != tt.wantErr {
+				t.Errorf("NewSoftmaxChooser() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// The resulting weights should be proportional to the softmax probabilities,
+// and flatten towards uniform as temperature increases.
+func TestNewSoftmaxChooser_weights(t *testing.T) {
+	scores := []float64{0, 1, 2}
+	for _, temp := range []float64{0.5, 1, 10} {
+		t.Run(fmt.Sprintf("temperature=%v", temp), func(t *testing.T) {
+			c, err := NewSoftmaxChooser([]int{0, 1, 2}, scores, temp)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var sum float64
+			for _, s := range scores {
+				sum += math.Exp(s / temp)
+			}
+			for _, choice := range c.data {
+				want := math.Exp(scores[choice.Item]/temp) / sum
+				got := float64(choice.Weight) / float64(c.max)
+				if math.Abs(got-want) > 1e-6 {
+					t.Errorf("probability of %d = %v, want %v", choice.Item, got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+// Package split divides datasets into subsets of given proportions, such as the
+// training, validation and test sets of a machine learning model, reproducibly
+// from a seed.
+package split
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+
+	"github.com/mroth/weightedrand/v2"
+	"github.com/mroth/weightedrand/v2/internal/constraints"
+)
+
+// ErrFractions is returned when the fractions to split into are not all
+// non-negative and summing to one.
+var ErrFractions = errors.New("split: fractions must be non-negative and sum to 1")
+
+// fractionTolerance allows for the rounding of fractions such as 0.1, which
+// have no exact binary floating point representation.
+const fractionTolerance = 1e-9
+
+// Split divides records into len(fractions) subsets holding the given
+// fractions of them, such as 0.8, 0.1 and 0.1 for training, validation and test
+// sets. Subset sizes are rounded to whole records, and every record is in
+// exactly one subset, in which records keep their original order.
+//
+// Records are assigned by a pseudo-random shuffle seeded with seed, so the
+// same records, seed and fractions always produce the same subsets, across
+// processes and Go releases.
+func Split[T any](records []T, seed int64, fractions ...float64) ([][]T, error) {
+	weights := make([]float64, len(records))
+	for i := range weights {
+		weights[i] = 1
+	}
+	return split(records, weights, seed, fractions)
+}
+
+// SplitWeighted is like Split, but divides the records by their importance
+// weights rather than their number, so that each subset holds close to the
+// given fraction of the total weight, such as when records are users weighted
+// by their number of events. Records with a weight < 1 count as having no
+// weight, but are still assigned to a subset.
+func SplitWeighted[T any, W constraints.Integer](records []weightedrand.Choice[T, W], seed int64, fractions ...float64) ([][]T, error) {
+	items := make([]T, len(records))
+	weights := make([]float64, len(records))
+	for i, r := range records {
+		items[i] = r.Item
+		if r.Weight > 0 {
+			weights[i] = float64(r.Weight)
+		}
+	}
+	return split(items, weights, seed, fractions)
+}
+
+// split shuffles the records and lays them end to end, each taking up the
+// length of its weight, then cuts the line into lengths in proportion to the
+// fractions. A record straddling a cut goes to the subset holding its midpoint.
+func split[T any](records []T, weights []float64, seed int64, fractions []float64) ([][]T, error) {
+	if len(fractions) == 0 {
+		return nil, ErrFractions
+	}
+	var sum, total float64
+	for _, f := range fractions {
+		if f < 0 || math.IsNaN(f) {
+			return nil, ErrFractions
+		}
+		sum += f
+	}
+	if math.Abs(sum-1) > fractionTolerance {
+		return nil, ErrFractions
+	}
+	for _, w := range weights {
+		total += w
+	}
+
+	cuts := make([]float64, len(fractions))
+	var cum float64
+	for j, f := range fractions {
+		cum += f
+		cuts[j] = cum * total
+	}
+	cuts[len(cuts)-1] = math.Inf(1)
+
+	assigned := make([]int, len(records))
+	var pos float64
+	j := 0
+	for _, i := range rand.New(rand.NewSource(seed)).Perm(len(records)) {
+		for pos+weights[i]/2 >= cuts[j] {
+			j++
+		}
+		assigned[i] = j
+		pos += weights[i]
+	}
+
+	subsets := make([][]T, len(fractions))
+	for i, r := range records {
+		subsets[assigned[i]] = append(subsets[assigned[i]], r)
+	}
+	return subsets, nil
+}
@@ -0,0 +1,134 @@
+package split
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/mroth/weightedrand/v2"
+)
+
+func ExampleSplit() {
+	records := []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j"}
+	sets, _ := Split(records, 42, 0.8, 0.1, 0.1)
+	train, validation, test := sets[0], sets[1], sets[2]
+	fmt.Println(len(train), len(validation), len(test))
+	// Output: 8 1 1
+}
+
+func records(n int) []int {
+	r := make([]int, n)
+	for i := range r {
+		r[i] = i
+	}
+	return r
+}
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		n         int
+		fractions []float64
+		want      []int
+	}{
+		{n: 1000, fractions: []float64{0.8, 0.1, 0.1}, want: []int{800, 100, 100}},
+		{n: 10, fractions: []float64{0.7, 0.3}, want: []int{7, 3}},
+		{n: 3, fractions: []float64{0.5, 0.5}, want: []int{1, 2}},
+		{n: 100, fractions: []float64{0, 1, 0}, want: []int{0, 100, 0}},
+		{n: 0, fractions: []float64{0.5, 0.5}, want: []int{0, 0}},
+		{n: 9, fractions: []float64{1.0 / 3, 1.0 / 3, 1.0 / 3}, want: []int{3, 3, 3}},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.n, tt.fractions), func(t *testing.T) {
+			sets, err := Split(records(tt.n), 1, tt.fractions...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := make([]int, len(sets))
+			seen := make(map[int]bool)
+			for j, set := range sets {
+				got[j] = len(set)
+				for i, r := range set {
+					if i > 0 && r <= set[i-1] {
+						t.Errorf("subset %d not in original order: %v", j, set)
+					}
+					seen[r] = true
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("subset sizes = %v, want %v", got, tt.want)
+			}
+			if len(seen) != tt.n {
+				t.Errorf("subsets hold %d distinct records, want %d", len(seen), tt.n)
+			}
+		})
+	}
+}
+
+func TestSplit_seed(t *testing.T) {
+	a, _ := Split(records(100), 7, 0.5, 0.5)
+	b, _ := Split(records(100), 7, 0.5, 0.5)
+	if !reflect.DeepEqual(a, b) {
+		t.Error("same seed produced different subsets")
+	}
+	c, _ := Split(records(100), 8, 0.5, 0.5)
+	if reflect.DeepEqual(a, c) {
+		t.Error("different seeds produced the same subsets")
+	}
+
+	// Pinned, so that changes to the shuffle which would break the
+	// reproducibility of existing splits are caught.
+	got, _ := Split(records(10), 42, 0.8, 0.2)
+	if want := []int{3, 4}; fmt.Sprint(got[1]) != fmt.Sprint(want) {
+		t.Errorf("Split(seed 42) = %v, want second subset %v", got, want)
+	}
+}
+
+func TestSplitWeighted(t *testing.T) {
+	const n = 10000
+	choices := make([]weightedrand.Choice[int, int], n)
+	var total float64
+	for i := range choices {
+		// Weights from -1 to 98, such that a split by count would differ.
+		choices[i] = weightedrand.NewChoice(i, i%100-1)
+		if w := choices[i].Weight; w > 0 {
+			total += float64(w)
+		}
+	}
+	fractions := []float64{0.6, 0.3, 0.1}
+	sets, err := SplitWeighted(choices, 3, fractions...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	count := 0
+	for j, set := range sets {
+		count += len(set)
+		var w float64
+		for _, i := range set {
+			if cw := choices[i].Weight; cw > 0 {
+				w += float64(cw)
+			}
+		}
+		// Each subset is within one record's weight of its share.
+		if got, want := w, fractions[j]*total; math.Abs(got-want) > 99 {
+			t.Errorf("subset %d holds weight %v, want %v", j, got, want)
+		}
+	}
+	if count != n {
+		t.Errorf("subsets hold %d records, want %d", count, n)
+	}
+}
+
+func TestSplit_fractions(t *testing.T) {
+	for _, fractions := range [][]float64{
+		nil,
+		{0.5},
+		{0.8, 0.3},
+		{1.5, -0.5},
+		{math.NaN(), 1},
+	} {
+		if _, err := Split(records(10), 1, fractions...); err != ErrFractions {
+			t.Errorf("Split(%v) error = %v, want %v", fractions, err, ErrFractions)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+// Package srv selects targets from DNS SRV records following RFC 2782: only
+// records of the lowest priority are eligible, and among them each is chosen
+// with probability proportional to its weight, with records of weight zero
+// having a very small chance of selection.
+//
+// The records returned by net.LookupSRV are already in this order, but a
+// client which caches records or excludes failed targets must reselect among
+// those remaining, which is subtly easy to get wrong.
+package srv
+
+import (
+	"errors"
+	"math/rand"
+	"net"
+	"sort"
+)
+
+// ErrNoRecords is returned by Select if there are no available records.
+var ErrNoRecords = errors.New("srv: no available records")
+
+// Select returns a record chosen per RFC 2782 from among those for which
+// available returns true, or from all records if available is nil. Only
+// available records of the lowest priority present are eligible.
+func Select(records []*net.SRV, available func(*net.SRV) bool) (*net.SRV, error) {
+	group := make([]*net.SRV, 0, len(records))
+	for _, r := range records {
+		if available != nil && !available(r) {
+			continue
+		}
+		switch {
+		case len(group) == 0 || r.Priority == group[0].Priority:
+			group = append(group, r)
+		case r.Priority < group[0].Priority:
+			group = append(group[:0], r)
+		}
+	}
+	if len(group) == 0 {
+		return nil, ErrNoRecords
+	}
+	return group[pick(group)], nil
+}
+
+// Order returns a copy of records in the order in which RFC 2782 directs
+// clients to try them: in increasing order of priority, and within each
+// priority in a weighted random order produced by repeated selection.
+func Order(records []*net.SRV) []*net.SRV {
+	ordered := append([]*net.SRV(nil), records...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].Priority < ordered[j].Priority
+	})
+	for start := 0; start < len(ordered); {
+		end := start + 1
+		for end < len(ordered) && ordered[end].Priority == ordered[start].Priority {
+			end++
+		}
+		// Select each position in turn from the records not yet placed.
+		for i := start; i < end-1; i++ {
+			j := i + pick(ordered[i:end])
+			ordered[i], ordered[j] = ordered[j], ordered[i]
+		}
+		start = end
+	}
+	return ordered
+}
+
+// pick returns the index of a record of group chosen by the RFC 2782 weighted
+// selection: the records are arranged with those of weight zero first, a
+// random number r is drawn from [0, sum] for the sum of the weights, and the
+// first record is chosen whose running sum of weights is >= r. The RFC allows
+// any arrangement besides this, so it is taken to be random, such that when r
+// is zero the choice is uniform among the first records with weight zero, or
+// among all records if there are none.
+func pick(group []*net.SRV) int {
+	var sum, zeros int
+	for _, r := range group {
+		sum += int(r.Weight)
+		if r.Weight == 0 {
+			zeros++
+		}
+	}
+	target := rand.Intn(sum + 1)
+	if target == 0 {
+		if zeros == 0 {
+			return rand.Intn(len(group))
+		}
+		n := rand.Intn(zeros)
+		for i, r := range group {
+			if r.Weight == 0 {
+				if n == 0 {
+					return i
+				}
+				n--
+			}
+		}
+	}
+	running := 0
+	for i, r := range group {
+		running += int(r.Weight)
+		if r.Weight > 0 && running >= target {
+			return i
+		}
+	}
+	panic("unreachable")
+}
@@ -0,0 +1,143 @@
+package srv
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"testing"
+)
+
+func records() []*net.SRV {
+	return []*net.SRV{
+		{Target: "backup.", Priority: 20, Weight: 10},
+		{Target: "a.", Priority: 10, Weight: 60},
+		{Target: "b.", Priority: 10, Weight: 30},
+		{Target: "c.", Priority: 10, Weight: 9},
+		{Target: "zero.", Priority: 10, Weight: 0},
+	}
+}
+
+// frequencies returns how often each target is returned by fn.
+func frequencies(t *testing.T, fn func() *net.SRV) map[string]float64 {
+	t.Helper()
+	const runs = 200000
+	freq := make(map[string]float64)
+	for i := 0; i < runs; i++ {
+		freq[fn().Target] += 1.0 / runs
+	}
+	return freq
+}
+
+func TestSelect(t *testing.T) {
+	tests := []struct {
+		name      string
+		available func(*net.SRV) bool
+		want      map[string]float64
+	}{
+		{
+			name: "all available",
+			want: map[string]float64{"a.": 60.0 / 100, "b.": 30.0 / 100, "c.": 9.0 / 100, "zero.": 1.0 / 100},
+		},
+		{
+			name:      "a down",
+			available: func(r *net.SRV) bool { return r.Target != "a." },
+			want:      map[string]float64{"b.": 30.0 / 40, "c.": 9.0 / 40, "zero.": 1.0 / 40},
+		},
+		{
+			name:      "only zero weight left at priority 10",
+			available: func(r *net.SRV) bool { return r.Target == "zero." || r.Target == "backup." },
+			want:      map[string]float64{"zero.": 1},
+		},
+		{
+			name:      "fall back to next priority",
+			available: func(r *net.SRV) bool { return r.Priority == 20 },
+			want:      map[string]float64{"backup.": 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recs := records()
+			got := frequencies(t, func() *net.SRV {
+				r, err := Select(recs, tt.available)
+				if err != nil {
+					t.Fatal(err)
+				}
+				return r
+			})
+			for target := range got {
+				if _, ok := tt.want[target]; !ok {
+					t.Errorf("selected ineligible target %s", target)
+				}
+			}
+			for target, want := range tt.want {
+				if math.Abs(got[target]-want) > 0.005 {
+					t.Errorf("frequency of %s = %.4f, want %.4f", target, got[target], want)
+				}
+			}
+		})
+	}
+
+	if _, err := Select(records(), func(*net.SRV) bool { return false }); err != ErrNoRecords {
+		t.Errorf("Select() error = %v, want %v", err, ErrNoRecords)
+	}
+	if _, err := Select(nil, nil); err != ErrNoRecords {
+		t.Errorf("Select(nil) error = %v, want %v", err, ErrNoRecords)
+	}
+}
+
+func TestSelect_allZero(t *testing.T) {
+	recs := []*net.SRV{{Target: "x."}, {Target: "y."}, {Target: "z."}}
+	got := frequencies(t, func() *net.SRV {
+		r, _ := Select(recs, nil)
+		return r
+	})
+	for _, r := range recs {
+		if math.Abs(got[r.Target]-1.0/3) > 0.005 {
+			t.Errorf("frequency of %s = %.4f, want 1/3", r.Target, got[r.Target])
+		}
+	}
+}
+
+func TestOrder(t *testing.T) {
+	recs := records()
+	first := frequencies(t, func() *net.SRV {
+		ordered := Order(recs)
+		if len(ordered) != len(recs) {
+			t.Fatalf("len(Order()) = %d, want %d", len(ordered), len(recs))
+		}
+		seen := make(map[string]bool)
+		for i, r := range ordered {
+			if i > 0 && r.Priority < ordered[i-1].Priority {
+				t.Fatalf("Order() not in priority order: %v", ordered)
+			}
+			if seen[r.Target] {
+				t.Fatalf("Order() repeats %s", r.Target)
+			}
+			seen[r.Target] = true
+		}
+		if ordered[len(ordered)-1].Target != "backup." {
+			t.Fatalf("last = %s, want backup.", ordered[len(ordered)-1].Target)
+		}
+		return ordered[0]
+	})
+	want := map[string]float64{"a.": 60.0 / 100, "b.": 30.0 / 100, "c.": 9.0 / 100, "zero.": 1.0 / 100}
+	for target, w := range want {
+		if math.Abs(first[target]-w) > 0.005 {
+			t.Errorf("frequency of %s first = %.4f, want %.4f", target, first[target], w)
+		}
+	}
+	if recs[0].Target != "backup." {
+		t.Error("Order() modified its input")
+	}
+}
+
+func ExampleSelect() {
+	records := []*net.SRV{
+		{Target: "primary.example.com.", Port: 443, Priority: 10, Weight: 5},
+		{Target: "backup.example.com.", Port: 443, Priority: 20, Weight: 5},
+	}
+	failed := map[string]bool{"primary.example.com.": true}
+	r, _ := Select(records, func(r *net.SRV) bool { return !failed[r.Target] })
+	fmt.Println(r.Target)
+	// Output: backup.example.com.
+}
@@ -0,0 +1,102 @@
+package statcheck
+
+import (
+	"errors"
+	"math"
+)
+
+// ChiSquare performs Pearson's chi-square goodness of fit test of observed
+// counts against expected counts, returning the test statistic and its
+// p-value, the probability of a statistic at least as extreme occurring if the
+// observations follow the expected distribution.
+//
+// Categories with an expected count of zero must also have an observed count
+// of zero, and are excluded from the test.
+func ChiSquare(observed []int, expected []float64) (stat, pvalue float64, err error) {
+	if len(observed) != len(expected) {
+		return 0, 0, errors.New("statcheck: observed and expected differ in length")
+	}
+	categories := 0
+	for i, e := range expected {
+		o := float64(observed[i])
+		if e == 0 {
+			if o != 0 {
+				return math.Inf(1), 0, nil
+			}
+			continue
+		}
+		stat += (o - e) * (o - e) / e
+		categories++
+	}
+	if categories < 2 {
+		return 0, 1, nil // a single category always fits
+	}
+	return stat, ChiSquareSF(stat, categories-1), nil
+}
+
+// ChiSquareSF is the survival function (1 - CDF) of the chi-square
+// distribution with k degrees of freedom.
+func ChiSquareSF(x float64, k int) float64 {
+	if x <= 0 {
+		return 1
+	}
+	return gammaQ(float64(k)/2, x/2)
+}
+
+// gammaQ is the regularized upper incomplete gamma function Q(a, x), computed
+// by series expansion for x < a+1 and by continued fraction otherwise, as per
+// Numerical Recipes §6.2.
+func gammaQ(a, x float64) float64 {
+	if x < a+1 {
+		return 1 - gammaPSeries(a, x)
+	}
+	return gammaQContinuedFraction(a, x)
+}
+
+const (
+	gammaMaxIter = 1000
+	gammaEpsilon = 1e-15
+)
+
+func gammaPSeries(a, x float64) float64 {
+	lg, _ := math.Lgamma(a)
+	ap, sum := a, 1/a
+	del := sum
+	for n := 0; n < gammaMaxIter; n++ {
+		ap++
+		del *= x / ap
+		sum += del
+		if math.Abs(del) < math.Abs(sum)*gammaEpsilon {
+			break
+		}
+	}
+	return sum * math.Exp(-x+a*math.Log(x)-lg)
+}
+
+func gammaQContinuedFraction(a, x float64) float64 {
+	const tiny = 1e-300
+	lg, _ := math.Lgamma(a)
+	b := x + 1 - a
+	c := 1 / tiny
+	d := 1 / b
+	h := d
+	for i := 1; i <= gammaMaxIter; i++ {
+		an := -float64(i) * (float64(i) - a)
+		b += 2
+		d = an*d + b
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = b + an/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		del := d * c
+		h *= del
+		if math.Abs(del-1) < gammaEpsilon {
+			break
+		}
+	}
+	return math.Exp(-x+a*math.Log(x)-lg) * h
+}
@@ -0,0 +1,47 @@
+package statcheck
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestChiSquareSF(t *testing.T) {
+	// Reference critical values from standard chi-square tables.
+	tests := []struct {
+		x    float64
+		k    int
+		want float64
+	}{
+		{x: 0, k: 1, want: 1},
+		{x: 3.841, k: 1, want: 0.05},
+		{x: 6.635, k: 1, want: 0.01},
+		{x: 5.991, k: 2, want: 0.05},
+		{x: 18.307, k: 10, want: 0.05},
+		{x: 2.558, k: 10, want: 0.99},
+		{x: 124.342, k: 100, want: 0.05},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("x=%v/k=%d", tt.x, tt.k), func(t *testing.T) {
+			if got := ChiSquareSF(tt.x, tt.k); math.Abs(got-tt.want) > 5e-4 {
+				t.Errorf("ChiSquareSF() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChiSquare(t *testing.T) {
+	stat, p, err := ChiSquare([]int{10, 20, 30}, []float64{10, 20, 30})
+	if err != nil || stat != 0 || p != 1 {
+		t.Errorf("ChiSquare() = %v, %v, %v for perfect fit", stat, p, err)
+	}
+	if _, p, _ := ChiSquare([]int{1, 0}, []float64{0, 1}); p != 0 {
+		t.Errorf("ChiSquare() p = %v for observation of impossible category, want 0", p)
+	}
+	if _, p, _ := ChiSquare([]int{5, 0}, []float64{5, 0}); p != 1 {
+		t.Errorf("ChiSquare() p = %v for single category, want 1", p)
+	}
+	if _, _, err := ChiSquare([]int{1}, []float64{1, 2}); err == nil {
+		t.Error("expected error for mismatched lengths")
+	}
+}
@@ -0,0 +1,54 @@
+// Package statcheck provides statistical tests for verifying that a weighted
+// Chooser actually produces the distribution its weights describe, for use in
+// the tests of code which configures Choosers.
+package statcheck
+
+import (
+	"github.com/mroth/weightedrand/v2"
+	"github.com/mroth/weightedrand/v2/internal/constraints"
+	"github.com/mroth/weightedrand/v2/statstest"
+)
+
+// TB is the subset of testing.TB used by this package.
+type TB = statstest.TB
+
+// VerifyDistribution makes n picks from c, and performs a chi-square goodness
+// of fit test of the results against the Chooser's configured weights. The
+// test fails t if the hypothesis that picks follow the weights is rejected at
+// significance level alpha (e.g. 0.001), or if an item which should never be
+// picked is.
+//
+// Since a correct Chooser will still fail with probability alpha, choose a
+// small alpha to avoid flaky tests. n should be large enough that every
+// pickable choice has an expected count of at least 5.
+func VerifyDistribution[T comparable, W constraints.Integer](t TB, c *weightedrand.Chooser[T, W], n int, alpha float64) {
+	t.Helper()
+
+	choices := c.Choices()
+	counts := statstest.Frequencies(n, c.Pick)
+	expectedCounts := statstest.ExpectedCounts(choices, n)
+
+	items := make([]T, 0, len(expectedCounts))
+	for item := range expectedCounts {
+		items = append(items, item)
+	}
+	observed := make([]int, len(items))
+	expected := make([]float64, len(items))
+	for i, item := range items {
+		observed[i], expected[i] = counts[item], expectedCounts[item]
+		if expected[i] == 0 && observed[i] > 0 {
+			t.Errorf("statcheck: %v has zero weight but was picked %d times", item, observed[i])
+		} else if expected[i] > 0 && expected[i] < 5 {
+			t.Logf("statcheck: expected count for %v is %.2f, chi-square test may be unreliable", item, expected[i])
+		}
+	}
+
+	stat, p, err := ChiSquare(observed, expected)
+	if err != nil {
+		t.Errorf("%v", err)
+		return
+	}
+	if p < alpha {
+		t.Errorf("statcheck: picks do not match weights (chi-square = %.2f, p = %.3g < alpha = %g)", stat, p, alpha)
+	}
+}
@@ -0,0 +1,63 @@
+package statcheck
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mroth/weightedrand/v2"
+)
+
+func ExampleVerifyDistribution() {
+	// Within a test function, where t is the *testing.T:
+	var t testing.TB
+	chooser, _ := weightedrand.NewChooser(
+		weightedrand.NewChoice("a", 1),
+		weightedrand.NewChoice("b", 2),
+		weightedrand.NewChoice("c", 7),
+	)
+	VerifyDistribution(t, chooser, 100000, 0.0001)
+}
+
+// recorder is a TB which records failures.
+type recorder struct {
+	errors []string
+}
+
+func (r *recorder) Helper()                                 {}
+func (r *recorder) Logf(format string, args ...interface{}) {}
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestVerifyDistribution(t *testing.T) {
+	chooser, err := weightedrand.NewChooser(
+		weightedrand.NewChoice(0, 0),
+		weightedrand.NewChoice(1, 1),
+		weightedrand.NewChoice(2, 2),
+		weightedrand.NewChoice(2, 2), // duplicate item
+		weightedrand.NewChoice(3, 5),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var r recorder
+	VerifyDistribution(&r, chooser, 100000, 0.0001)
+	if len(r.errors) != 0 {
+		t.Errorf("unexpected failures: %v", r.errors)
+	}
+}
+
+// Picks skewed away from the configured weights must be rejected.
+func TestChiSquare_skewed(t *testing.T) {
+	chooser, err := weightedrand.NewChooser(weightedrand.NewChoice(0, 1), weightedrand.NewChoice(1, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	observed := make([]int, 2)
+	for i := 0; i < 10000; i++ {
+		observed[chooser.Pick()]++
+	}
+	if _, p, _ := ChiSquare(observed, []float64{2500, 7500}); p > 1e-6 {
+		t.Errorf("ChiSquare() p = %v for picks from 1:1 weights against 1:3", p)
+	}
+}
@@ -0,0 +1,114 @@
+// Package statstest provides utilities for testing that weighted random
+// selections follow their configured distribution: collecting empirical
+// frequencies, computing expected counts, and asserting the two agree within
+// a tolerance.
+//
+// For a rigorous test of fit, see the chi-square test in package statcheck.
+package statstest
+
+import (
+	"math"
+	"sort"
+
+	"github.com/mroth/weightedrand/v2"
+	"github.com/mroth/weightedrand/v2/internal/constraints"
+)
+
+// TB is the subset of testing.TB used by this package and statcheck.
+type TB interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+	Logf(format string, args ...interface{})
+}
+
+// Frequencies calls pick n times, returning the number of times each distinct
+// item was returned.
+func Frequencies[T comparable](n int, pick func() T) map[T]int {
+	counts := make(map[T]int)
+	for i := 0; i < n; i++ {
+		counts[pick()]++
+	}
+	return counts
+}
+
+// ExpectedCounts returns the expected number of times each item would be
+// picked in n picks from choices. Choices with a weight < 1 have an expected
+// count of zero, and duplicate items have their weights combined.
+func ExpectedCounts[T comparable, W constraints.Integer](choices []weightedrand.Choice[T, W], n int) map[T]float64 {
+	weights := make(map[T]float64, len(choices))
+	var total float64
+	for _, c := range choices {
+		w := 0.0
+		if c.Weight >= 1 {
+			w = float64(c.Weight)
+		}
+		weights[c.Item] += w
+		total += w
+	}
+	for item, w := range weights {
+		if total > 0 {
+			weights[item] = w / total * float64(n)
+		}
+	}
+	return weights
+}
+
+// AssertWithinTolerance fails t for each item whose observed proportion of all
+// picks differs from its expected proportion by more than tol, e.g. 0.01 for
+// one percentage point. It also fails t for any observed item which was not
+// expected at all.
+func AssertWithinTolerance[T comparable](t TB, observed map[T]int, expected map[T]float64, tol float64) {
+	t.Helper()
+	var n, expectedN float64
+	for _, c := range observed {
+		n += float64(c)
+	}
+	for _, e := range expected {
+		expectedN += e
+	}
+	if n == 0 || expectedN == 0 {
+		if n != expectedN {
+			t.Errorf("statstest: observed %v picks, expected %v", n, expectedN)
+		}
+		return
+	}
+
+	for item, e := range expected {
+		got, want := float64(observed[item])/n, e/expectedN
+		if math.Abs(got-want) > tol {
+			t.Errorf("statstest: %v picked with proportion %.4f, want %.4f ± %g", item, got, want, tol)
+		}
+	}
+	for item, c := range observed {
+		if _, ok := expected[item]; !ok {
+			t.Errorf("statstest: unexpected item %v picked %d times", item, c)
+		}
+	}
+}
+
+// AssertOrdered fails t unless items with a greater expected count were
+// observed strictly more often than those with a lesser expected count, and
+// items with an expected count of zero were never observed. This is a weak
+// but cheap check, useful with small numbers of picks.
+func AssertOrdered[T comparable](t TB, observed map[T]int, expected map[T]float64) {
+	t.Helper()
+	items := make([]T, 0, len(expected))
+	for item := range expected {
+		items = append(items, item)
+	}
+	sort.Slice(items, func(i, j int) bool { return expected[items[i]] < expected[items[j]] })
+
+	for i, item := range items {
+		if expected[item] == 0 && observed[item] != 0 {
+			t.Errorf("statstest: %v has zero expected count but was picked %d times", item, observed[item])
+		}
+		if i == 0 {
+			continue
+		}
+		prev := items[i-1]
+		if expected[prev] < expected[item] && observed[prev] >= observed[item] {
+			t.Errorf("statstest: %v picked %d times, not less than %v picked %d times",
+				prev, observed[prev], item, observed[item])
+		}
+	}
+}
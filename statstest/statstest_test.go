@@ -0,0 +1,95 @@
+package statstest
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mroth/weightedrand/v2"
+)
+
+// recorder is a TB which records failures.
+type recorder struct {
+	errors []string
+}
+
+func (r *recorder) Helper()                                 {}
+func (r *recorder) Logf(format string, args ...interface{}) {}
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestFrequencies(t *testing.T) {
+	i := 0
+	got := Frequencies(5, func() int { i++; return i % 2 })
+	if got[0] != 2 || got[1] != 3 {
+		t.Errorf("Frequencies() = %v", got)
+	}
+}
+
+func TestExpectedCounts(t *testing.T) {
+	got := ExpectedCounts([]weightedrand.Choice[string, int]{
+		weightedrand.NewChoice("a", 1),
+		weightedrand.NewChoice("b", 2),
+		weightedrand.NewChoice("b", 1),
+		weightedrand.NewChoice("c", -1),
+	}, 100)
+	want := map[string]float64{"a": 25, "b": 75, "c": 0}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("ExpectedCounts() = %v, want %v", got, want)
+	}
+}
+
+func TestAssertWithinTolerance(t *testing.T) {
+	expected := map[string]float64{"a": 25, "b": 75}
+	tests := []struct {
+		name     string
+		observed map[string]int
+		wantFail int
+	}{
+		{name: "exact", observed: map[string]int{"a": 25, "b": 75}},
+		{name: "within", observed: map[string]int{"a": 27, "b": 73}},
+		{name: "outside", observed: map[string]int{"a": 40, "b": 60}, wantFail: 2},
+		{name: "unexpected", observed: map[string]int{"a": 25, "b": 75, "z": 0}, wantFail: 1},
+		{name: "nothing", observed: map[string]int{}, wantFail: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var r recorder
+			AssertWithinTolerance(&r, tt.observed, expected, 0.05)
+			if len(r.errors) != tt.wantFail {
+				t.Errorf("got %d failures, want %d: %v", len(r.errors), tt.wantFail, r.errors)
+			}
+		})
+	}
+}
+
+func TestAssertOrdered(t *testing.T) {
+	expected := map[string]float64{"zero": 0, "a": 1, "b": 2}
+	var r recorder
+	AssertOrdered(&r, map[string]int{"a": 1, "b": 5}, expected)
+	if len(r.errors) != 0 {
+		t.Errorf("unexpected failures: %v", r.errors)
+	}
+	AssertOrdered(&r, map[string]int{"zero": 1, "a": 5, "b": 5}, expected)
+	if len(r.errors) != 2 {
+		t.Errorf("got %d failures, want 2: %v", len(r.errors), r.errors)
+	}
+}
+
+// The package utilities composed to test a real Chooser.
+func TestChooser(t *testing.T) {
+	choices := []weightedrand.Choice[rune, int]{
+		weightedrand.NewChoice('a', 1),
+		weightedrand.NewChoice('b', 3),
+		weightedrand.NewChoice('c', 6),
+	}
+	chooser, err := weightedrand.NewChooser(choices...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const n = 100000
+	observed := Frequencies(n, chooser.Pick)
+	expected := ExpectedCounts(choices, n)
+	AssertWithinTolerance(t, observed, expected, 0.01)
+	AssertOrdered(t, observed, expected)
+}
@@ -0,0 +1,95 @@
+package weightedrand
+
+import (
+	"errors"
+	"reflect"
+)
+
+// structTag is the struct tag identifying the weight field of structs passed to
+// NewChooserFromStructs.
+const structTag = "weightedrand"
+
+// Possible errors returned by NewChooserFromStructs.
+var (
+	errStructType  = errors.New("item type is not a struct or pointer to struct")
+	errStructField = errors.New(`item type must have exactly one integer field tagged weightedrand:"weight"`)
+)
+
+// NewChooserFromStructs initializes a new Chooser picking from items, which must
+// be structs or pointers to structs, weighted by the integer field tagged with
+// `weightedrand:"weight"`, for example:
+//
+//	type Backend struct {
+//		Addr   string
+//		Weight int `weightedrand:"weight"`
+//	}
+//
+// Only fields declared directly on the struct are considered. Nil pointers are
+// ignored, as are items with a negative weight, as with NewChooser.
+func NewChooserFromStructs[T any](items []T) (*Chooser[T, int], error) {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		return nil, errStructType
+	}
+	field, err := weightField(typ)
+	if err != nil {
+		return nil, err
+	}
+
+	choices := make([]Choice[T, int], 0, len(items))
+	for _, item := range items {
+		v := reflect.ValueOf(item)
+		if v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				continue
+			}
+			v = v.Elem()
+		}
+		f := v.Field(field)
+		var weight int
+		if f.CanInt() {
+			w := f.Int()
+			if w > maxInt {
+				return nil, errWeightOverflow
+			}
+			weight = int(w)
+		} else {
+			w := f.Uint()
+			if w > maxInt {
+				return nil, errWeightOverflow
+			}
+			weight = int(w)
+		}
+		choices = append(choices, NewChoice(item, weight))
+	}
+	return NewChooser(choices...)
+}
+
+// weightField returns the index of the sole integer field of the struct type
+// typ tagged as its weight.
+func weightField(typ reflect.Type) (int, error) {
+	index := -1
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if f.Tag.Get(structTag) != "weight" {
+			continue
+		}
+		switch f.Type.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		default:
+			return 0, errStructField
+		}
+		if index >= 0 {
+			return 0, errStructField
+		}
+		index = i
+	}
+	if index < 0 {
+		return 0, errStructField
+	}
+	return index, nil
+}
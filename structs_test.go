@@ -0,0 +1,75 @@
+package weightedrand
+
+import (
+	"fmt"
+	"testing"
+)
+
+func ExampleNewChooserFromStructs() {
+	type Backend struct {
+		Addr   string
+		Weight int `weightedrand:"weight"`
+	}
+	chooser, _ := NewChooserFromStructs([]Backend{
+		{Addr: "10.0.0.1", Weight: 0},
+		{Addr: "10.0.0.2", Weight: 5},
+	})
+	fmt.Println(chooser.Pick().Addr)
+	//Output: 10.0.0.2
+}
+
+func TestNewChooserFromStructs(t *testing.T) {
+	type unsigned struct {
+		Name   string
+		weight uint8 `weightedrand:"weight"`
+	}
+	c, err := NewChooserFromStructs([]*unsigned{{"a", 1}, nil, {"b", 0}, {"c", 3}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.max != 4 || len(c.data) != 3 {
+		t.Errorf("max = %d with %d choices, want 4 with 3", c.max, len(c.data))
+	}
+
+	type signed struct {
+		Weight int64 `weightedrand:"weight"`
+	}
+	c2, err := NewChooserFromStructs([]signed{{-5}, {2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c2.max != 2 {
+		t.Errorf("max = %d, want 2", c2.max)
+	}
+
+	type none struct{ Weight int }
+	type twice struct {
+		A int `weightedrand:"weight"`
+		B int `weightedrand:"weight"`
+	}
+	type notInteger struct {
+		Weight float64 `weightedrand:"weight"`
+	}
+	type overflow struct {
+		Weight uint64 `weightedrand:"weight"`
+	}
+	tests := []struct {
+		name    string
+		fn      func() error
+		wantErr error
+	}{
+		{"not a struct", func() error { _, err := NewChooserFromStructs([]int{1}); return err }, errStructType},
+		{"no tagged field", func() error { _, err := NewChooserFromStructs([]none{{1}}); return err }, errStructField},
+		{"two tagged fields", func() error { _, err := NewChooserFromStructs([]twice{{1, 1}}); return err }, errStructField},
+		{"non-integer field", func() error { _, err := NewChooserFromStructs([]notInteger{{1}}); return err }, errStructField},
+		{"overflow", func() error { _, err := NewChooserFromStructs([]overflow{{maxUint64}}); return err }, errWeightOverflow},
+		{"no valid choices", func() error { _, err := NewChooserFromStructs([]signed{{0}}); return err }, errNoValidChoices},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.fn(); err != tt.wantErr {
+				t.Errorf("NewChooserFromStructs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
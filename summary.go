@@ -0,0 +1,103 @@
+package weightedrand
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// Summary describes the distribution of weights a Chooser was constructed
+// from. It is intended for logging and sanity checking configuration, so that
+// callers do not need to dump entire choice tables to understand them.
+//
+// All statistics other than the ignored counts consider only the Choices with
+// a Weight >= 1, since those are the only ones that can ever be picked.
+type Summary[W integer] struct {
+	Count    int // number of pickable Choices (Weight >= 1)
+	Zero     int // number of Choices ignored for having a Weight of zero
+	Negative int // number of Choices ignored for having a negative Weight
+
+	Min  W       // smallest pickable Weight
+	Max  W       // largest pickable Weight
+	Mean float64 // mean of pickable Weights
+
+	// Nearest-rank percentiles of pickable Weights.
+	P50, P90, P99 W
+
+	// Histogram of pickable Weights in power of two buckets, where Histogram[i]
+	// is the number of Choices with a Weight in the range [2^i, 2^(i+1)).
+	Histogram []int
+}
+
+// String returns a compact single line representation of s suitable for logs.
+func (s Summary[W]) String() string {
+	return fmt.Sprintf("count=%d zero=%d negative=%d min=%d max=%d mean=%.2f p50=%d p90=%d p99=%d",
+		s.Count, s.Zero, s.Negative, s.Min, s.Max, s.Mean, s.P50, s.P90, s.P99)
+}
+
+// Summary returns statistics about the weights of the Choices the Chooser was
+// constructed from.
+func (c Chooser[T, W]) Summary() Summary[W] {
+	s := c.summary
+	s.Histogram = append([]int(nil), s.Histogram...)
+	return s
+}
+
+// summarize computes a Summary for choices, which must be sorted by ascending
+// weight and have a sum of positive weights equal to total.
+//
+// Since the choices are already sorted, everything can be derived from binary
+// searches rather than an additional pass over a potentially very large slice.
+// The backing array of hist is reused for the Histogram if large enough.
+func summarize[T any, W integer](choices []Choice[T, W], total int, hist []int) Summary[W] {
+	negEnd := searchWeights(choices, 0)
+	posStart := searchWeights(choices, 1)
+	s := Summary[W]{
+		Count:    len(choices) - posStart,
+		Zero:     posStart - negEnd,
+		Negative: negEnd,
+	}
+	if s.Count == 0 {
+		return s
+	}
+
+	pickable := choices[posStart:]
+	percentile := func(p int) W {
+		rank := (p*s.Count + 99) / 100 // ceil(p/100 * count)
+		return pickable[rank-1].Weight
+	}
+	s.Min = pickable[0].Weight
+	s.Max = pickable[len(pickable)-1].Weight
+	s.Mean = float64(total) / float64(s.Count)
+	s.P50, s.P90, s.P99 = percentile(50), percentile(90), percentile(99)
+
+	if n := bits.Len64(uint64(s.Max)); cap(hist) >= n {
+		s.Histogram = hist[:n]
+	} else {
+		s.Histogram = make([]int, n)
+	}
+	lo := 0
+	for i := range s.Histogram {
+		hi := len(pickable) // final bucket bound 2^(i+1) may overflow W
+		if i < len(s.Histogram)-1 {
+			hi = searchWeights(pickable, W(1)<<(i+1))
+		}
+		s.Histogram[i] = hi - lo
+		lo = hi
+	}
+	return s
+}
+
+// searchWeights returns the index of the first choice with a weight >= w, in
+// choices sorted by ascending weight.
+func searchWeights[T any, W integer](choices []Choice[T, W], w W) int {
+	i, j := 0, len(choices)
+	for i < j {
+		h := int(uint(i+j) >> 1)
+		if choices[h].Weight < w {
+			i = h + 1
+		} else {
+			j = h
+		}
+	}
+	return i
+}
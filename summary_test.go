@@ -0,0 +1,87 @@
+package weightedrand
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func ExampleChooser_Summary() {
+	chooser, _ := NewChooser(
+		NewChoice('🍒', 0),
+		NewChoice('🍋', 1),
+		NewChoice('🍊', 1),
+		NewChoice('🍉', 3),
+		NewChoice('🥑', 5),
+	)
+	fmt.Println(chooser.Summary())
+	//Output: count=4 zero=1 negative=0 min=1 max=5 mean=2.50 p50=1 p90=5 p99=5
+}
+
+func TestChooser_Summary(t *testing.T) {
+	tests := []struct {
+		name string
+		cs   []Choice[rune, int]
+		want Summary[int]
+	}{
+		{
+			name: "single choice",
+			cs:   []Choice[rune, int]{{Item: 'a', Weight: 1}},
+			want: Summary[int]{
+				Count: 1, Min: 1, Max: 1, Mean: 1, P50: 1, P90: 1, P99: 1,
+				Histogram: []int{1},
+			},
+		},
+		{
+			name: "ignored weights",
+			cs: []Choice[rune, int]{
+				{Item: 'a', Weight: -3}, {Item: 'b', Weight: 0}, {Item: 'c', Weight: 0},
+				{Item: 'd', Weight: 4}, {Item: 'e', Weight: -1},
+			},
+			want: Summary[int]{
+				Count: 1, Zero: 2, Negative: 2, Min: 4, Max: 4, Mean: 4, P50: 4, P90: 4, P99: 4,
+				Histogram: []int{0, 0, 1},
+			},
+		},
+		{
+			name: "percentiles",
+			cs:   mockSequentialChoices(100),
+			want: Summary[int]{
+				Count: 100, Min: 1, Max: 100, Mean: 50.5, P50: 50, P90: 90, P99: 99,
+				Histogram: []int{1, 2, 4, 8, 16, 32, 37},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewChooser(tt.cs...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := c.Summary(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Summary() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// Histogram bucket bounds must not overflow for narrow weight types.
+func TestChooser_Summary_narrowWeight(t *testing.T) {
+	c, err := NewChooser(NewChoice('a', int8(127)), NewChoice('b', int8(64)), NewChoice('c', int8(1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []int{1, 0, 0, 0, 0, 0, 2}
+	if got := c.Summary().Histogram; !reflect.DeepEqual(got, want) {
+		t.Errorf("Histogram = %v, want %v", got, want)
+	}
+}
+
+// mockSequentialChoices returns n choices with weights 1 through n.
+func mockSequentialChoices(n int) []Choice[rune, int] {
+	choices := make([]Choice[rune, int], 0, n)
+	for i := 1; i <= n; i++ {
+		choices = append(choices, NewChoice('🥑', i))
+	}
+	return choices
+}
@@ -0,0 +1,79 @@
+package weightedrand
+
+import (
+	"errors"
+	"math"
+)
+
+// Possible errors returned by ZipfWeights and GeometricWeights.
+var (
+	errSyntheticCount    = errors.New("number of choices is negative")
+	errZipfExponent      = errors.New("Zipf exponent is not finite")
+	errGeometricProb     = errors.New("geometric probability is not in the range (0, 1]")
+	errSyntheticOverflow = errors.New("relative weights overflow float64")
+)
+
+// ZipfWeights returns n Choices of the items 0 through n-1, weighted according
+// to a Zipf distribution with exponent s, such that item i has a weight
+// proportional to 1/(i+1)^s. This is the standard model for popularity skew in
+// synthetic workloads.
+//
+// Weights are scaled to sum to nearly half the platform's max int, preserving
+// as much precision as possible while remaining usable by NewChooser. Items
+// whose probability is too small to be represented receive a weight of zero.
+//
+// It returns an error if n < 0 or s is not finite, or if s is so negative that
+// the relative weights overflow before they can be scaled.
+func ZipfWeights(n int, s float64) ([]Choice[int, uint64], error) {
+	if n < 0 {
+		return nil, errSyntheticCount
+	}
+	if math.IsNaN(s) || math.IsInf(s, 0) {
+		return nil, errZipfExponent
+	}
+	return scaledChoices(n, func(i int) float64 {
+		return math.Pow(float64(i+1), -s)
+	})
+}
+
+// GeometricWeights returns n Choices of the items 0 through n-1, weighted
+// according to a geometric distribution with success probability p, such that
+// item i has a weight proportional to (1-p)^i.
+//
+// Weights are scaled as per ZipfWeights. It returns an error if n < 0 or p is
+// not in the range (0, 1].
+func GeometricWeights(n int, p float64) ([]Choice[int, uint64], error) {
+	if n < 0 {
+		return nil, errSyntheticCount
+	}
+	if !(p > 0 && p <= 1) {
+		return nil, errGeometricProb
+	}
+	q := 1 - p
+	return scaledChoices(n, func(i int) float64 {
+		return math.Pow(q, float64(i))
+	})
+}
+
+// scaledChoices returns n Choices with weights proportional to f(i), with the
+// total scaled to maxInt/2. It returns an error if the sum of f(i) is not
+// finite, since no weights could then be derived from it.
+func scaledChoices(n int, f func(i int) float64) ([]Choice[int, uint64], error) {
+	relative := make([]float64, n)
+	var sum float64
+	for i := range relative {
+		relative[i] = f(i)
+		sum += relative[i]
+	}
+	if !(sum <= math.MaxFloat64) {
+		return nil, errSyntheticOverflow
+	}
+
+	const scale = float64(maxInt / 2)
+	choices := make([]Choice[int, uint64], n)
+	for i, r := range relative {
+		// Floor rather than round, so that the total can never exceed scale.
+		choices[i] = Choice[int, uint64]{Item: i, Weight: uint64(math.Floor(r / sum * scale))}
+	}
+	return choices, nil
+}
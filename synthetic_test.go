@@ -0,0 +1,86 @@
+package weightedrand
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func ExampleZipfWeights() {
+	choices, _ := ZipfWeights(1000, 1.1)
+	chooser, _ := NewChooser(choices...)
+	fmt.Println(chooser.Summary().Count)
+	//Output: 1000
+}
+
+func TestZipfWeights(t *testing.T) {
+	for _, s := range []float64{0, 0.5, 1, 2} {
+		t.Run(fmt.Sprintf("s=%v", s), func(t *testing.T) {
+			choices, err := ZipfWeights(100, s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			verifyScaledChoices(t, choices, func(i int) float64 { return math.Pow(float64(i+1), -s) })
+		})
+	}
+	if got, err := ZipfWeights(0, 1); err != nil || len(got) != 0 {
+		t.Errorf("ZipfWeights(0) = %v, want empty", got)
+	}
+}
+
+func TestGeometricWeights(t *testing.T) {
+	for _, p := range []float64{0.01, 0.5, 1} {
+		t.Run(fmt.Sprintf("p=%v", p), func(t *testing.T) {
+			choices, err := GeometricWeights(100, p)
+			if err != nil {
+				t.Fatal(err)
+			}
+			verifyScaledChoices(t, choices, func(i int) float64 { return math.Pow(1-p, float64(i)) })
+		})
+	}
+}
+
+func TestSyntheticWeights_errors(t *testing.T) {
+	tests := map[string]struct {
+		fn      func() ([]Choice[int, uint64], error)
+		wantErr error
+	}{
+		"Zipf negative n":      {func() ([]Choice[int, uint64], error) { return ZipfWeights(-1, 1) }, errSyntheticCount},
+		"Zipf NaN s":           {func() ([]Choice[int, uint64], error) { return ZipfWeights(1, math.NaN()) }, errZipfExponent},
+		"Zipf infinite s":      {func() ([]Choice[int, uint64], error) { return ZipfWeights(1, math.Inf(-1)) }, errZipfExponent},
+		"Zipf overflowing s":   {func() ([]Choice[int, uint64], error) { return ZipfWeights(100, -500) }, errSyntheticOverflow},
+		"Geometric negative n": {func() ([]Choice[int, uint64], error) { return GeometricWeights(-1, 0.5) }, errSyntheticCount},
+		"Geometric zero p":     {func() ([]Choice[int, uint64], error) { return GeometricWeights(1, 0) }, errGeometricProb},
+		"Geometric p > 1":      {func() ([]Choice[int, uint64], error) { return GeometricWeights(1, 1.5) }, errGeometricProb},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := tt.fn(); err != tt.wantErr {
+				t.Errorf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// verifyScaledChoices checks that choices are usable by NewChooser and have
+// probabilities matching the relative weights given by f.
+func verifyScaledChoices(t *testing.T, choices []Choice[int, uint64], f func(i int) float64) {
+	t.Helper()
+	var sum float64
+	for i := range choices {
+		sum += f(i)
+	}
+	chooser, err := NewChooser(append([]Choice[int, uint64](nil), choices...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, c := range choices {
+		if c.Item != i {
+			t.Fatalf("choices[%d].Item = %d", i, c.Item)
+		}
+		got, want := float64(c.Weight)/float64(chooser.max), f(i)/sum
+		if math.Abs(got-want) > 1e-9 {
+			t.Errorf("probability of %d = %v, want %v", i, got, want)
+		}
+	}
+}
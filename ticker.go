@@ -0,0 +1,67 @@
+package weightedrand
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var errTickerInterval = errors.New("Choice with Weight >= 1 has non-positive interval")
+
+// A Ticker delivers ticks at intervals picked at random from a weighted set of
+// durations, such as mostly one second but occasionally ten, for chaos testing
+// or simulating the irregular behavior of users.
+//
+// As with time.Ticker, ticks are dropped rather than queued for slow receivers,
+// and the next interval starts as soon as each tick is delivered or dropped.
+type Ticker struct {
+	C <-chan time.Time // The channel on which the ticks are delivered.
+
+	cancel context.CancelFunc
+}
+
+// NewTicker returns a new Ticker sending the current time on its channel after
+// each interval, picked from intervals with probability proportional to its
+// weight. The Ticker runs until ctx is done or Stop is called, after which
+// its channel is closed.
+//
+// Intervals with a weight < 1 are never picked; all others must be positive.
+func NewTicker[W integer](ctx context.Context, intervals ...Choice[time.Duration, W]) (*Ticker, error) {
+	for _, c := range intervals {
+		if c.Weight >= 1 && c.Item <= 0 {
+			return nil, errTickerInterval
+		}
+	}
+	// The Chooser takes ownership of its choices, so the caller's are copied.
+	chooser, err := NewChooser(append([]Choice[time.Duration, W](nil), intervals...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	ch := make(chan time.Time, 1)
+	go func() {
+		defer close(ch)
+		timer := time.NewTimer(chooser.Pick())
+		defer timer.Stop()
+		for {
+			select {
+			case now := <-timer.C:
+				select {
+				case ch <- now:
+				default:
+				}
+				timer.Reset(chooser.Pick())
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return &Ticker{C: ch, cancel: cancel}, nil
+}
+
+// Stop turns off the Ticker, releasing its resources. Its channel is closed
+// shortly after, though a tick already delivered may still be received first.
+func (t *Ticker) Stop() {
+	t.cancel()
+}
@@ -0,0 +1,109 @@
+package weightedrand
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func ExampleNewTicker() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ticker, _ := NewTicker(ctx,
+		NewChoice(10*time.Millisecond, 9),
+		NewChoice(50*time.Millisecond, 1),
+	)
+	for i := 0; i < 3; i++ {
+		<-ticker.C
+		fmt.Println("poll")
+	}
+	// Output:
+	// poll
+	// poll
+	// poll
+}
+
+func TestNewTicker(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name      string
+		intervals []Choice[time.Duration, int]
+		wantErr   error
+	}{
+		{name: "no intervals", wantErr: errNoValidChoices},
+		{name: "zero weights", intervals: []Choice[time.Duration, int]{{time.Second, 0}}, wantErr: errNoValidChoices},
+		{name: "zero interval", intervals: []Choice[time.Duration, int]{{0, 1}}, wantErr: errTickerInterval},
+		{name: "negative interval", intervals: []Choice[time.Duration, int]{{time.Second, 1}, {-time.Second, 1}}, wantErr: errTickerInterval},
+		{name: "unpickable zero interval", intervals: []Choice[time.Duration, int]{{time.Second, 1}, {0, 0}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ticker, err := NewTicker(ctx, tt.intervals...)
+			if err != tt.wantErr {
+				t.Fatalf("NewTicker() error = %v, want %v", err, tt.wantErr)
+			}
+			if ticker != nil {
+				ticker.Stop()
+			}
+		})
+	}
+}
+
+// waitClosed fails the test unless ch is closed within a few seconds, draining
+// any tick already delivered.
+func waitClosed(t *testing.T, ch <-chan time.Time) {
+	t.Helper()
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case _, ok := <-ch:
+			if !ok {
+				return
+			}
+		case <-timeout:
+			t.Fatal("timed out waiting for ticker channel to close")
+		}
+	}
+}
+
+func TestTicker(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	// The hour is never picked, so ticks arrive every millisecond.
+	ticker, err := NewTicker(ctx, NewChoice(time.Millisecond, 1), NewChoice(time.Hour, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	timeout := time.After(5 * time.Second)
+	for i := 0; i < 5; i++ {
+		select {
+		case <-ticker.C:
+		case <-timeout:
+			t.Fatalf("received only %d ticks", i)
+		}
+	}
+	cancel()
+	waitClosed(t, ticker.C)
+}
+
+func TestTicker_Stop(t *testing.T) {
+	ticker, err := NewTicker(context.Background(), NewChoice(time.Hour, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ticker.Stop()
+	ticker.Stop() // no-op
+	waitClosed(t, ticker.C)
+}
+
+func TestNewTicker_noReorder(t *testing.T) {
+	intervals := []Choice[time.Duration, int]{NewChoice(time.Hour, 3), NewChoice(2*time.Hour, 1)}
+	ticker, err := NewTicker(context.Background(), intervals...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ticker.Stop()
+	if intervals[0].Item != time.Hour || intervals[1].Item != 2*time.Hour {
+		t.Errorf("NewTicker reordered the caller's intervals: %v", intervals)
+	}
+}
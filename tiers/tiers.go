@@ -0,0 +1,179 @@
+// Package tiers groups weighted choices into named rarity tiers, such as
+// common, rare and epic, where each tier has its own weight and items are
+// picked by weight within their tier.
+//
+// Tiers may be picked in two stages, first a tier and then an item within it,
+// with New, or flattened into a single weightedrand.Chooser with Flatten.
+package tiers
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+
+	"github.com/mroth/weightedrand/v2"
+	"github.com/mroth/weightedrand/v2/internal/constraints"
+)
+
+// A Tier is a named group of items, picked with probability proportional to
+// its Weight among all tiers.
+type Tier[T any, W constraints.Integer] struct {
+	Name   string
+	Weight W
+	Items  []weightedrand.Choice[T, W]
+	// Uniform, if true, makes every item in the tier equally likely,
+	// ignoring the weights of Items.
+	Uniform bool
+}
+
+// ErrWeightOverflow is returned by Flatten if the flattened weights would
+// exceed the maximum int value for the platform.
+var ErrWeightOverflow = errors.New("tiers: flattened weights exceed max int")
+
+// effective returns the weights of the items of t which can be picked, and
+// their sum.
+func (t Tier[T, W]) effective() ([]weightedrand.Choice[T, uint64], uint64, error) {
+	var items []weightedrand.Choice[T, uint64]
+	var sum uint64
+	for _, c := range t.Items {
+		if c.Weight < 1 {
+			continue
+		}
+		w := uint64(1)
+		if !t.Uniform {
+			w = uint64(c.Weight)
+		}
+		if sum+w < sum {
+			return nil, 0, ErrWeightOverflow
+		}
+		sum += w
+		items = append(items, weightedrand.NewChoice(c.Item, w))
+	}
+	if len(items) == 0 {
+		return nil, 0, fmt.Errorf("tiers: tier %q has weight but no items with weight >= 1", t.Name)
+	}
+	return items, sum, nil
+}
+
+// A Picker picks a tier and then an item within it. It is safe for concurrent
+// usage.
+type Picker[T any] struct {
+	tiers *weightedrand.Chooser[int, uint64] // index into names and items
+	names []string
+	items []*weightedrand.Chooser[T, uint64]
+}
+
+// New initializes a Picker over tiers. Tiers with a weight < 1 are ignored, and
+// it is an error for any other tier to have no items with a weight >= 1.
+func New[T any, W constraints.Integer](tiers ...Tier[T, W]) (*Picker[T], error) {
+	p := &Picker[T]{}
+	var choices []weightedrand.Choice[int, uint64]
+	for _, t := range tiers {
+		if t.Weight < 1 {
+			continue
+		}
+		items, _, err := t.effective()
+		if err != nil {
+			return nil, err
+		}
+		c, err := weightedrand.NewChooser(items...)
+		if err != nil {
+			return nil, fmt.Errorf("tiers: tier %q: %w", t.Name, err)
+		}
+		choices = append(choices, weightedrand.NewChoice(len(p.names), uint64(t.Weight)))
+		p.names = append(p.names, t.Name)
+		p.items = append(p.items, c)
+	}
+	c, err := weightedrand.NewChooser(choices...)
+	if err != nil {
+		return nil, fmt.Errorf("tiers: %w", err)
+	}
+	p.tiers = c
+	return p, nil
+}
+
+// Pick returns a weighted random item from a weighted random tier.
+func (p *Picker[T]) Pick() T {
+	_, item := p.PickTier()
+	return item
+}
+
+// PickTier is like Pick, but also returns the name of the tier picked from.
+func (p *Picker[T]) PickTier() (string, T) {
+	i := p.tiers.Pick()
+	return p.names[i], p.items[i].Pick()
+}
+
+// Flatten returns a single Chooser picking items with the same probabilities
+// as a Picker over tiers: the probability of each tier multiplied by that of
+// the item within it. An item appearing in more than one tier appears more
+// than once in the Chooser.
+//
+// Weights are scaled to integers by the least common multiple of the sums of
+// the weights of each tier, so for many tiers of large, coprime sums they may
+// overflow, in which case New should be used instead.
+func Flatten[T any, W constraints.Integer](tiers ...Tier[T, W]) (*weightedrand.Chooser[T, uint64], error) {
+	type tier struct {
+		weight uint64
+		items  []weightedrand.Choice[T, uint64]
+		sum    uint64
+	}
+	var valid []tier
+	lcm := uint64(1)
+	for _, t := range tiers {
+		if t.Weight < 1 {
+			continue
+		}
+		items, sum, err := t.effective()
+		if err != nil {
+			return nil, err
+		}
+		if lcm, err = mul(lcm/gcd(lcm, sum), sum); err != nil {
+			return nil, err
+		}
+		valid = append(valid, tier{weight: uint64(t.Weight), items: items, sum: sum})
+	}
+
+	var choices []weightedrand.Choice[T, uint64]
+	var g uint64
+	for _, t := range valid {
+		for _, c := range t.items {
+			// weight * item weight / sum, scaled by lcm
+			w, err := mul(t.weight, lcm/t.sum)
+			if err == nil {
+				w, err = mul(w, c.Weight)
+			}
+			if err != nil {
+				return nil, err
+			}
+			g = gcd(g, w)
+			choices = append(choices, weightedrand.NewChoice(c.Item, w))
+		}
+	}
+	for i := range choices {
+		choices[i].Weight /= g // smallest integer weights in the same proportions
+	}
+	c, err := weightedrand.NewChooser(choices...)
+	if err != nil {
+		if len(choices) > 0 {
+			return nil, ErrWeightOverflow
+		}
+		return nil, fmt.Errorf("tiers: %w", err)
+	}
+	return c, nil
+}
+
+func mul(a, b uint64) (uint64, error) {
+	hi, lo := bits.Mul64(a, b)
+	if hi != 0 {
+		return 0, ErrWeightOverflow
+	}
+	return lo, nil
+}
+
+func gcd(a, b uint64) uint64 {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
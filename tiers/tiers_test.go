@@ -0,0 +1,86 @@
+package tiers
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/mroth/weightedrand/v2"
+)
+
+var gacha = []Tier[string, int]{
+	{Name: "common", Weight: 70, Items: []weightedrand.Choice[string, int]{
+		weightedrand.NewChoice("a", 1), weightedrand.NewChoice("b", 1),
+	}},
+	{Name: "rare", Weight: 25, Uniform: true, Items: []weightedrand.Choice[string, int]{
+		weightedrand.NewChoice("c", 5), weightedrand.NewChoice("d", 100), weightedrand.NewChoice("never", 0),
+	}},
+	{Name: "epic", Weight: 5, Items: []weightedrand.Choice[string, int]{
+		weightedrand.NewChoice("e", 3),
+	}},
+	{Name: "disabled", Weight: 0},
+}
+
+func ExamplePicker_PickTier() {
+	p, _ := New(
+		Tier[string, int]{Name: "common", Weight: 0, Items: []weightedrand.Choice[string, int]{weightedrand.NewChoice("sock", 1)}},
+		Tier[string, int]{Name: "epic", Weight: 1, Items: []weightedrand.Choice[string, int]{weightedrand.NewChoice("sword", 1)}},
+	)
+	fmt.Println(p.PickTier())
+	//Output: epic sword
+}
+
+func TestFlatten(t *testing.T) {
+	c, err := Flatten(gacha...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[string]uint64)
+	for _, choice := range c.Choices() {
+		got[choice.Item] = choice.Weight
+	}
+	// a = 70% * 1/2 = 14/40, c = 25% * 1/2 = 5/40, e = 5% = 2/40
+	want := map[string]uint64{"a": 14, "b": 14, "c": 5, "d": 5, "e": 2}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("flattened weights = %v, want %v", got, want)
+	}
+
+	huge := []Tier[int, uint64]{
+		{Name: "x", Weight: 1 << 40, Items: []weightedrand.Choice[int, uint64]{weightedrand.NewChoice(1, uint64(1)), weightedrand.NewChoice(2, uint64(1<<40))}},
+		{Name: "y", Weight: 1, Items: []weightedrand.Choice[int, uint64]{weightedrand.NewChoice(3, uint64(3))}},
+	}
+	if _, err := Flatten(huge...); err != ErrWeightOverflow {
+		t.Errorf("Flatten() error = %v, want %v", err, ErrWeightOverflow)
+	}
+}
+
+func TestNew(t *testing.T) {
+	p, err := New(gacha...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const n = 40000
+	counts := make(map[string]int)
+	for i := 0; i < n; i++ {
+		tier, item := p.PickTier()
+		counts[tier]++
+		counts[item]++
+	}
+	for key, want := range map[string]float64{"common": 0.7, "rare": 0.25, "epic": 0.05, "a": 0.35, "c": 0.125, "d": 0.125} {
+		if got := float64(counts[key]) / n; math.Abs(got-want) > 0.015 {
+			t.Errorf("frequency of %s = %.3f, want %.3f", key, got, want)
+		}
+	}
+	if counts["never"] != 0 || counts["disabled"] != 0 {
+		t.Errorf("picked excluded tier or item: %v", counts)
+	}
+
+	empty := Tier[string, int]{Name: "empty", Weight: 1}
+	if _, err := New(empty); err == nil || !strings.Contains(err.Error(), `tier "empty" has weight but no items`) {
+		t.Errorf("New() error = %v, want empty tier error", err)
+	}
+	if _, err := New[string, int](); err == nil {
+		t.Error("New() with no tiers should return an error")
+	}
+}
@@ -0,0 +1,67 @@
+package weightedrand
+
+import (
+	"container/heap"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// WeightedTopK returns the first k items of a weighted random shuffle of
+// choices, that is, a weighted random sample of k items without replacement,
+// in the order they would have been selected.
+//
+// This uses the method of Efraimidis and Spirakis, assigning each choice a
+// random key of u^(1/weight) and keeping the k largest in a heap, so it runs in
+// O(n log k) time rather than the O(n log n) of a full shuffle.
+//
+// Choices with a weight < 1 are never selected, so fewer than k items are
+// returned if there are not enough other choices. The provided slice is not
+// modified.
+func WeightedTopK[T any, W integer](k int, choices ...Choice[T, W]) []T {
+	if k <= 0 {
+		return nil
+	}
+	h := make(keyHeap, 0, k)
+	for i, c := range choices {
+		if c.Weight < 1 {
+			continue
+		}
+		// Compare keys in log space, log(u^(1/w)) = log(u)/w, for precision
+		// with large weights. u is in (0, 1] so that the log is finite.
+		key := math.Log(1-rand.Float64()) / float64(c.Weight)
+		if len(h) < k {
+			heap.Push(&h, keyedIndex{key: key, index: i})
+		} else if key > h[0].key {
+			h[0] = keyedIndex{key: key, index: i}
+			heap.Fix(&h, 0)
+		}
+	}
+
+	sort.Slice(h, func(i, j int) bool { return h[i].key > h[j].key })
+	items := make([]T, len(h))
+	for i, ki := range h {
+		items[i] = choices[ki.index].Item
+	}
+	return items
+}
+
+type keyedIndex struct {
+	key   float64
+	index int
+}
+
+// keyHeap is a min-heap of keys, implementing heap.Interface.
+type keyHeap []keyedIndex
+
+func (h keyHeap) Len() int            { return len(h) }
+func (h keyHeap) Less(i, j int) bool  { return h[i].key < h[j].key }
+func (h keyHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *keyHeap) Push(x interface{}) { *h = append(*h, x.(keyedIndex)) }
+func (h *keyHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
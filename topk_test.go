@@ -0,0 +1,65 @@
+package weightedrand
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func ExampleWeightedTopK() {
+	recommendations := WeightedTopK(2,
+		NewChoice("never", 0),
+		NewChoice("always", 1_000_000_000),
+		NewChoice("sometimes", 1),
+	)
+	fmt.Println(recommendations)
+	//Output: [always sometimes]
+}
+
+func TestWeightedTopK(t *testing.T) {
+	choices := []Choice[int, int]{{0, 1}, {1, 2}, {2, 3}, {3, 4}, {4, 0}}
+	if got := WeightedTopK(0, choices...); len(got) != 0 {
+		t.Errorf("WeightedTopK(0) = %v, want empty", got)
+	}
+	if got := WeightedTopK(10, choices...); len(got) != 4 {
+		t.Errorf("WeightedTopK(10) = %v, want all 4 pickable items", got)
+	}
+
+	// The first position of a weighted shuffle is a single weighted pick, and
+	// the second is a weighted pick among the remainder.
+	const n = 100000
+	first := make([]int, len(choices))
+	second := make(map[int]int)
+	for i := 0; i < n; i++ {
+		got := WeightedTopK(2, choices...)
+		if len(got) != 2 || got[0] == got[1] {
+			t.Fatalf("WeightedTopK(2) = %v", got)
+		}
+		first[got[0]]++
+		if got[0] == 3 {
+			second[got[1]]++
+		}
+	}
+	for i, c := range choices {
+		if got, want := float64(first[i])/n, float64(c.Weight)/10; math.Abs(got-want) > 0.01 {
+			t.Errorf("first position %d frequency = %v, want %v", i, got, want)
+		}
+	}
+	for item, want := range map[int]float64{0: 1.0 / 6, 1: 2.0 / 6, 2: 3.0 / 6} {
+		if got := float64(second[item]) / float64(first[3]); math.Abs(got-want) > 0.02 {
+			t.Errorf("second position %d frequency given first 3 = %v, want %v", item, got, want)
+		}
+	}
+}
+
+func BenchmarkWeightedTopK(b *testing.B) {
+	for n := BMMinChoices; n <= BMMaxChoices/100; n *= 10 {
+		b.Run(fmt.Sprintf("size=%s", fmt1eN(n)), func(b *testing.B) {
+			choices := mockChoices(n)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_ = WeightedTopK(5, choices...)
+			}
+		})
+	}
+}
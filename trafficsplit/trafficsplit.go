@@ -0,0 +1,178 @@
+// Package trafficsplit assigns HTTP requests to the variants of an experiment
+// or rollout, such as "control" and "treatment", by configured percentages.
+//
+// Assignments can be made sticky, so that a client sees the same variant on
+// every request: by persisting the assignment in a cookie, or by consistently
+// hashing the value of a request header such as a user ID. Header assignments
+// use rendezvous hashing, so changing the percentages only reassigns the
+// minimum share of clients needed to reach them.
+package trafficsplit
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/mroth/weightedrand/v2"
+	"github.com/mroth/weightedrand/v2/rendezvous"
+)
+
+// Possible errors returned by New.
+var (
+	ErrEmptyName        = errors.New("trafficsplit: variant with empty name")
+	ErrDuplicateVariant = errors.New("trafficsplit: duplicate variant name")
+)
+
+// A Variant is a named share of traffic.
+type Variant = weightedrand.PercentChoice[string]
+
+// An Option configures a Split.
+type Option func(*config)
+
+type config struct {
+	cookie       string
+	cookieMaxAge time.Duration
+	header       string
+}
+
+// WithCookie persists each client's assignment in a cookie with the given
+// name, valid for maxAge or for the browser session if maxAge is zero. A
+// request carrying the cookie keeps its variant, unless that variant's
+// percentage has since been set to zero.
+func WithCookie(name string, maxAge time.Duration) Option {
+	return func(cfg *config) { cfg.cookie, cfg.cookieMaxAge = name, maxAge }
+}
+
+// WithHeader assigns requests carrying the named header by consistently
+// hashing its value, so that all requests with the same value are assigned to
+// the same variant, even across processes. A cookie set WithCookie takes
+// precedence over the header.
+func WithHeader(name string) Option {
+	return func(cfg *config) { cfg.header = name }
+}
+
+// A Split assigns requests to variants. It is safe for concurrent usage.
+type Split struct {
+	cfg     config
+	index   map[string]int // of variants with a percentage > 0
+	names   []string
+	counts  []uint64 // accessed atomically
+	chooser *weightedrand.Chooser[int, int]
+	hash    *rendezvous.Hash[string]
+}
+
+// New initializes a Split among variants, whose percentages must sum to 100.
+func New(variants []Variant, opts ...Option) (*Split, error) {
+	s := &Split{
+		index:  make(map[string]int, len(variants)),
+		names:  make([]string, len(variants)),
+		counts: make([]uint64, len(variants)),
+	}
+	for _, opt := range opts {
+		opt(&s.cfg)
+	}
+
+	percents := make([]weightedrand.PercentChoice[int], len(variants))
+	weights := make([]weightedrand.Choice[string, int], len(variants))
+	seen := make(map[string]bool, len(variants))
+	for i, v := range variants {
+		if v.Item == "" {
+			return nil, ErrEmptyName
+		}
+		if seen[v.Item] {
+			return nil, ErrDuplicateVariant
+		}
+		seen[v.Item] = true
+		s.names[i] = v.Item
+		if v.Percent > 0 {
+			s.index[v.Item] = i
+		}
+		percents[i] = weightedrand.PercentChoice[int]{Item: i, Percent: v.Percent}
+	}
+	var err error
+	if s.chooser, err = weightedrand.NewChooserFromPercents(percents...); err != nil {
+		return nil, err
+	}
+
+	// Variants are hashed by name, so that header assignments do not depend
+	// on the order of variants, at a resolution of a millionth of a percent.
+	for i, v := range variants {
+		weights[i] = weightedrand.NewChoice(v.Item, int(math.Round(v.Percent*1e6)))
+	}
+	if s.hash, err = rendezvous.New(weights...); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Assign returns the name of the variant for r, setting the cookie for it on
+// w if the Split was configured WithCookie and r does not already carry it.
+// Each call is counted as an assignment to the variant.
+func (s *Split) Assign(w http.ResponseWriter, r *http.Request) string {
+	i, fromCookie := s.assign(r)
+	atomic.AddUint64(&s.counts[i], 1)
+	if s.cfg.cookie != "" && !fromCookie {
+		c := &http.Cookie{
+			Name:     s.cfg.cookie,
+			Value:    s.names[i],
+			Path:     "/",
+			HttpOnly: true,
+			Secure:   r.TLS != nil,
+			SameSite: http.SameSiteLaxMode,
+		}
+		if s.cfg.cookieMaxAge > 0 {
+			c.MaxAge = int(s.cfg.cookieMaxAge / time.Second)
+		}
+		http.SetCookie(w, c)
+	}
+	return s.names[i]
+}
+
+// assign returns the index of the variant for r, and whether it was taken
+// from r's cookie.
+func (s *Split) assign(r *http.Request) (int, bool) {
+	if s.cfg.cookie != "" {
+		if c, err := r.Cookie(s.cfg.cookie); err == nil {
+			if i, ok := s.index[c.Value]; ok {
+				return i, true
+			}
+		}
+	}
+	if s.cfg.header != "" {
+		if key := r.Header.Get(s.cfg.header); key != "" {
+			return s.index[s.hash.Pick(key)], false
+		}
+	}
+	return s.chooser.Pick(), false
+}
+
+// Counts returns the number of assignments made to each variant by name.
+func (s *Split) Counts() map[string]uint64 {
+	counts := make(map[string]uint64, len(s.names))
+	for i, name := range s.names {
+		counts[name] = atomic.LoadUint64(&s.counts[i])
+	}
+	return counts
+}
+
+type contextKey struct{}
+
+// Middleware returns a handler which assigns each request to a variant before
+// passing it to next, with the variant available from the request's context
+// by FromContext.
+func (s *Split) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		v := s.Assign(w, r)
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), contextKey{}, v)))
+	})
+}
+
+// FromContext returns the variant assigned to a request by Middleware, from
+// the request's context.
+func FromContext(ctx context.Context) (string, bool) {
+	v, ok := ctx.Value(contextKey{}).(string)
+	return v, ok
+}
@@ -0,0 +1,153 @@
+package trafficsplit
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSplit_random(t *testing.T) {
+	s, err := New([]Variant{{Item: "control", Percent: 90}, {Item: "treatment", Percent: 10}, {Item: "off", Percent: 0}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	const n = 20000
+	for i := 0; i < n; i++ {
+		rec := httptest.NewRecorder()
+		s.Assign(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		if len(rec.Result().Cookies()) != 0 {
+			t.Fatal("set cookie without WithCookie")
+		}
+	}
+	counts := s.Counts()
+	if got := float64(counts["treatment"]) / n; math.Abs(got-0.1) > 0.01 {
+		t.Errorf("treatment share = %.3f, want 0.1", got)
+	}
+	if counts["off"] != 0 || counts["control"]+counts["treatment"] != n {
+		t.Errorf("Counts() = %v", counts)
+	}
+}
+
+func TestSplit_cookie(t *testing.T) {
+	s, err := New([]Variant{{Item: "a", Percent: 50}, {Item: "b", Percent: 50}, {Item: "retired", Percent: 0}},
+		WithCookie("variant", time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec := httptest.NewRecorder()
+	v := s.Assign(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	cookies := rec.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != "variant" || cookies[0].Value != v || cookies[0].MaxAge != 3600 {
+		t.Fatalf("cookies = %v, want variant=%s", cookies, v)
+	}
+
+	// Returning clients keep their variant without the cookie being set again.
+	for i := 0; i < 50; i++ {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.AddCookie(cookies[0])
+		rec := httptest.NewRecorder()
+		if got := s.Assign(rec, req); got != v {
+			t.Fatalf("returning client assigned %s, want %s", got, v)
+		}
+		if len(rec.Result().Cookies()) != 0 {
+			t.Fatal("cookie set again for returning client")
+		}
+	}
+
+	// Clients of retired or unknown variants are reassigned.
+	for _, old := range []string{"retired", "unknown"} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.AddCookie(&http.Cookie{Name: "variant", Value: old})
+		rec := httptest.NewRecorder()
+		got := s.Assign(rec, req)
+		if got != "a" && got != "b" {
+			t.Errorf("client of %s variant assigned %s", old, got)
+		}
+		if c := rec.Result().Cookies(); len(c) != 1 || c[0].Value != got {
+			t.Errorf("cookies = %v, want variant=%s", c, got)
+		}
+	}
+}
+
+func TestSplit_header(t *testing.T) {
+	variants := []Variant{{Item: "a", Percent: 70}, {Item: "b", Percent: 30}}
+	s, err := New(variants, WithHeader("X-User-ID"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Reversing the variants must not change any assignments.
+	reversed, err := New([]Variant{variants[1], variants[0]}, WithHeader("X-User-ID"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const users = 10000
+	for u := 0; u < users; u++ {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("X-User-ID", fmt.Sprint("user-", u))
+		first := s.Assign(httptest.NewRecorder(), req)
+		if again := s.Assign(httptest.NewRecorder(), req); again != first {
+			t.Fatalf("user %d assigned %s, then %s", u, first, again)
+		}
+		if other := reversed.Assign(httptest.NewRecorder(), req); other != first {
+			t.Fatalf("user %d assigned %s, but %s with reordered variants", u, first, other)
+		}
+	}
+	if got := float64(s.Counts()["b"]) / (2 * users); math.Abs(got-0.3) > 0.02 {
+		t.Errorf("share of b = %.3f, want 0.3", got)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	s, err := New([]Variant{{Item: "only", Percent: 100}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got string
+	h := s.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, _ = FromContext(r.Context())
+	}))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	if got != "only" {
+		t.Errorf("FromContext() = %q, want only", got)
+	}
+	if _, ok := FromContext(httptest.NewRequest(http.MethodGet, "/", nil).Context()); ok {
+		t.Error("FromContext() reported a variant for an unassigned request")
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name     string
+		variants []Variant
+		wantErr  error
+	}{
+		{name: "empty name", variants: []Variant{{Item: "", Percent: 100}}, wantErr: ErrEmptyName},
+		{name: "duplicate", variants: []Variant{{Item: "a", Percent: 50}, {Item: "a", Percent: 50}}, wantErr: ErrDuplicateVariant},
+		{name: "bad sum", variants: []Variant{{Item: "a", Percent: 50}, {Item: "b", Percent: 40}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(tt.variants)
+			if err == nil || (tt.wantErr != nil && err != tt.wantErr) {
+				t.Errorf("New() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func ExampleSplit_Middleware() {
+	split, _ := New([]Variant{
+		{Item: "old-checkout", Percent: 95},
+		{Item: "new-checkout", Percent: 5},
+	}, WithCookie("checkout", 30*24*time.Hour), WithHeader("X-User-ID"))
+
+	http.Handle("/checkout", split.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		variant, _ := FromContext(r.Context())
+		fmt.Fprintln(w, "rendering", variant)
+	})))
+}
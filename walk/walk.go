@@ -0,0 +1,76 @@
+// Package walk performs weighted random walks over graphs, in which each step
+// follows an outgoing edge of the current node with probability proportional
+// to its weight, as used for graph sampling and DeepWalk-style embeddings.
+package walk
+
+import (
+	"sync"
+
+	"github.com/mroth/weightedrand/v2"
+	"github.com/mroth/weightedrand/v2/internal/constraints"
+)
+
+// A Walker performs random walks over a graph. The Chooser for the edges of
+// each node is built the first time the node is visited and cached for
+// subsequent visits, so walks over large graphs only pay for the nodes they
+// reach. It is safe for concurrent usage.
+type Walker[T comparable, W constraints.Integer] struct {
+	edges func(node T) []weightedrand.Choice[T, W]
+
+	mu    sync.RWMutex
+	cache map[T]*weightedrand.Chooser[T, W] // nil for nodes without edges
+}
+
+// New initializes a Walker over the graph whose weighted outgoing edges from
+// each node are returned by edges. The graph must not change while the Walker
+// is in use, since edges are cached. The returned slice is not retained.
+func New[T comparable, W constraints.Integer](edges func(node T) []weightedrand.Choice[T, W]) *Walker[T, W] {
+	return &Walker[T, W]{edges: edges, cache: make(map[T]*weightedrand.Chooser[T, W])}
+}
+
+// FromMap initializes a Walker over an adjacency map of weighted outgoing
+// edges from each node.
+func FromMap[T comparable, W constraints.Integer](adjacency map[T][]weightedrand.Choice[T, W]) *Walker[T, W] {
+	return New(func(node T) []weightedrand.Choice[T, W] { return adjacency[node] })
+}
+
+// Walk returns a path starting at start and following up to steps weighted
+// random edges. The walk ends early at nodes without outgoing edges of weight
+// >= 1.
+func (w *Walker[T, W]) Walk(start T, steps int) []T {
+	path := make([]T, 1, steps+1)
+	path[0] = start
+	node := start
+	for i := 0; i < steps; i++ {
+		c := w.chooser(node)
+		if c == nil {
+			break
+		}
+		node = c.Pick()
+		path = append(path, node)
+	}
+	return path
+}
+
+// chooser returns the cached Chooser for the edges of node, building it if
+// this is the first visit.
+func (w *Walker[T, W]) chooser(node T) *weightedrand.Chooser[T, W] {
+	w.mu.RLock()
+	c, ok := w.cache[node]
+	w.mu.RUnlock()
+	if ok {
+		return c
+	}
+
+	// Copy, since the Chooser takes ownership of the slice. An error means
+	// there are no edges which can be followed.
+	edges := append([]weightedrand.Choice[T, W](nil), w.edges(node)...)
+	c, err := weightedrand.NewChooser(edges...)
+	if err != nil {
+		c = nil
+	}
+	w.mu.Lock()
+	w.cache[node] = c
+	w.mu.Unlock()
+	return c
+}
@@ -0,0 +1,54 @@
+package walk
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/mroth/weightedrand/v2"
+)
+
+func ExampleFromMap() {
+	graph := map[string][]weightedrand.Choice[string, int]{
+		"home":     {weightedrand.NewChoice("products", 3), weightedrand.NewChoice("about", 0)},
+		"products": {weightedrand.NewChoice("checkout", 1)},
+	}
+	fmt.Println(FromMap(graph).Walk("home", 5))
+	//Output: [home products checkout]
+}
+
+func TestWalker_Walk(t *testing.T) {
+	// a triangle with a heavily weighted edge from a to b
+	graph := map[int][]weightedrand.Choice[int, int]{
+		0: {weightedrand.NewChoice(1, 9), weightedrand.NewChoice(2, 1)},
+		1: {weightedrand.NewChoice(0, 1)},
+		2: {weightedrand.NewChoice(0, 1)},
+	}
+	visits := 0
+	w := New(func(node int) []weightedrand.Choice[int, int] {
+		visits++
+		return graph[node]
+	})
+
+	const steps = 20000
+	path := w.Walk(0, steps)
+	if len(path) != steps+1 || path[0] != 0 {
+		t.Fatalf("Walk() returned %d nodes starting at %d", len(path), path[0])
+	}
+	if visits != len(graph) {
+		t.Errorf("edges called %d times, want once per node (%d)", visits, len(graph))
+	}
+	counts := make(map[int]int)
+	for i := 1; i < len(path); i++ {
+		if path[i-1] == 0 {
+			counts[path[i]]++
+		}
+	}
+	if got := float64(counts[1]) / float64(counts[1]+counts[2]); math.Abs(got-0.9) > 0.02 {
+		t.Errorf("fraction of steps from 0 to 1 = %.3f, want 0.9", got)
+	}
+
+	if got := w.Walk(3, 5); fmt.Sprint(got) != "[3]" {
+		t.Errorf("Walk() from node without edges = %v, want [3]", got)
+	}
+}
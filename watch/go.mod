@@ -0,0 +1,15 @@
+module github.com/mroth/weightedrand/v2/watch
+
+go 1.23
+
+require (
+	github.com/fsnotify/fsnotify v1.10.1
+	github.com/mroth/weightedrand/v2 v2.2.0
+)
+
+require golang.org/x/sys v0.13.0 // indirect
+
+// Builds within this repository use the parent module as checked out. The
+// replace directive is ignored for users of this module, who get the version
+// required above, the first to provide all the APIs used here.
+replace github.com/mroth/weightedrand/v2 => ../
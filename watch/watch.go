@@ -0,0 +1,187 @@
+// Package watch provides a Chooser whose choices are loaded from a weights
+// file, and rebuilt whenever that file changes.
+//
+// It is kept in its own module, so that the fsnotify dependency is only
+// required by users of this package.
+package watch
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/mroth/weightedrand/v2"
+)
+
+// ErrClosed is returned by Reload once the Chooser has been closed.
+var ErrClosed = errors.New("watch: chooser closed")
+
+// integer is satisfied by any type which may be used as a Choice Weight.
+type integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 | ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// A Parser builds a chooser from the contents of a weights file, such as
+// ParseYAML or ParseTOML of the config package.
+type Parser[T any, W integer] func(data []byte) (*weightedrand.Chooser[T, W], error)
+
+// An Option configures a Chooser.
+type Option func(*config)
+
+type config struct {
+	onError func(error)
+}
+
+// WithErrorHandler registers fn to be called whenever reading or parsing the
+// changed file fails and the previous choices are retained, e.g. for logging
+// or alerting. It is called from the goroutine watching the file.
+func WithErrorHandler(fn func(error)) Option {
+	return func(cfg *config) { cfg.onError = fn }
+}
+
+// Chooser is a weighted random chooser backed by a weights file, which is
+// watched for changes until Close is called. It is safe for concurrent usage,
+// including calling Pick while the file is being reloaded.
+type Chooser[T any, W integer] struct {
+	path    string
+	parse   Parser[T, W]
+	cfg     config
+	current atomic.Value // *weightedrand.Chooser[T, W]
+	watcher *fsnotify.Watcher
+	done    chan struct{}
+
+	mu      sync.Mutex // serializes reloads, guards fields below
+	lastErr error
+	closed  bool
+}
+
+// WatchFile initializes a Chooser with the choices parsed from the file at path,
+// and watches it for changes until Close is called. Since there are no previous
+// choices to fall back to, any failure of this initial load is returned as an
+// error.
+//
+// The directory containing the file is watched rather than the file itself, so
+// that replacing it by renaming another over it, as editors and deployment
+// tools commonly do, is also observed. Such atomic replacement is preferable to
+// writing the file in place, which may be observed partway through, so that an
+// error is reported before the complete file is loaded.
+func WatchFile[T any, W integer](path string, parse Parser[T, W], opts ...Option) (*Chooser[T, W], error) {
+	c := &Chooser[T, W]{
+		path:  filepath.Clean(path),
+		parse: parse,
+		done:  make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(&c.cfg)
+	}
+
+	chs, err := c.load()
+	if err != nil {
+		return nil, err
+	}
+	c.current.Store(chs)
+
+	c.watcher, err = fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("watch: %w", err)
+	}
+	if err := c.watcher.Add(filepath.Dir(c.path)); err != nil {
+		c.watcher.Close()
+		return nil, fmt.Errorf("watch: %w", err)
+	}
+	go c.run()
+	return c, nil
+}
+
+// Pick returns a single weighted random item from the most recently loaded
+// choices.
+func (c *Chooser[T, W]) Pick() T {
+	return c.current.Load().(*weightedrand.Chooser[T, W]).Pick()
+}
+
+// Reload reads and parses the file immediately, as happens whenever it changes.
+// On failure the previous choices are retained, and the error is passed to any
+// registered error handler before being returned.
+func (c *Chooser[T, W]) Reload() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return ErrClosed
+	}
+
+	chs, err := c.load()
+	c.lastErr = err
+	if err != nil {
+		if c.cfg.onError != nil {
+			c.cfg.onError(err)
+		}
+		return err
+	}
+	c.current.Store(chs)
+	return nil
+}
+
+// Err returns the error from the most recent reload, or nil if it succeeded.
+func (c *Chooser[T, W]) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lastErr
+}
+
+// Close stops watching the file. The most recently loaded choices continue to
+// be served by Pick.
+func (c *Chooser[T, W]) Close() error {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return nil
+	}
+	c.closed = true
+	c.mu.Unlock()
+
+	err := c.watcher.Close()
+	<-c.done
+	return err
+}
+
+func (c *Chooser[T, W]) load() (*weightedrand.Chooser[T, W], error) {
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		return nil, fmt.Errorf("watch: %w", err)
+	}
+	chs, err := c.parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("watch: parsing %s: %w", c.path, err)
+	}
+	return chs, nil
+}
+
+// run reloads the file whenever an event concerns it, until the watcher is
+// closed. Removal of the file is ignored, since it is usually followed by its
+// replacement, and otherwise the previous choices are retained anyway.
+func (c *Chooser[T, W]) run() {
+	defer close(c.done)
+	for {
+		select {
+		case ev, ok := <-c.watcher.Events:
+			if !ok {
+				return
+			}
+			if filepath.Clean(ev.Name) != c.path || !ev.Has(fsnotify.Write|fsnotify.Create) {
+				continue
+			}
+			_ = c.Reload() // reported by Err and any error handler
+		case err, ok := <-c.watcher.Errors:
+			if !ok {
+				return
+			}
+			if c.cfg.onError != nil {
+				c.cfg.onError(fmt.Errorf("watch: %w", err))
+			}
+		}
+	}
+}
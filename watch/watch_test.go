@@ -0,0 +1,134 @@
+package watch
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mroth/weightedrand/v2"
+)
+
+// parseSpec parses a weights file holding a single ParseChoices spec.
+func parseSpec(data []byte) (*weightedrand.Chooser[string, int], error) {
+	choices, err := weightedrand.ParseChoices[string](strings.TrimSpace(string(data)))
+	if err != nil {
+		return nil, err
+	}
+	return weightedrand.NewChooser(choices...)
+}
+
+func writeFile(t *testing.T, path, data string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// eventually fails the test unless cond becomes true within a few seconds.
+func eventually(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestWatchFile(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := WatchFile(filepath.Join(dir, "missing"), parseSpec); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("WatchFile(missing) error = %v, want %v", err, os.ErrNotExist)
+	}
+
+	path := filepath.Join(dir, "weights")
+	writeFile(t, path, "a:0")
+	if _, err := WatchFile(path, parseSpec); err == nil {
+		t.Error("expected error from initial load with no valid choices")
+	}
+
+	writeFile(t, path, "a:1, b:0")
+	c, err := WatchFile(path, parseSpec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	if got := c.Pick(); got != "a" {
+		t.Errorf("Pick() = %q, want %q", got, "a")
+	}
+}
+
+func TestChooser_watch(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "weights")
+	writeFile(t, path, "a:1")
+
+	var mu sync.Mutex
+	var handled []error
+	c, err := WatchFile(path, parseSpec, WithErrorHandler(func(err error) {
+		mu.Lock()
+		handled = append(handled, err)
+		mu.Unlock()
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	writeFile(t, path, "b:1")
+	eventually(t, "write to be loaded", func() bool { return c.Pick() == "b" })
+
+	// Changes to other files in the directory are ignored.
+	writeFile(t, filepath.Join(dir, "other"), "nonsense")
+
+	writeFile(t, path, "c:nonsense")
+	eventually(t, "invalid write to be reported", func() bool { return c.Err() != nil })
+	if got := c.Pick(); got != "b" {
+		t.Errorf("Pick() after invalid write = %q, want previous choice %q", got, "b")
+	}
+	mu.Lock()
+	if len(handled) == 0 {
+		t.Error("error handler not called for invalid write")
+	}
+	mu.Unlock()
+
+	// Atomic replacement, as by editors and deployment tools.
+	tmp := filepath.Join(dir, "weights.tmp")
+	writeFile(t, tmp, "d:1")
+	if err := os.Rename(tmp, path); err != nil {
+		t.Fatal(err)
+	}
+	eventually(t, "replacement to be loaded", func() bool { return c.Pick() == "d" })
+	if err := c.Err(); err != nil {
+		t.Errorf("Err() after valid reload = %v", err)
+	}
+}
+
+func TestChooser_Close(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "weights")
+	writeFile(t, path, "a:1")
+	c, err := WatchFile(path, parseSpec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Close(); err != nil {
+		t.Errorf("second Close() = %v", err)
+	}
+	if err := c.Reload(); err != ErrClosed {
+		t.Errorf("Reload() after Close = %v, want %v", err, ErrClosed)
+	}
+
+	writeFile(t, path, "b:1")
+	time.Sleep(50 * time.Millisecond)
+	if got := c.Pick(); got != "a" {
+		t.Errorf("Pick() after Close = %q, want %q", got, "a")
+	}
+}
@@ -12,6 +12,8 @@ import (
 	"errors"
 	"math/rand"
 	"sort"
+
+	"github.com/mroth/weightedrand/v2/internal/constraints"
 )
 
 // Choice is a generic wrapper that can be used to add weights for any item.
@@ -20,9 +22,7 @@ type Choice[T any, W integer] struct {
 	Weight W
 }
 
-type integer interface {
-	~int | ~int8 | ~int16 | ~int32 | ~int64 | ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
-}
+type integer = constraints.Integer
 
 // NewChoice creates a new Choice with specified item and weight.
 func NewChoice[T any, W integer](item T, weight W) Choice[T, W] {
@@ -32,18 +32,132 @@ func NewChoice[T any, W integer](item T, weight W) Choice[T, W] {
 // A Chooser caches many possible Choices in a structure designed to improve
 // performance on repeated calls for weighted random selection.
 type Chooser[T any, W integer] struct {
-	data   []Choice[T, W]
-	totals []int
-	max    int
+	data    []Choice[T, W]
+	totals  []int
+	max     int
+	summary Summary[W]
+	rng     intner     // nil if using global rand
+	onPick  func(T, W) // optional observer hook
+	workers int        // workers for parallel construction on Reset
+	eytz    *eytzinger // optional layout of totals, nil if unused
+	guide   *guide     // index of large totals, nil if unused
 }
 
 // NewChooser initializes a new Chooser for picking from the provided choices.
+//
+// The Chooser takes ownership of the choices slice rather than copying it: the
+// slice is sorted by weight in place and retained, so constructing a Chooser
+// only allocates its cumulative weight table alongside it. Callers must not
+// modify the slice afterwards. Use [Chooser.Choices] to obtain a copy.
 func NewChooser[T any, W integer](choices ...Choice[T, W]) (*Chooser[T, W], error) {
-	sort.Slice(choices, func(i, j int) bool {
-		return choices[i].Weight < choices[j].Weight
-	})
+	return newChooser(choices, 1)
+}
+
+// newChooser is NewChooser, with construction of tables large enough to
+// benefit split across the given number of workers.
+func newChooser[T any, W integer](choices []Choice[T, W], workers int) (*Chooser[T, W], error) {
+	c := &Chooser[T, W]{workers: workers}
+	if err := c.build(choices, workers); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// build (re)constructs the Chooser's tables from choices, reusing the backing
+// arrays of any previous tables when they have sufficient capacity. On error,
+// the Chooser must be discarded.
+func (c *Chooser[T, W]) build(choices []Choice[T, W], workers int) error {
+	totals := c.totals[:0]
+	if cap(totals) < len(choices) {
+		totals = make([]int, len(choices))
+	} else {
+		totals = totals[:len(choices)]
+		for i := range totals {
+			totals[i] = 0 // negative weights leave their total unset
+		}
+	}
+
+	var runningTotal int
+	var err error
+	if workers > 1 && len(choices) >= parallelBuildMinLen {
+		parallelSortChoices(choices, workers)
+		runningTotal, err = parallelFillTotals(choices, totals, workers)
+	} else {
+		sortChoices(choices)
+		runningTotal, err = fillTotals(choices, totals)
+	}
+	if err == nil && runningTotal < 1 {
+		err = errNoValidChoices
+	}
+	if err != nil {
+		return err
+	}
+
+	c.data = choices
+	c.totals = totals
+	c.max = runningTotal
+	if c.eytz != nil {
+		c.eytz.build(totals)
+	}
+	if len(totals) < guideMinLen || c.eytz != nil {
+		c.guide = nil
+	} else {
+		if c.guide == nil {
+			c.guide = &guide{}
+		}
+		if !c.guide.build(totals, runningTotal) {
+			c.guide = nil
+		}
+	}
+	c.summary = summarize(choices, runningTotal, c.summary.Histogram)
+	return nil
+}
+
+// Reset rebuilds the Chooser in place to pick from the provided choices, as if
+// it had been newly constructed by NewChooser, while retaining any options it
+// was constructed with. The cumulative weight table of the previous choices is
+// reused when it has sufficient capacity, so periodically refreshing the
+// weights of a Chooser does not allocate in the steady state.
+//
+// As with NewChooser, the Chooser takes ownership of the choices slice. The
+// choices are validated before anything is modified, so if an error is
+// returned the Chooser continues to pick from its previous choices.
+//
+// Reset must not be called concurrently with any other method of the Chooser.
+func (c *Chooser[T, W]) Reset(choices ...Choice[T, W]) error {
+	total, err := sumWeights(choices)
+	if err != nil {
+		return err
+	}
+	if total < 1 {
+		return errNoValidChoices
+	}
+	return c.build(choices, c.workers)
+}
+
+// sumWeights returns the sum of all non-negative weights of choices, or an
+// error if the sum would overflow the totals of a Chooser.
+func sumWeights[T any, W integer](choices []Choice[T, W]) (int, error) {
+	runningTotal := 0
+	for _, c := range choices {
+		if c.Weight < 0 {
+			continue
+		}
+		if uint64(c.Weight) >= maxInt {
+			return 0, errWeightOverflow
+		}
+		weight := int(c.Weight)
+		if (maxInt - runningTotal) <= weight {
+			return 0, errWeightOverflow
+		}
+		runningTotal += weight
+	}
+	return runningTotal, nil
+}
 
-	totals := make([]int, len(choices))
+// fillTotals writes the running total of the sorted choices into totals,
+// returning the sum of all weights.
+func fillTotals[T any, W integer](choices []Choice[T, W], totals []int) (int, error) {
 	runningTotal := 0
 	for i, c := range choices {
 		if c.Weight < 0 {
@@ -52,22 +166,36 @@ func NewChooser[T any, W integer](choices ...Choice[T, W]) (*Chooser[T, W], erro
 
 		// case of single ~uint64 or similar value that exceeds maxInt on its own
 		if uint64(c.Weight) >= maxInt {
-			return nil, errWeightOverflow
+			return 0, errWeightOverflow
 		}
 
 		weight := int(c.Weight) // convert weight to int for internal counter usage
 		if (maxInt - runningTotal) <= weight {
-			return nil, errWeightOverflow
+			return 0, errWeightOverflow
 		}
 		runningTotal += weight
 		totals[i] = runningTotal
 	}
+	return runningTotal, nil
+}
 
-	if runningTotal < 1 {
-		return nil, errNoValidChoices
+// sortChoices sorts choices by ascending weight. Sorting is skipped entirely if
+// the choices are already in order, as is always the case for single choices
+// and frequently the case for two-way splits and pre-sorted tables, which
+// avoids the allocations of sort.Slice.
+func sortChoices[T any, W integer](choices []Choice[T, W]) {
+	for i := 1; i < len(choices); i++ {
+		if choices[i].Weight < choices[i-1].Weight {
+			if len(choices) == 2 {
+				choices[0], choices[1] = choices[1], choices[0]
+				return
+			}
+			sort.Slice(choices, func(i, j int) bool {
+				return choices[i].Weight < choices[j].Weight
+			})
+			return
+		}
 	}
-
-	return &Chooser[T, W]{data: choices, totals: totals, max: runningTotal}, nil
 }
 
 const (
@@ -91,13 +219,43 @@ var (
 
 // Pick returns a single weighted random Choice.Item from the Chooser.
 //
-// Utilizes global rand as the source of randomness. Safe for concurrent usage.
+// Utilizes global rand as the source of randomness, unless the Chooser was
+// constructed WithPrivateRand. Safe for concurrent usage.
 func (c Chooser[T, W]) Pick() T {
-	r := rand.Intn(c.max) + 1
-	i := searchInts(c.totals, r)
-	return c.data[i].Item
+	r := c.intn(c.max) + 1
+	if len(c.totals) == 2 {
+		// Two-way splits are common enough to warrant a single comparison
+		// rather than a search.
+		if r <= c.totals[0] {
+			return c.selected(0)
+		}
+		return c.selected(1)
+	}
+	i := c.search(r)
+	return c.selected(i)
+}
+
+// TryPick is like Pick, but returns an error rather than panicking if the
+// Chooser is in an invalid state, such as the zero value of a Chooser, or one
+// whose internals were corrupted by unsafe concurrent mutation.
+//
+// This is intended for services which must never crash on a bad hot-swap of
+// their Chooser. Validity checks are O(1), so overhead versus Pick is minimal.
+func (c Chooser[T, W]) TryPick() (T, error) {
+	n := len(c.totals)
+	if c.max < 1 || n == 0 || n != len(c.data) || c.totals[n-1] != c.max {
+		var zero T
+		return zero, errInvalidState
+	}
+	r := c.intn(c.max) + 1
+	i := c.search(r)
+	return c.selected(i), nil
 }
 
+// errInvalidState is returned by TryPick for a Chooser which was not created
+// by NewChooser, or has since been corrupted.
+var errInvalidState = errors.New("invalid Chooser state")
+
 // PickSource returns a single weighted random Choice.Item from the Chooser,
 // utilizing the provided *rand.Rand source rs for randomness.
 //
@@ -113,10 +271,54 @@ func (c Chooser[T, W]) Pick() T {
 // manually seed it. Use [Chooser.Pick] instead.
 func (c Chooser[T, W]) PickSource(rs *rand.Rand) T {
 	r := rs.Intn(c.max) + 1
-	i := searchInts(c.totals, r)
+	i := c.search(r)
+	return c.selected(i)
+}
+
+// PickRand returns a single weighted random Choice.Item from the Chooser,
+// utilizing r for randomness rather than the Chooser's own source. Access to r
+// is not serialized, so it is the responsibility of the caller to ensure r is
+// free from thread safety issues, such as by using one RNG per goroutine.
+func (c Chooser[T, W]) PickRand(r RNG) T {
+	i := c.search(int(r.Uint64N(uint64(c.max))) + 1)
+	return c.selected(i)
+}
+
+// Choices returns a copy of the Choices the Chooser picks from, in ascending
+// order of weight, including any which can never be picked.
+func (c Chooser[T, W]) Choices() []Choice[T, W] {
+	return append([]Choice[T, W](nil), c.data...)
+}
+
+// selected returns the item for the choice at index i, notifying any observer.
+func (c Chooser[T, W]) selected(i int) T {
+	if c.onPick != nil {
+		c.onPick(c.data[i].Item, c.data[i].Weight)
+	}
 	return c.data[i].Item
 }
 
+// search returns the index of the first total >= x, using the Eytzinger layout
+// of the totals if the Chooser has one.
+func (c Chooser[T, W]) search(x int) int {
+	if c.eytz != nil {
+		return c.eytz.search(x)
+	}
+	if c.guide != nil {
+		return c.guide.search(c.totals, x)
+	}
+	return search(c.totals, x)
+}
+
+// intn returns a random number in [0,n) from the Chooser's own source of
+// randomness if it has one, otherwise from global rand.
+func (c Chooser[T, W]) intn(n int) int {
+	if c.rng != nil {
+		return c.rng.Intn(n)
+	}
+	return rand.Intn(n)
+}
+
 // The standard library sort.SearchInts() just wraps the generic sort.Search()
 // function, which takes a function closure to determine truthfulness. However,
 // since this function is utilized within a for loop, it cannot currently be
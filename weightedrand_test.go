@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"math"
 	"math/rand"
+	"reflect"
 	"sync"
 	"testing"
 	"time"
@@ -117,6 +118,22 @@ func TestNewChooser(t *testing.T) {
 	}
 }
 
+// NewChooser should retain the provided slice, sorted in place, rather than
+// duplicating it, which matters for very large tables.
+func TestNewChooser_noCopy(t *testing.T) {
+	choices := []Choice[rune, int]{{Item: 'a', Weight: 3}, {Item: 'b', Weight: 1}, {Item: 'c', Weight: 2}}
+	chooser, err := NewChooser(choices...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if &chooser.data[0] != &choices[0] {
+		t.Error("NewChooser copied the choices slice")
+	}
+	if choices[0].Item != 'b' || choices[2].Item != 'a' {
+		t.Errorf("choices not sorted in place: %v", choices)
+	}
+}
+
 // TestChooser_Pick assembles a list of Choices, weighted 0-9, and tests that
 // over the course of 1,000,000 calls to Pick() each choice is returned more
 // often than choices with a lower weight.
@@ -139,6 +156,177 @@ func TestChooser_Pick(t *testing.T) {
 	verifyFrequencyCounts(t, counts, choices)
 }
 
+func TestChooser_TryPick(t *testing.T) {
+	valid, err := NewChooser(NewChoice('a', 1), NewChoice('b', 2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		chooser Chooser[rune, int]
+		wantErr error
+	}{
+		{name: "valid", chooser: *valid, wantErr: nil},
+		{name: "zero value", chooser: Chooser[rune, int]{}, wantErr: errInvalidState},
+		{
+			name:    "mismatched lengths",
+			chooser: Chooser[rune, int]{data: valid.data[:1], totals: valid.totals, max: valid.max},
+			wantErr: errInvalidState,
+		},
+		{
+			name:    "corrupted total",
+			chooser: Chooser[rune, int]{data: valid.data, totals: valid.totals, max: valid.max + 1},
+			wantErr: errInvalidState,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 10; i++ {
+				if _, err := tt.chooser.TryPick(); err != tt.wantErr {
+					t.Fatalf("TryPick() error = %v, wantErr %v", err, tt.wantErr)
+				}
+			}
+		})
+	}
+}
+
+func TestChooser_PickRand(t *testing.T) {
+	chooser, err := NewChooser(NewChoice('a', 1), NewChoice('b', 0), NewChoice('c', 3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Of the 4 possible random values, the first picks a and the rest c.
+	if got := chooser.PickRand(fixedRNG(0)); got != 'a' {
+		t.Errorf("PickRand() = %q, want 'a'", got)
+	}
+	if got := chooser.PickRand(fixedRNG(0.34)); got != 'c' {
+		t.Errorf("PickRand() = %q, want 'c'", got)
+	}
+}
+
+func TestChooser_Choices(t *testing.T) {
+	chooser, err := NewChooser(NewChoice('a', 2), NewChoice('b', 0), NewChoice('c', 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := chooser.Choices()
+	want := []Choice[rune, int]{{'b', 0}, {'c', 1}, {'a', 2}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Choices() = %v, want %v", got, want)
+	}
+	got[0].Weight = 42
+	if chooser.data[0].Weight != 0 {
+		t.Error("modifying result of Choices() modified Chooser")
+	}
+}
+
+func TestChooser_Reset(t *testing.T) {
+	chooser, err := NewChooserWithOptions(
+		[]Choice[rune, int]{{'a', 1}, {'b', 2}, {'c', -1}, {'d', 3}},
+		WithSource(rand.NewSource(1)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	totals := chooser.totals
+
+	if err := chooser.Reset(NewChoice('x', -5), NewChoice('y', 4)); err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{0, 4}; !reflect.DeepEqual(chooser.totals, want) {
+		t.Errorf("totals = %v, want %v", chooser.totals, want)
+	}
+	if &chooser.totals[0] != &totals[0] {
+		t.Error("Reset did not reuse totals backing array")
+	}
+	if chooser.rng == nil {
+		t.Error("Reset discarded WithSource option")
+	}
+	if got := chooser.Pick(); got != 'y' {
+		t.Errorf("Pick() = %q, want 'y'", got)
+	}
+
+	// A failed Reset must leave the previous choices intact.
+	failures := []struct {
+		choices []Choice[rune, int]
+		wantErr error
+	}{
+		{[]Choice[rune, int]{{'z', 0}}, errNoValidChoices},
+		{[]Choice[rune, int]{{'z', maxInt / 2}, {'z', maxInt / 2}, {'z', 2}}, errWeightOverflow},
+	}
+	for _, f := range failures {
+		if err := chooser.Reset(f.choices...); err != f.wantErr {
+			t.Errorf("Reset() error = %v, want %v", err, f.wantErr)
+		}
+		if got, err := chooser.TryPick(); err != nil || got != 'y' {
+			t.Errorf("TryPick() after failed Reset = %q, %v, want 'y'", got, err)
+		}
+	}
+}
+
+func TestChooser_Reset_parallel(t *testing.T) {
+	chooser, err := NewChooserWithOptions(mockChoices(10), WithParallelBuild())
+	if err != nil {
+		t.Fatal(err)
+	}
+	choices := mockChoices(parallelBuildMinLen)
+	want, err := NewChooser(append([]Choice[rune, int](nil), choices...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := chooser.Reset(choices...); err != nil {
+		t.Fatal(err)
+	}
+	if chooser.workers < 1 || chooser.max != want.max || !reflect.DeepEqual(chooser.totals, want.totals) {
+		t.Error("Reset of parallel Chooser differs from NewChooser")
+	}
+}
+
+func TestChooser_Reset_allocs(t *testing.T) {
+	choices := []Choice[rune, int]{{'a', 1}, {'b', 2}, {'c', 3}, {'d', 300}}
+	chooser, err := NewChooser(append([]Choice[rune, int](nil), choices...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	allocs := testing.AllocsPerRun(100, func() {
+		_ = chooser.Reset(choices...)
+	})
+	if allocs != 0 {
+		t.Errorf("Reset allocated %v times per run, want 0", allocs)
+	}
+}
+
+// Two-way splits take a fast path in both NewChooser and Pick, which must
+// handle either order of input weights.
+func TestChooser_Pick_twoChoices(t *testing.T) {
+	for _, cs := range [][]Choice[rune, int]{
+		{{Item: 'a', Weight: 1}, {Item: 'b', Weight: 3}},
+		{{Item: 'b', Weight: 3}, {Item: 'a', Weight: 1}},
+		{{Item: 'a', Weight: 0}, {Item: 'b', Weight: 3}},
+	} {
+		t.Run(fmt.Sprint(cs), func(t *testing.T) {
+			total := float64(cs[0].Weight + cs[1].Weight)
+			want := map[rune]float64{}
+			for _, c := range cs {
+				want[c.Item] = float64(c.Weight) / total
+			}
+			chooser, err := NewChooser(cs...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			counts := make(map[rune]int)
+			for i := 0; i < testIterations/10; i++ {
+				counts[chooser.Pick()]++
+			}
+			for item, p := range want {
+				if got := float64(counts[item]) / (testIterations / 10); math.Abs(got-p) > 0.01 {
+					t.Errorf("%c picked with frequency %v, want %v", item, got, p)
+				}
+			}
+		})
+	}
+}
+
 // TestChooser_PickSource is the same test methodology as TestChooser_Pick, but
 // here we use the PickSource method and access the same chooser concurrently
 // from multiple different goroutines, each providing its own source of
@@ -224,24 +412,25 @@ func BenchmarkNewChooser(b *testing.B) {
 	}
 }
 
-func BenchmarkPick(b *testing.B) {
+func BenchmarkReset(b *testing.B) {
 	for n := BMMinChoices; n <= BMMaxChoices; n *= 10 {
 		b.Run(fmt.Sprintf("size=%s", fmt1eN(n)), func(b *testing.B) {
 			choices := mockChoices(n)
-			chooser, err := NewChooser(choices...)
+			chooser, err := NewChooser(append([]Choice[rune, int](nil), choices...)...)
 			if err != nil {
 				b.Fatal(err)
 			}
+			b.ReportAllocs()
 			b.ResetTimer()
 
 			for i := 0; i < b.N; i++ {
-				_ = chooser.Pick()
+				_ = chooser.Reset(choices...)
 			}
 		})
 	}
 }
 
-func BenchmarkPickParallel(b *testing.B) {
+func BenchmarkPick(b *testing.B) {
 	for n := BMMinChoices; n <= BMMaxChoices; n *= 10 {
 		b.Run(fmt.Sprintf("size=%s", fmt1eN(n)), func(b *testing.B) {
 			choices := mockChoices(n)
@@ -250,15 +439,62 @@ func BenchmarkPickParallel(b *testing.B) {
 				b.Fatal(err)
 			}
 			b.ResetTimer()
-			b.RunParallel(func(pb *testing.PB) {
-				for pb.Next() {
-					_ = chooser.Pick()
+
+			for i := 0; i < b.N; i++ {
+				_ = chooser.Pick()
+			}
+		})
+	}
+}
+
+// BenchmarkPickParallel compares contention between the global rand source and
+// a Chooser constructed WithPrivateRand.
+func BenchmarkPickParallel(b *testing.B) {
+	sources := []struct {
+		name string
+		opts []Option
+	}{
+		{name: "global"},
+		{name: "private", opts: []Option{WithPrivateRand()}},
+	}
+	for n := BMMinChoices; n <= BMMaxChoices; n *= 10 {
+		for _, src := range sources {
+			b.Run(fmt.Sprintf("size=%s/rand=%s", fmt1eN(n), src.name), func(b *testing.B) {
+				choices := mockChoices(n)
+				chooser, err := NewChooserWithOptions(choices, src.opts...)
+				if err != nil {
+					b.Fatal(err)
 				}
+				b.ResetTimer()
+				b.RunParallel(func(pb *testing.PB) {
+					for pb.Next() {
+						_ = chooser.Pick()
+					}
+				})
 			})
-		})
+		}
 	}
 }
 
+func BenchmarkTwoChoices(b *testing.B) {
+	b.Run("NewChooser", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, _ = NewChooser(NewChoice('a', 9), NewChoice('b', 1))
+		}
+	})
+	b.Run("Pick", func(b *testing.B) {
+		chooser, err := NewChooser(NewChoice('a', 9), NewChoice('b', 1))
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			_ = chooser.Pick()
+		}
+	})
+}
+
 func BenchmarkPickSourceParallel(b *testing.B) {
 	for n := BMMinChoices; n <= BMMaxChoices; n *= 10 {
 		b.Run(fmt.Sprintf("size=%s", fmt1eN(n)), func(b *testing.B) {
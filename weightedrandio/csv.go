@@ -0,0 +1,131 @@
+// Package weightedrandio loads weighted choice tables from external formats
+// into a weightedrand.Chooser.
+package weightedrandio
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/mroth/weightedrand/v2"
+	"github.com/mroth/weightedrand/v2/internal/percent"
+)
+
+// An Option configures how LoadCSV parses its input.
+type Option func(*config)
+
+type config struct {
+	comma     rune
+	itemCol   int
+	weightCol int
+	header    bool
+	headerSet bool
+}
+
+// WithComma sets the field delimiter, which defaults to ','.
+func WithComma(r rune) Option {
+	return func(cfg *config) { cfg.comma = r }
+}
+
+// WithColumns sets the zero-based indices of the item and weight columns, which
+// default to 0 and 1.
+func WithColumns(item, weight int) Option {
+	return func(cfg *config) { cfg.itemCol, cfg.weightCol = item, weight }
+}
+
+// WithHeader sets whether the first row is a header to be skipped. By default
+// the first row is treated as a header only if its weight is not an integer.
+func WithHeader(header bool) Option {
+	return func(cfg *config) { cfg.header, cfg.headerSet = header, true }
+}
+
+// ErrNoRows is returned by LoadCSV and NewChooserFromRows if the input
+// contains no rows of choices.
+var ErrNoRows = errors.New("weightedrandio: no rows")
+
+// LoadCSV reads rows of item and weight columns from r and returns a Chooser
+// picking among them. Surrounding whitespace is trimmed from both columns, and
+// weights are parsed as base 10 integers. Errors identify the offending line.
+//
+// Alternatively, weights may all be written as percentages summing to exactly
+// 100%, such as "99.5%" and "0.5%", which are converted to the smallest
+// integer weights in exactly the same proportions.
+func LoadCSV(r io.Reader, opts ...Option) (*weightedrand.Chooser[string, int], error) {
+	cfg := config{comma: ',', itemCol: 0, weightCol: 1}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	cr := csv.NewReader(r)
+	cr.Comma = cfg.comma
+	cr.FieldsPerRecord = -1 // checked per row against the configured columns
+	cr.ReuseRecord = true
+
+	var choices []weightedrand.Choice[string, int]
+	var percents []percent.Percent
+	for row := 0; ; row++ {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("weightedrandio: %w", err)
+		}
+		line, _ := cr.FieldPos(0)
+		if len(record) <= cfg.itemCol || len(record) <= cfg.weightCol {
+			return nil, fmt.Errorf("weightedrandio: line %d: want at least %d columns, got %d",
+				line, maxInt(cfg.itemCol, cfg.weightCol)+1, len(record))
+		}
+
+		item := strings.TrimSpace(record[cfg.itemCol])
+		field := strings.TrimSpace(record[cfg.weightCol])
+		var weight int64
+		var pct percent.Percent
+		if percent.Is(field) {
+			pct, err = percent.Parse(field)
+		} else {
+			weight, err = strconv.ParseInt(field, 10, strconv.IntSize)
+		}
+		if row == 0 && (cfg.header || !cfg.headerSet && err != nil) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("weightedrandio: line %d: invalid weight %q for item %q", line, field, item)
+		}
+		if len(choices) > 0 && percent.Is(field) != (len(percents) > 0) {
+			return nil, fmt.Errorf("weightedrandio: line %d: weights mix percentages and integers", line)
+		}
+		if percent.Is(field) {
+			percents = append(percents, pct)
+		}
+		choices = append(choices, weightedrand.NewChoice(item, int(weight)))
+	}
+	if len(choices) == 0 {
+		return nil, ErrNoRows
+	}
+	if len(percents) > 0 {
+		weights, err := percent.Weights(percents)
+		if err != nil {
+			return nil, fmt.Errorf("weightedrandio: %w", err)
+		}
+		for i, w := range weights {
+			choices[i].Weight = w
+		}
+	}
+
+	c, err := weightedrand.NewChooser(choices...)
+	if err != nil {
+		return nil, fmt.Errorf("weightedrandio: %w", err)
+	}
+	return c, nil
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,72 @@
+package weightedrandio
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func ExampleLoadCSV() {
+	table := `fruit,weight
+cherry,0
+avocado,5
+`
+	chooser, _ := LoadCSV(strings.NewReader(table))
+	fmt.Println(chooser.Pick())
+	//Output: avocado
+}
+
+func TestLoadCSV(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		opts    []Option
+		want    map[string]int // expected weights of pickable choices
+		wantErr string
+	}{
+		{name: "detected header", input: "item,weight\na,1\nb,2\n", want: map[string]int{"a": 1, "b": 2}},
+		{name: "no header", input: "a,1\nb, 2 \n", want: map[string]int{"a": 1, "b": 2}},
+		{name: "forced header", input: "a,1\nb,2\n", opts: []Option{WithHeader(true)}, want: map[string]int{"b": 2}},
+		{name: "columns and comma", input: "1;x;a\n2;y;b\n", opts: []Option{WithComma(';'), WithColumns(2, 0)}, want: map[string]int{"a": 1, "b": 2}},
+		{name: "leading zeros", input: "a,010\n", want: map[string]int{"a": 10}},
+		{name: "percentages", input: "item,share\na,99.5%\nb,0.5%\n", want: map[string]int{"a": 199, "b": 1}},
+		{name: "percentages not summing to 100", input: "a,33.3%\nb,33.3%\nc,33.3%\n", wantErr: "sum is 99.9%"},
+		{name: "mixed percentages", input: "a,50%\nb,50\n", wantErr: "line 2: weights mix percentages and integers"},
+		{name: "bad percentage", input: "a,50%\nb,half%\n", wantErr: `line 2: invalid weight "half%" for item "b"`},
+		{name: "bad weight", input: "item,weight\na,1\nb,lots\n", wantErr: `line 3: invalid weight "lots" for item "b"`},
+		{name: "header not skipped", input: "item,weight\n", opts: []Option{WithHeader(false)}, wantErr: `line 1: invalid weight "weight"`},
+		{name: "too few columns", input: "a,1\nb\n", wantErr: "line 2: want at least 2 columns, got 1"},
+		{name: "empty", input: "", wantErr: ErrNoRows.Error()},
+		{name: "only header", input: "item,weight\n", wantErr: ErrNoRows.Error()},
+		{name: "no positive weights", input: "a,0\n", wantErr: "zero Choices"},
+		{name: "malformed csv", input: "\"a,1\n", wantErr: "extraneous or missing"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := LoadCSV(strings.NewReader(tt.input), tt.opts...)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("LoadCSV() error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := make(map[string]int)
+			for _, choice := range c.Choices() {
+				got[choice.Item] = choice.Weight
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("weights = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadCSV_errNoRows(t *testing.T) {
+	if _, err := LoadCSV(strings.NewReader("")); !errors.Is(err, ErrNoRows) {
+		t.Errorf("LoadCSV() error = %v, want %v", err, ErrNoRows)
+	}
+}
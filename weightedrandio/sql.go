@@ -0,0 +1,62 @@
+package weightedrandio
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/mroth/weightedrand/v2"
+	"github.com/mroth/weightedrand/v2/internal/constraints"
+)
+
+const maxWeight = uint64(^uint(0) >> 1) // max int
+
+// ErrWeightOverflow is returned by NewChooserFromRows as soon as the sum of
+// weights scanned so far exceeds the maximum int value for the platform.
+var ErrWeightOverflow = errors.New("weightedrandio: sum of weights exceeds max int")
+
+// NewChooserFromRows returns a Chooser picking among the choices produced by
+// calling scan for each row of rows, such as those of a query selecting item
+// and weight columns:
+//
+//	rows, err := db.Query("SELECT name, weight FROM backends")
+//	...
+//	defer rows.Close()
+//	c, err := weightedrandio.NewChooserFromRows(rows, func(rows *sql.Rows) (weightedrand.Choice[string, int], error) {
+//		var c weightedrand.Choice[string, int]
+//		err := rows.Scan(&c.Item, &c.Weight)
+//		return c, err
+//	})
+//
+// Rows are consumed as they are streamed, and overflow of the sum of weights
+// is detected as soon as it occurs, without reading the remaining rows. The
+// caller remains responsible for closing rows.
+func NewChooserFromRows[T any, W constraints.Integer](rows *sql.Rows, scan func(*sql.Rows) (weightedrand.Choice[T, W], error)) (*weightedrand.Chooser[T, W], error) {
+	var choices []weightedrand.Choice[T, W]
+	var total uint64
+	for row := 1; rows.Next(); row++ {
+		c, err := scan(rows)
+		if err != nil {
+			return nil, fmt.Errorf("weightedrandio: row %d: %w", row, err)
+		}
+		if c.Weight > 0 {
+			if uint64(c.Weight) >= maxWeight || maxWeight-total <= uint64(c.Weight) {
+				return nil, fmt.Errorf("%w at row %d", ErrWeightOverflow, row)
+			}
+			total += uint64(c.Weight)
+		}
+		choices = append(choices, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("weightedrandio: %w", err)
+	}
+	if len(choices) == 0 {
+		return nil, ErrNoRows
+	}
+
+	c, err := weightedrand.NewChooser(choices...)
+	if err != nil {
+		return nil, fmt.Errorf("weightedrandio: %w", err)
+	}
+	return c, nil
+}
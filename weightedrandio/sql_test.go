@@ -0,0 +1,106 @@
+package weightedrandio
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/mroth/weightedrand/v2"
+)
+
+// fakeConnector is a minimal database/sql driver whose every query returns
+// the same rows of (item, weight) values, followed by err if non-nil.
+type fakeConnector struct {
+	rows [][]driver.Value
+	err  error
+}
+
+func (c fakeConnector) Connect(context.Context) (driver.Conn, error) { return fakeConn(c), nil }
+func (c fakeConnector) Driver() driver.Driver                        { return nil }
+
+type fakeConn fakeConnector
+
+func (c fakeConn) Prepare(string) (driver.Stmt, error) { return fakeStmt(c), nil }
+func (c fakeConn) Close() error                        { return nil }
+func (c fakeConn) Begin() (driver.Tx, error)           { return nil, errors.New("not supported") }
+
+type fakeStmt fakeConnector
+
+func (s fakeStmt) Close() error  { return nil }
+func (s fakeStmt) NumInput() int { return -1 }
+func (s fakeStmt) Exec([]driver.Value) (driver.Result, error) {
+	return nil, errors.New("not supported")
+}
+func (s fakeStmt) Query([]driver.Value) (driver.Rows, error) {
+	return &fakeRows{rows: s.rows, err: s.err}, nil
+}
+
+type fakeRows struct {
+	rows [][]driver.Value
+	err  error
+	read int
+}
+
+func (r *fakeRows) Columns() []string { return []string{"item", "weight"} }
+func (r *fakeRows) Close() error      { return nil }
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.read == len(r.rows) {
+		if r.err != nil {
+			return r.err
+		}
+		return io.EOF
+	}
+	copy(dest, r.rows[r.read])
+	r.read++
+	return nil
+}
+
+func scanChoice(rows *sql.Rows) (weightedrand.Choice[string, int], error) {
+	var c weightedrand.Choice[string, int]
+	err := rows.Scan(&c.Item, &c.Weight)
+	return c, err
+}
+
+func TestNewChooserFromRows(t *testing.T) {
+	tests := []struct {
+		name    string
+		conn    fakeConnector
+		wantErr string
+	}{
+		{name: "valid", conn: fakeConnector{rows: [][]driver.Value{{"a", int64(1)}, {"b", int64(0)}}}},
+		{name: "no rows", wantErr: ErrNoRows.Error()},
+		{name: "scan error", conn: fakeConnector{rows: [][]driver.Value{{"a", int64(1)}, {"b", "lots"}}}, wantErr: "row 2: sql: Scan error"},
+		{name: "overflow", conn: fakeConnector{rows: [][]driver.Value{{"a", int64(maxWeight / 2)}, {"b", int64(maxWeight / 2)}, {"c", int64(1)}}}, wantErr: "exceeds max int at row 3"},
+		{name: "rows error", conn: fakeConnector{rows: [][]driver.Value{{"a", int64(1)}}, err: errors.New("connection reset")}, wantErr: "connection reset"},
+		{name: "no positive weights", conn: fakeConnector{rows: [][]driver.Value{{"a", int64(0)}}}, wantErr: "zero Choices"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := sql.OpenDB(tt.conn)
+			defer db.Close()
+			rows, err := db.Query("SELECT item, weight FROM choices")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer rows.Close()
+
+			c, err := NewChooserFromRows(rows, scanChoice)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("NewChooserFromRows() error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := c.Pick(); got != "a" {
+				t.Errorf("Pick() = %q, want a", got)
+			}
+		})
+	}
+}